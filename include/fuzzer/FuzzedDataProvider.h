@@ -3,6 +3,10 @@
 
 // Modified by Fabian Meumertzheim:
 //   - added preprocessor check for C++11
+// Modified by Code Intelligence:
+//   - log the values returned by the public methods to the file
+//     specified via the CIFUZZ_DATA_PROVIDER_LOG environment variable,
+//     which is used by `cifuzz finding show <name> --decode`
 //
 //===- FuzzedDataProvider.h - Utility header for fuzz targets ---*- C++ -* ===//
 //
@@ -30,6 +34,8 @@
 #include <climits>
 #include <cstddef>
 #include <cstdint>
+#include <cstdio>
+#include <cstdlib>
 #include <cstring>
 #include <initializer_list>
 #include <limits>
@@ -108,8 +114,59 @@ class FuzzedDataProvider {
 
   template <typename TS, typename TU> TS ConvertUnsignedToSigned(TU value);
 
+  // Tracks the nesting of calls, so that only the outermost call of a
+  // public method is logged (e.g. ConsumeBool calls ConsumeIntegral).
+  // If logging is disabled, which is the case while fuzzing, it only
+  // checks the cached log file.
+  class CallGuard {
+   public:
+    explicit CallGuard(FuzzedDataProvider *provider)
+        : provider_(CallLog() != nullptr ? provider : nullptr),
+          outermost_(provider_ != nullptr && provider_->call_depth_++ == 0) {}
+    ~CallGuard() {
+      if (provider_ != nullptr)
+        provider_->call_depth_--;
+    }
+    bool ShouldLog() const { return outermost_; }
+
+   private:
+    FuzzedDataProvider *provider_;
+    bool outermost_;
+  };
+
+  static FILE *CallLog();
+  static FILE *OpenCallLog();
+
+  template <typename T, typename... Args>
+  T LogCall(const CallGuard &guard, T value, const char *method,
+            const char *type, Args... args);
+  void LogPick(const CallGuard &guard, size_t index, size_t size);
+  void WriteCallLog(const std::string &call, const std::string &value);
+
+  template <typename T> static const char *TypeName();
+  static std::string FormatArgs() { return ""; }
+  template <typename A> static std::string FormatArgs(A arg);
+  template <typename A, typename... Rest>
+  static std::string FormatArgs(A arg, Rest... rest);
+  static std::string FormatValue(bool value);
+  template <typename T>
+  static typename std::enable_if<std::is_integral<T>::value, std::string>::type
+  FormatValue(T value);
+  template <typename T>
+  static typename std::enable_if<std::is_floating_point<T>::value,
+                                 std::string>::type
+  FormatValue(T value);
+  template <typename T>
+  static typename std::enable_if<std::is_enum<T>::value, std::string>::type
+  FormatValue(T value);
+  static std::string FormatValue(const std::string &value);
+  template <typename T>
+  static std::string FormatValue(const std::vector<T> &value);
+  static std::string FormatBytes(const void *data, size_t size);
+
   const uint8_t *data_ptr_;
   size_t remaining_bytes_;
+  int call_depth_ = 0;
 };
 
 // Returns a std::vector containing |num_bytes| of input data. If fewer than
@@ -118,8 +175,10 @@ class FuzzedDataProvider {
 // char, unsigned char, uint8_t, etc.
 template <typename T>
 std::vector<T> FuzzedDataProvider::ConsumeBytes(size_t num_bytes) {
-  num_bytes = std::min(num_bytes, remaining_bytes_);
-  return ConsumeBytes<T>(num_bytes, num_bytes);
+  CallGuard guard(this);
+  size_t size = std::min(num_bytes, remaining_bytes_);
+  return LogCall(guard, ConsumeBytes<T>(size, size), "ConsumeBytes",
+                 TypeName<T>(), num_bytes);
 }
 
 // Similar to |ConsumeBytes|, but also appends the terminator value at the end
@@ -129,16 +188,20 @@ std::vector<T> FuzzedDataProvider::ConsumeBytes(size_t num_bytes) {
 template <typename T>
 std::vector<T> FuzzedDataProvider::ConsumeBytesWithTerminator(size_t num_bytes,
                                                               T terminator) {
-  num_bytes = std::min(num_bytes, remaining_bytes_);
-  std::vector<T> result = ConsumeBytes<T>(num_bytes + 1, num_bytes);
+  CallGuard guard(this);
+  size_t size = std::min(num_bytes, remaining_bytes_);
+  std::vector<T> result = ConsumeBytes<T>(size + 1, size);
   result.back() = terminator;
-  return result;
+  return LogCall(guard, std::move(result), "ConsumeBytesWithTerminator",
+                 TypeName<T>(), num_bytes, terminator);
 }
 
 // Returns a std::vector containing all remaining bytes of the input data.
 template <typename T>
 std::vector<T> FuzzedDataProvider::ConsumeRemainingBytes() {
-  return ConsumeBytes<T>(remaining_bytes_);
+  CallGuard guard(this);
+  return LogCall(guard, ConsumeBytes<T>(remaining_bytes_),
+                 "ConsumeRemainingBytes", TypeName<T>());
 }
 
 // Returns a std::string containing |num_bytes| of input data. Using this and
@@ -149,11 +212,13 @@ inline std::string FuzzedDataProvider::ConsumeBytesAsString(size_t num_bytes) {
   static_assert(sizeof(std::string::value_type) == sizeof(uint8_t),
                 "ConsumeBytesAsString cannot convert the data to a string.");
 
-  num_bytes = std::min(num_bytes, remaining_bytes_);
+  CallGuard guard(this);
+  size_t size = std::min(num_bytes, remaining_bytes_);
   std::string result(
-      reinterpret_cast<const std::string::value_type *>(data_ptr_), num_bytes);
-  Advance(num_bytes);
-  return result;
+      reinterpret_cast<const std::string::value_type *>(data_ptr_), size);
+  Advance(size);
+  return LogCall(guard, std::move(result), "ConsumeBytesAsString", nullptr,
+                 num_bytes);
 }
 
 // Returns a std::string of length from 0 to |max_length|. When it runs out of
@@ -168,6 +233,7 @@ FuzzedDataProvider::ConsumeRandomLengthString(size_t max_length) {
   // will be lengthened to include those new characters, resulting in a more
   // stable fuzzer than picking the length of a string independently from
   // picking its contents.
+  CallGuard guard(this);
   std::string result;
 
   // Reserve the anticipated capaticity to prevent several reallocations.
@@ -185,27 +251,35 @@ FuzzedDataProvider::ConsumeRandomLengthString(size_t max_length) {
   }
 
   result.shrink_to_fit();
-  return result;
+  return LogCall(guard, std::move(result), "ConsumeRandomLengthString",
+                 nullptr, max_length);
 }
 
 // Returns a std::string of length from 0 to |remaining_bytes_|.
 inline std::string FuzzedDataProvider::ConsumeRandomLengthString() {
-  return ConsumeRandomLengthString(remaining_bytes_);
+  CallGuard guard(this);
+  return LogCall(guard, ConsumeRandomLengthString(remaining_bytes_),
+                 "ConsumeRandomLengthString", nullptr);
 }
 
 // Returns a std::string containing all remaining bytes of the input data.
 // Prefer using |ConsumeRemainingBytes| unless you actually need a std::string
 // object.
 inline std::string FuzzedDataProvider::ConsumeRemainingBytesAsString() {
-  return ConsumeBytesAsString(remaining_bytes_);
+  CallGuard guard(this);
+  return LogCall(guard, ConsumeBytesAsString(remaining_bytes_),
+                 "ConsumeRemainingBytesAsString", nullptr);
 }
 
 // Returns a number in the range [Type's min, Type's max]. The value might
 // not be uniformly distributed in the given range. If there's no input data
 // left, always returns |min|.
 template <typename T> T FuzzedDataProvider::ConsumeIntegral() {
-  return ConsumeIntegralInRange(std::numeric_limits<T>::min(),
-                                std::numeric_limits<T>::max());
+  CallGuard guard(this);
+  return LogCall(guard,
+                 ConsumeIntegralInRange(std::numeric_limits<T>::min(),
+                                        std::numeric_limits<T>::max()),
+                 "ConsumeIntegral", TypeName<T>());
 }
 
 // Returns a number in the range [min, max] by consuming bytes from the
@@ -220,6 +294,8 @@ T FuzzedDataProvider::ConsumeIntegralInRange(T min, T max) {
   if (min > max)
     abort();
 
+  CallGuard guard(this);
+
   // Use the biggest type possible to hold the range and the result.
   uint64_t range = static_cast<uint64_t>(max) - min;
   uint64_t result = 0;
@@ -242,15 +318,20 @@ T FuzzedDataProvider::ConsumeIntegralInRange(T min, T max) {
   if (range != std::numeric_limits<decltype(range)>::max())
     result = result % (range + 1);
 
-  return static_cast<T>(min + result);
+  return LogCall(guard, static_cast<T>(min + result), "ConsumeIntegralInRange",
+                 TypeName<T>(), min, max);
 }
 
 // Returns a floating point value in the range [Type's lowest, Type's max] by
 // consuming bytes from the input data. If there's no input data left, always
 // returns approximately 0.
 template <typename T> T FuzzedDataProvider::ConsumeFloatingPoint() {
-  return ConsumeFloatingPointInRange<T>(std::numeric_limits<T>::lowest(),
-                                        std::numeric_limits<T>::max());
+  CallGuard guard(this);
+  return LogCall(guard,
+                 ConsumeFloatingPointInRange<T>(
+                     std::numeric_limits<T>::lowest(),
+                     std::numeric_limits<T>::max()),
+                 "ConsumeFloatingPoint", TypeName<T>());
 }
 
 // Returns a floating point value in the given range by consuming bytes from
@@ -261,6 +342,7 @@ T FuzzedDataProvider::ConsumeFloatingPointInRange(T min, T max) {
   if (min > max)
     abort();
 
+  CallGuard guard(this);
   T range = .0;
   T result = min;
   constexpr T zero(.0);
@@ -276,7 +358,8 @@ T FuzzedDataProvider::ConsumeFloatingPointInRange(T min, T max) {
     range = max - min;
   }
 
-  return result + range * ConsumeProbability<T>();
+  return LogCall(guard, result + range * ConsumeProbability<T>(),
+                 "ConsumeFloatingPointInRange", TypeName<T>(), min, max);
 }
 
 // Returns a floating point number in the range [0.0, 1.0]. If there's no
@@ -291,14 +374,17 @@ template <typename T> T FuzzedDataProvider::ConsumeProbability() {
       typename std::conditional<(sizeof(T) <= sizeof(uint32_t)), uint32_t,
                                 uint64_t>::type;
 
+  CallGuard guard(this);
   T result = static_cast<T>(ConsumeIntegral<IntegralType>());
   result /= static_cast<T>(std::numeric_limits<IntegralType>::max());
-  return result;
+  return LogCall(guard, result, "ConsumeProbability", TypeName<T>());
 }
 
 // Reads one byte and returns a bool, or false when no data remains.
 inline bool FuzzedDataProvider::ConsumeBool() {
-  return 1 & ConsumeIntegral<uint8_t>();
+  CallGuard guard(this);
+  return LogCall(guard, static_cast<bool>(1 & ConsumeIntegral<uint8_t>()),
+                 "ConsumeBool", nullptr);
 }
 
 // Returns an enum value. The enum must start at 0 and be contiguous. It must
@@ -306,21 +392,30 @@ inline bool FuzzedDataProvider::ConsumeBool() {
 // enum class Foo { SomeValue, OtherValue, kMaxValue = OtherValue };
 template <typename T> T FuzzedDataProvider::ConsumeEnum() {
   static_assert(std::is_enum<T>::value, "|T| must be an enum type.");
-  return static_cast<T>(
-      ConsumeIntegralInRange<uint32_t>(0, static_cast<uint32_t>(T::kMaxValue)));
+  CallGuard guard(this);
+  return LogCall(guard,
+                 static_cast<T>(ConsumeIntegralInRange<uint32_t>(
+                     0, static_cast<uint32_t>(T::kMaxValue))),
+                 "ConsumeEnum", TypeName<T>());
 }
 
 // Returns a copy of the value selected from the given fixed-size |array|.
 template <typename T, size_t size>
 T FuzzedDataProvider::PickValueInArray(const T (&array)[size]) {
   static_assert(size > 0, "The array must be non empty.");
-  return array[ConsumeIntegralInRange<size_t>(0, size - 1)];
+  CallGuard guard(this);
+  size_t index = ConsumeIntegralInRange<size_t>(0, size - 1);
+  LogPick(guard, index, size);
+  return array[index];
 }
 
 template <typename T, size_t size>
 T FuzzedDataProvider::PickValueInArray(const std::array<T, size> &array) {
   static_assert(size > 0, "The array must be non empty.");
-  return array[ConsumeIntegralInRange<size_t>(0, size - 1)];
+  CallGuard guard(this);
+  size_t index = ConsumeIntegralInRange<size_t>(0, size - 1);
+  LogPick(guard, index, size);
+  return array[index];
 }
 
 template <typename T>
@@ -329,7 +424,10 @@ T FuzzedDataProvider::PickValueInArray(std::initializer_list<const T> list) {
   if (!list.size())
     abort();
 
-  return *(list.begin() + ConsumeIntegralInRange<size_t>(0, list.size() - 1));
+  CallGuard guard(this);
+  size_t index = ConsumeIntegralInRange<size_t>(0, list.size() - 1);
+  LogPick(guard, index, list.size());
+  return *(list.begin() + index);
 }
 
 // Writes |num_bytes| of input data to the given destination pointer. If there
@@ -340,9 +438,13 @@ T FuzzedDataProvider::PickValueInArray(std::initializer_list<const T> list) {
 // fuzzing data.
 inline size_t FuzzedDataProvider::ConsumeData(void *destination,
                                               size_t num_bytes) {
-  num_bytes = std::min(num_bytes, remaining_bytes_);
-  CopyAndAdvance(destination, num_bytes);
-  return num_bytes;
+  CallGuard guard(this);
+  size_t size = std::min(num_bytes, remaining_bytes_);
+  if (guard.ShouldLog())
+    WriteCallLog("ConsumeData(" + FormatArgs(num_bytes) + ")",
+                 FormatBytes(data_ptr_, size));
+  CopyAndAdvance(destination, size);
+  return size;
 }
 
 // Private methods.
@@ -406,4 +508,155 @@ TS FuzzedDataProvider::ConvertUnsignedToSigned(TU value) {
   }
 }
 
+// Methods logging the calls of the public methods.
+
+// Returns the log file, or nullptr if logging is disabled. The
+// environment variable is only checked once per process, because data
+// providers are created for every input while fuzzing.
+inline FILE *FuzzedDataProvider::CallLog() {
+  static FILE *call_log = OpenCallLog();
+  return call_log;
+}
+
+inline FILE *FuzzedDataProvider::OpenCallLog() {
+  const char *path = std::getenv("CIFUZZ_DATA_PROVIDER_LOG");
+  if (path == nullptr || *path == '\0')
+    return nullptr;
+  // The file is shared by all data providers of the process and closed
+  // when the process exits
+  return std::fopen(path, "a");
+}
+
+template <typename T, typename... Args>
+T FuzzedDataProvider::LogCall(const CallGuard &guard, T value,
+                              const char *method, const char *type,
+                              Args... args) {
+  if (guard.ShouldLog()) {
+    std::string call = method;
+    if (type != nullptr)
+      call += std::string("<") + type + ">";
+    call += "(" + FormatArgs(args...) + ")";
+    WriteCallLog(call, FormatValue(value));
+  }
+  return value;
+}
+
+inline void FuzzedDataProvider::LogPick(const CallGuard &guard, size_t index,
+                                        size_t size) {
+  if (guard.ShouldLog())
+    WriteCallLog("PickValueInArray(" + FormatArgs(size) + " values)",
+                 "index " + FormatArgs(index));
+}
+
+inline void FuzzedDataProvider::WriteCallLog(const std::string &call,
+                                             const std::string &value) {
+  std::fprintf(CallLog(), "%s = %s\n", call.c_str(), value.c_str());
+  // Flush, so that the call is logged even if the fuzz test crashes
+  std::fflush(CallLog());
+}
+
+template <typename T> const char *FuzzedDataProvider::TypeName() {
+  return std::is_same<T, bool>::value                 ? "bool"
+         : std::is_same<T, char>::value               ? "char"
+         : std::is_same<T, signed char>::value        ? "signed char"
+         : std::is_same<T, unsigned char>::value      ? "unsigned char"
+         : std::is_same<T, short>::value              ? "short"
+         : std::is_same<T, unsigned short>::value     ? "unsigned short"
+         : std::is_same<T, int>::value                ? "int"
+         : std::is_same<T, unsigned int>::value       ? "unsigned int"
+         : std::is_same<T, long>::value               ? "long"
+         : std::is_same<T, unsigned long>::value      ? "unsigned long"
+         : std::is_same<T, long long>::value          ? "long long"
+         : std::is_same<T, unsigned long long>::value ? "unsigned long long"
+         : std::is_same<T, float>::value              ? "float"
+         : std::is_same<T, double>::value             ? "double"
+         : std::is_same<T, long double>::value        ? "long double"
+         : std::is_enum<T>::value                     ? "enum"
+                                                      : "T";
+}
+
+template <typename A> std::string FuzzedDataProvider::FormatArgs(A arg) {
+  return FormatValue(arg);
+}
+
+template <typename A, typename... Rest>
+std::string FuzzedDataProvider::FormatArgs(A arg, Rest... rest) {
+  return FormatValue(arg) + ", " + FormatArgs(rest...);
+}
+
+inline std::string FuzzedDataProvider::FormatValue(bool value) {
+  return value ? "true" : "false";
+}
+
+template <typename T>
+typename std::enable_if<std::is_integral<T>::value, std::string>::type
+FuzzedDataProvider::FormatValue(T value) {
+  if (std::numeric_limits<T>::is_signed)
+    return std::to_string(static_cast<long long>(value));
+  return std::to_string(static_cast<unsigned long long>(value));
+}
+
+template <typename T>
+typename std::enable_if<std::is_floating_point<T>::value, std::string>::type
+FuzzedDataProvider::FormatValue(T value) {
+  char buffer[64];
+  std::snprintf(buffer, sizeof(buffer), "%.*Lg",
+                std::numeric_limits<T>::max_digits10,
+                static_cast<long double>(value));
+  return buffer;
+}
+
+template <typename T>
+typename std::enable_if<std::is_enum<T>::value, std::string>::type
+FuzzedDataProvider::FormatValue(T value) {
+  return FormatValue(static_cast<typename std::underlying_type<T>::type>(value));
+}
+
+inline std::string FuzzedDataProvider::FormatValue(const std::string &value) {
+  return FormatBytes(value.data(), value.size());
+}
+
+template <typename T>
+std::string FuzzedDataProvider::FormatValue(const std::vector<T> &value) {
+  return FormatBytes(value.data(), value.size() * sizeof(T));
+}
+
+// Formats the bytes as a quoted string with C escape sequences, so that
+// each call is logged on a single line.
+inline std::string FuzzedDataProvider::FormatBytes(const void *data,
+                                                   size_t size) {
+  static const char kHexDigits[] = "0123456789abcdef";
+  const uint8_t *bytes = static_cast<const uint8_t *>(data);
+  std::string result = "\"";
+  for (size_t i = 0; i < size; ++i) {
+    uint8_t byte = bytes[i];
+    switch (byte) {
+    case '"':
+      result += "\\\"";
+      break;
+    case '\\':
+      result += "\\\\";
+      break;
+    case '\n':
+      result += "\\n";
+      break;
+    case '\r':
+      result += "\\r";
+      break;
+    case '\t':
+      result += "\\t";
+      break;
+    default:
+      if (byte >= 0x20 && byte < 0x7f) {
+        result += static_cast<char>(byte);
+      } else {
+        result += "\\x";
+        result += kHexDigits[byte >> 4];
+        result += kHexDigits[byte & 0xf];
+      }
+    }
+  }
+  return result + "\"";
+}
+
 #endif // LLVM_FUZZER_FUZZED_DATA_PROVIDER_H_
@@ -0,0 +1,204 @@
+package fuzztest
+
+import (
+	"io"
+	"os/exec"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/viper"
+
+	"code-intelligence.com/cifuzz/internal/build"
+	"code-intelligence.com/cifuzz/internal/build/bazel"
+	"code-intelligence.com/cifuzz/internal/build/cmake"
+	"code-intelligence.com/cifuzz/internal/build/gradle"
+	"code-intelligence.com/cifuzz/internal/build/maven"
+	"code-intelligence.com/cifuzz/internal/build/other"
+	"code-intelligence.com/cifuzz/internal/config"
+	"code-intelligence.com/cifuzz/pkg/log"
+)
+
+// BuildOptions configures how a fuzz test is built
+type BuildOptions struct {
+	BuildSystem  string
+	BuildCommand string
+	CleanCommand string
+	NumBuildJobs uint
+	ProjectDir   string
+	// The name of the fuzz test. When building with Bazel, this is
+	// updated to the name of the target which is actually run.
+	FuzzTest string
+	// Additional arguments passed to the build system
+	Args []string
+	// A temporary directory which the builder can use to create
+	// temporary files
+	TempDir   string
+	BuildOnly bool
+	Stdout    io.Writer
+	Stderr    io.Writer
+}
+
+// Build builds the fuzz test with the project's build system.
+// If opts.BuildOnly is set, the result can be nil for build systems
+// which don't produce all of its information when only building.
+func Build(opts *BuildOptions) (*build.Result, error) {
+	var err error
+
+	// TODO: Do not hardcode these values.
+	sanitizers := []string{"address", "undefined"}
+
+	switch opts.BuildSystem {
+	case config.BuildSystemBazel:
+		// The cc_fuzz_test rule defines multiple bazel targets: If the
+		// name is "foo", it defines the targets "foo", "foo_bin", and
+		// others. We need to run the "foo_bin" target but want to
+		// allow users to specify either "foo" or "foo_bin", so we check
+		// if the fuzz test name appended with "_bin" is a valid target
+		// and use that in that case
+		cmd := exec.Command("bazel", "query", opts.FuzzTest+"_bin")
+		err = cmd.Run()
+		if err == nil {
+			opts.FuzzTest += "_bin"
+		}
+
+		var builder *bazel.Builder
+		builder, err = bazel.NewBuilder(&bazel.BuilderOptions{
+			ProjectDir: opts.ProjectDir,
+			Args:       opts.Args,
+			NumJobs:    opts.NumBuildJobs,
+			Stdout:     opts.Stdout,
+			Stderr:     opts.Stderr,
+			TempDir:    opts.TempDir,
+			Verbose:    viper.GetBool("verbose"),
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		var buildResults []*build.Result
+		buildResults, err = builder.BuildForRun([]string{opts.FuzzTest})
+		if err != nil {
+			return nil, err
+		}
+		return buildResults[0], nil
+
+	case config.BuildSystemCMake:
+		var builder *cmake.Builder
+		builder, err = cmake.NewBuilder(&cmake.BuilderOptions{
+			ProjectDir: opts.ProjectDir,
+			Args:       opts.Args,
+			Sanitizers: sanitizers,
+			Parallel: cmake.ParallelOptions{
+				Enabled: viper.IsSet("build-jobs"),
+				NumJobs: opts.NumBuildJobs,
+			},
+			Stdout:    opts.Stdout,
+			Stderr:    opts.Stderr,
+			BuildOnly: opts.BuildOnly,
+		})
+		if err != nil {
+			return nil, err
+		}
+		err = builder.Configure()
+		if err != nil {
+			return nil, err
+		}
+
+		var buildResults []*build.Result
+		buildResults, err = builder.Build([]string{opts.FuzzTest})
+		if err != nil {
+			return nil, err
+		}
+
+		if opts.BuildOnly {
+			return nil, nil
+		}
+		return buildResults[0], nil
+
+	case config.BuildSystemMaven:
+		if len(opts.Args) > 0 {
+			log.Warnf("Passing additional arguments is not supported for Maven.\n"+
+				"These arguments are ignored: %s", strings.Join(opts.Args, " "))
+		}
+
+		var builder *maven.Builder
+		builder, err = maven.NewBuilder(&maven.BuilderOptions{
+			ProjectDir: opts.ProjectDir,
+			Parallel: maven.ParallelOptions{
+				Enabled: viper.IsSet("build-jobs"),
+				NumJobs: opts.NumBuildJobs,
+			},
+			Stdout: opts.Stdout,
+			Stderr: opts.Stderr,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		var buildResult *build.Result
+		buildResult, err = builder.Build(opts.FuzzTest)
+		if err != nil {
+			return nil, err
+		}
+		return buildResult, err
+
+	case config.BuildSystemGradle:
+		if len(opts.Args) > 0 {
+			log.Warnf("Passing additional arguments is not supported for Gradle.\n"+
+				"These arguments are ignored: %s", strings.Join(opts.Args, " "))
+		}
+
+		var builder *gradle.Builder
+		builder, err = gradle.NewBuilder(&gradle.BuilderOptions{
+			ProjectDir: opts.ProjectDir,
+			Parallel: gradle.ParallelOptions{
+				Enabled: viper.IsSet("build-jobs"),
+				NumJobs: opts.NumBuildJobs,
+			},
+			Stdout: opts.Stdout,
+			Stderr: opts.Stderr,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		var buildResult *build.Result
+		buildResult, err = builder.Build(opts.FuzzTest)
+		if err != nil {
+			return nil, err
+		}
+		return buildResult, err
+	case config.BuildSystemOther:
+		if len(opts.Args) > 0 {
+			log.Warnf("Passing additional arguments is not supported for build system type \"other\".\n"+
+				"These arguments are ignored: %s", strings.Join(opts.Args, " "))
+		}
+
+		var builder *other.Builder
+		builder, err = other.NewBuilder(&other.BuilderOptions{
+			ProjectDir:   opts.ProjectDir,
+			BuildCommand: opts.BuildCommand,
+			CleanCommand: opts.CleanCommand,
+			Sanitizers:   sanitizers,
+			Stdout:       opts.Stdout,
+			Stderr:       opts.Stderr,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		err := builder.Clean()
+		if err != nil {
+			return nil, err
+		}
+
+		var buildResult *build.Result
+		buildResult, err = builder.Build(opts.FuzzTest)
+		if err != nil {
+			return nil, err
+		}
+		return buildResult, nil
+	}
+
+	return nil, errors.Errorf("Unsupported build system \"%s\"", opts.BuildSystem)
+}
@@ -365,6 +365,12 @@ func (i *CIFuzzBuilder) CopyFiles() error {
 		return errors.WithStack(err)
 	}
 
+	// Copy Java source files to the src directory
+	err = copy.Copy(filepath.Join(i.projectDir, "tools", "jazzer-hooks"), i.srcDir(), opts)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
 	return nil
 }
 
@@ -161,7 +161,7 @@ func (b *Bundler) getCodeRevision() *archive.CodeRevision {
 	var gitBranch string
 
 	if b.opts.Commit == "" {
-		gitCommit, err = vcs.GitCommit()
+		gitCommit, err = vcs.GitCommit(b.opts.ProjectDir)
 		if err != nil {
 			log.Debugf("failed to get Git commit: %+v", err)
 			return nil
@@ -171,7 +171,7 @@ func (b *Bundler) getCodeRevision() *archive.CodeRevision {
 	}
 
 	if b.opts.Branch == "" {
-		gitBranch, err = vcs.GitBranch()
+		gitBranch, err = vcs.GitBranch(b.opts.ProjectDir)
 		if err != nil {
 			log.Debugf("failed to get Git branch: %+v", err)
 			return nil
@@ -180,7 +180,7 @@ func (b *Bundler) getCodeRevision() *archive.CodeRevision {
 		gitBranch = b.opts.Branch
 	}
 
-	if vcs.GitIsDirty() {
+	if vcs.GitIsDirty(b.opts.ProjectDir) {
 		log.Warnf("The Git repository has uncommitted changes. Archive metadata may be inaccurate.")
 	}
 
@@ -12,11 +12,14 @@ import (
 
 	"code-intelligence.com/cifuzz/internal/build"
 	"code-intelligence.com/cifuzz/internal/build/bazel"
+	"code-intelligence.com/cifuzz/internal/cmd/coverage/converter"
 	"code-intelligence.com/cifuzz/internal/cmd/coverage/summary"
 	"code-intelligence.com/cifuzz/internal/cmdutils"
+	"code-intelligence.com/cifuzz/internal/coverage"
 	"code-intelligence.com/cifuzz/pkg/log"
 	"code-intelligence.com/cifuzz/pkg/runfiles"
 	"code-intelligence.com/cifuzz/util/envutil"
+	"code-intelligence.com/cifuzz/util/stringutil"
 )
 
 type CoverageGenerator struct {
@@ -147,6 +150,26 @@ func (cov *CoverageGenerator) GenerateCoverageReport() (string, error) {
 		return cov.OutputPath, nil
 	}
 
+	if stringutil.Contains(coverage.ConvertedFormats, cov.OutputFormat) {
+		if cov.OutputPath == "" {
+			path, err := bazel.PathFromLabel(cov.FuzzTest, commonFlags)
+			if err != nil {
+				return "", err
+			}
+			name := strings.ReplaceAll(path, "/", "-")
+			cov.OutputPath = name + "." + converter.FileName(cov.OutputFormat)
+		}
+		report, err := converter.ParseLcov(strings.NewReader(string(lcovReportContent)), cov.ProjectDir)
+		if err != nil {
+			return "", err
+		}
+		err = report.WriteFile(cov.OutputPath, cov.OutputFormat, cov.ProjectDir)
+		if err != nil {
+			return "", err
+		}
+		return cov.OutputPath, nil
+	}
+
 	// If no output path was specified, create the coverage report in a
 	// temporary directory
 	if cov.OutputPath == "" {
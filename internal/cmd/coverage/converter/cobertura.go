@@ -0,0 +1,165 @@
+package converter
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const coberturaDoctype = `<!DOCTYPE coverage SYSTEM "http://cobertura.sourceforge.net/xml/coverage-04.dtd">`
+
+// now is used for the timestamp of Cobertura reports and can be
+// replaced in tests
+var now = time.Now
+
+type coberturaCoverage struct {
+	XMLName         xml.Name            `xml:"coverage"`
+	LineRate        string              `xml:"line-rate,attr"`
+	BranchRate      string              `xml:"branch-rate,attr"`
+	LinesCovered    int                 `xml:"lines-covered,attr"`
+	LinesValid      int                 `xml:"lines-valid,attr"`
+	BranchesCovered int                 `xml:"branches-covered,attr"`
+	BranchesValid   int                 `xml:"branches-valid,attr"`
+	Complexity      string              `xml:"complexity,attr"`
+	Version         string              `xml:"version,attr"`
+	Timestamp       int64               `xml:"timestamp,attr"`
+	Sources         []string            `xml:"sources>source"`
+	Packages        []*coberturaPackage `xml:"packages>package"`
+}
+
+type coberturaPackage struct {
+	Name       string            `xml:"name,attr"`
+	LineRate   string            `xml:"line-rate,attr"`
+	BranchRate string            `xml:"branch-rate,attr"`
+	Complexity string            `xml:"complexity,attr"`
+	Classes    []*coberturaClass `xml:"classes>class"`
+}
+
+type coberturaClass struct {
+	Name       string           `xml:"name,attr"`
+	Filename   string           `xml:"filename,attr"`
+	LineRate   string           `xml:"line-rate,attr"`
+	BranchRate string           `xml:"branch-rate,attr"`
+	Complexity string           `xml:"complexity,attr"`
+	Methods    coberturaMethods `xml:"methods"`
+	Lines      coberturaLines   `xml:"lines"`
+}
+
+// The methods and lines elements are required by the DTD even if they
+// are empty, so we don't use the "a>b" syntax for them
+type coberturaMethods struct {
+	Methods []*coberturaMethod `xml:"method"`
+}
+
+type coberturaMethod struct {
+	Name       string         `xml:"name,attr"`
+	Signature  string         `xml:"signature,attr"`
+	LineRate   string         `xml:"line-rate,attr"`
+	BranchRate string         `xml:"branch-rate,attr"`
+	Complexity string         `xml:"complexity,attr"`
+	Lines      coberturaLines `xml:"lines"`
+}
+
+type coberturaLines struct {
+	Lines []*coberturaLine `xml:"line"`
+}
+
+type coberturaLine struct {
+	Number            int    `xml:"number,attr"`
+	Hits              int    `xml:"hits,attr"`
+	Branch            bool   `xml:"branch,attr"`
+	ConditionCoverage string `xml:"condition-coverage,attr,omitempty"`
+}
+
+// WriteCobertura writes the report in the Cobertura XML format, with
+// one package per Java package or C/C++ source directory
+func (r *Report) WriteCobertura(w io.Writer) error {
+	counts := r.Counts()
+	coverage := &coberturaCoverage{
+		LineRate:        formatRate(counts.LineRate()),
+		BranchRate:      formatRate(counts.BranchRate()),
+		LinesCovered:    counts.LinesHit,
+		LinesValid:      counts.LinesFound,
+		BranchesCovered: counts.BranchesHit,
+		BranchesValid:   counts.BranchesFound,
+		Complexity:      "0",
+		Version:         "cifuzz",
+		Timestamp:       now().UnixMilli(),
+		Sources:         r.Sources,
+	}
+
+	packages := make(map[string][]*File)
+	var packageNames []string
+	for _, f := range r.Files {
+		if _, ok := packages[f.Package]; !ok {
+			packageNames = append(packageNames, f.Package)
+		}
+		packages[f.Package] = append(packages[f.Package], f)
+	}
+	sort.Strings(packageNames)
+
+	for _, name := range packageNames {
+		packageCounts := &Counts{}
+		p := &coberturaPackage{Name: name, Complexity: "0"}
+		for _, f := range packages[name] {
+			packageCounts.add(f.Lines())
+			for _, c := range f.Classes {
+				p.Classes = append(p.Classes, coberturaClassFromClass(c, f.Path))
+			}
+		}
+		p.LineRate = formatRate(packageCounts.LineRate())
+		p.BranchRate = formatRate(packageCounts.BranchRate())
+		coverage.Packages = append(coverage.Packages, p)
+	}
+
+	out, err := xml.MarshalIndent(coverage, "", "  ")
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	_, err = fmt.Fprintf(w, "%s%s\n%s\n", xml.Header, coberturaDoctype, out)
+	return errors.WithStack(err)
+}
+
+func coberturaClassFromClass(c *Class, filename string) *coberturaClass {
+	counts := c.Counts()
+	class := &coberturaClass{
+		Name:       c.Name,
+		Filename:   filename,
+		LineRate:   formatRate(counts.LineRate()),
+		BranchRate: formatRate(counts.BranchRate()),
+		Complexity: "0",
+	}
+	for _, m := range c.Methods {
+		methodCounts := &Counts{}
+		methodCounts.add([]*Line{m.Line})
+		class.Methods.Methods = append(class.Methods.Methods, &coberturaMethod{
+			Name:       m.Name,
+			Signature:  m.Signature,
+			LineRate:   formatRate(methodCounts.LineRate()),
+			BranchRate: formatRate(1),
+			Complexity: "0",
+			Lines: coberturaLines{
+				Lines: []*coberturaLine{{Number: m.Line.Number, Hits: m.Line.Hits}},
+			},
+		})
+	}
+	for _, l := range c.Lines {
+		line := &coberturaLine{Number: l.Number, Hits: l.Hits}
+		if l.BranchesFound > 0 {
+			line.Branch = true
+			line.ConditionCoverage = fmt.Sprintf("%d%% (%d/%d)",
+				l.BranchesHit*100/l.BranchesFound, l.BranchesHit, l.BranchesFound)
+		}
+		class.Lines.Lines = append(class.Lines.Lines, line)
+	}
+	return class
+}
+
+func formatRate(rate float64) string {
+	return strconv.FormatFloat(rate, 'f', 4, 64)
+}
@@ -0,0 +1,70 @@
+package converter
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteCobertura(t *testing.T) {
+	now = func() time.Time { return time.UnixMilli(1700000000000) }
+	defer func() { now = time.Now }()
+
+	report, err := ParseJacocoXML(strings.NewReader(jacocoReport), []string{"/project/src/main/java"})
+	require.NoError(t, err)
+
+	out := &bytes.Buffer{}
+	err = report.WriteCobertura(out)
+	require.NoError(t, err)
+
+	expected := `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE coverage SYSTEM "http://cobertura.sourceforge.net/xml/coverage-04.dtd">
+<coverage line-rate="0.7500" branch-rate="0.7500" lines-covered="3" lines-valid="4" branches-covered="3" branches-valid="4" complexity="0" version="cifuzz" timestamp="1700000000000">
+  <sources>
+    <source>/project/src/main/java</source>
+  </sources>
+  <packages>
+    <package name="com.example" line-rate="0.7500" branch-rate="0.7500" complexity="0">
+      <classes>
+        <class name="com.example.ExploreMe" filename="com/example/ExploreMe.java" line-rate="1.0000" branch-rate="0.7500" complexity="0">
+          <methods>
+            <method name="&lt;init&gt;" signature="(I)V" line-rate="1.0000" branch-rate="1.0000" complexity="0">
+              <lines>
+                <line number="5" hits="1" branch="false"></line>
+              </lines>
+            </method>
+            <method name="exploreMe" signature="(Ljava/lang/String;)V" line-rate="1.0000" branch-rate="1.0000" complexity="0">
+              <lines>
+                <line number="9" hits="1" branch="false"></line>
+              </lines>
+            </method>
+          </methods>
+          <lines>
+            <line number="3" hits="1" branch="false"></line>
+            <line number="6" hits="1" branch="false"></line>
+            <line number="10" hits="1" branch="true" condition-coverage="75% (3/4)"></line>
+          </lines>
+        </class>
+        <class name="com.example.ExploreMe$Helper" filename="com/example/ExploreMe.java" line-rate="0.0000" branch-rate="1.0000" complexity="0">
+          <methods>
+            <method name="help" signature="()V" line-rate="0.0000" branch-rate="1.0000" complexity="0">
+              <lines>
+                <line number="20" hits="0" branch="false"></line>
+              </lines>
+            </method>
+          </methods>
+          <lines>
+            <line number="21" hits="0" branch="false"></line>
+          </lines>
+        </class>
+      </classes>
+    </package>
+  </packages>
+</coverage>
+`
+	assert.Equal(t, expected, out.String())
+}
@@ -0,0 +1,39 @@
+package converter
+
+import (
+	"os"
+
+	"github.com/pkg/errors"
+
+	"code-intelligence.com/cifuzz/internal/coverage"
+)
+
+// WriteFile writes the report to the file at the given path in one of
+// the coverage.ConvertedFormats. For formats which require paths
+// relative to the project, they are made relative to projectDir.
+func (r *Report) WriteFile(path, format, projectDir string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defer f.Close()
+
+	switch format {
+	case coverage.FormatCobertura:
+		err = r.WriteCobertura(f)
+	case coverage.FormatSonarQube:
+		err = r.WriteSonarQube(f, projectDir)
+	default:
+		err = errors.Errorf("Unsupported output format %q", format)
+	}
+	if err != nil {
+		return err
+	}
+	return errors.WithStack(f.Close())
+}
+
+// FileName returns the default name of a report in one of the
+// coverage.ConvertedFormats
+func FileName(format string) string {
+	return format + ".xml"
+}
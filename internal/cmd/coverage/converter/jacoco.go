@@ -0,0 +1,185 @@
+package converter
+
+import (
+	"encoding/xml"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"code-intelligence.com/cifuzz/util/fileutil"
+)
+
+type jacocoCounter struct {
+	Type    string `xml:"type,attr"`
+	Missed  int    `xml:"missed,attr"`
+	Covered int    `xml:"covered,attr"`
+}
+
+type jacocoMethod struct {
+	Name     string          `xml:"name,attr"`
+	Desc     string          `xml:"desc,attr"`
+	Line     int             `xml:"line,attr"`
+	Counters []jacocoCounter `xml:"counter"`
+}
+
+type jacocoClass struct {
+	Name           string          `xml:"name,attr"`
+	SourceFilename string          `xml:"sourcefilename,attr"`
+	Methods        []*jacocoMethod `xml:"method"`
+}
+
+type jacocoLine struct {
+	Nr int `xml:"nr,attr"`
+	Mi int `xml:"mi,attr"`
+	Ci int `xml:"ci,attr"`
+	Mb int `xml:"mb,attr"`
+	Cb int `xml:"cb,attr"`
+}
+
+type jacocoSourcefile struct {
+	Name  string        `xml:"name,attr"`
+	Lines []*jacocoLine `xml:"line"`
+}
+
+type jacocoPackage struct {
+	Name        string              `xml:"name,attr"`
+	Classes     []*jacocoClass      `xml:"class"`
+	Sourcefiles []*jacocoSourcefile `xml:"sourcefile"`
+}
+
+// Reports of multi-module projects group the packages by module
+type jacocoGroup struct {
+	Groups   []*jacocoGroup   `xml:"group"`
+	Packages []*jacocoPackage `xml:"package"`
+}
+
+func (g *jacocoGroup) packages() []*jacocoPackage {
+	packages := g.Packages
+	for _, group := range g.Groups {
+		packages = append(packages, group.packages()...)
+	}
+	return packages
+}
+
+// ParseJacocoXML parses the line, branch and method coverage of a
+// JaCoCo XML report. The lines of a source file are assigned to the
+// class which contains the closest method starting before the line.
+// The paths of the files are relative to the source directories.
+func ParseJacocoXML(in io.Reader, sourceDirs []string) (*Report, error) {
+	report := &Report{}
+	for _, dir := range sourceDirs {
+		report.Sources = append(report.Sources, filepath.ToSlash(dir))
+	}
+
+	jacocoReport := &jacocoGroup{}
+	err := xml.NewDecoder(in).Decode(jacocoReport)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse JaCoCo XML report")
+	}
+
+	for _, p := range jacocoReport.packages() {
+		packageName := strings.ReplaceAll(p.Name, "/", ".")
+		for _, sourcefile := range p.Sourcefiles {
+			file := &File{
+				Path:    sourcefile.Name,
+				Package: packageName,
+			}
+			if p.Name != "" {
+				file.Path = p.Name + "/" + sourcefile.Name
+			}
+			file.Classes = jacocoClasses(p, sourcefile)
+			report.Files = append(report.Files, file)
+		}
+	}
+
+	return report, nil
+}
+
+func jacocoClasses(p *jacocoPackage, sourcefile *jacocoSourcefile) []*Class {
+	type methodStart struct {
+		line  int
+		class *Class
+	}
+	var classes []*Class
+	var starts []methodStart
+	for _, c := range p.Classes {
+		if c.SourceFilename != sourcefile.Name {
+			continue
+		}
+		class := &Class{Name: strings.ReplaceAll(c.Name, "/", ".")}
+		for _, m := range c.Methods {
+			method := &Method{
+				Name:      m.Name,
+				Signature: m.Desc,
+				Line:      &Line{Number: m.Line},
+			}
+			for _, counter := range m.Counters {
+				if counter.Type == "METHOD" && counter.Covered > 0 {
+					method.Line.Hits = 1
+				}
+			}
+			class.Methods = append(class.Methods, method)
+			if m.Line > 0 {
+				starts = append(starts, methodStart{line: m.Line, class: class})
+			}
+		}
+		classes = append(classes, class)
+	}
+	if len(classes) == 0 {
+		// The source file doesn't contain any classes with code, e.g.
+		// because it only contains an interface
+		name := strings.TrimSuffix(sourcefile.Name, filepath.Ext(sourcefile.Name))
+		if p.Name != "" {
+			name = strings.ReplaceAll(p.Name, "/", ".") + "." + name
+		}
+		classes = append(classes, &Class{Name: name})
+	}
+	sort.SliceStable(starts, func(i, j int) bool {
+		return starts[i].line < starts[j].line
+	})
+
+	for _, l := range sourcefile.Lines {
+		// Lines before the first method, like field initializers,
+		// belong to the first class
+		class := classes[0]
+		for _, start := range starts {
+			if start.line > l.Nr {
+				break
+			}
+			class = start.class
+		}
+		line := &Line{
+			Number:        l.Nr,
+			BranchesFound: l.Mb + l.Cb,
+			BranchesHit:   l.Cb,
+		}
+		// JaCoCo doesn't record how often a line was executed, only
+		// the number of covered instructions
+		if l.Ci > 0 {
+			line.Hits = 1
+		}
+		class.Lines = append(class.Lines, line)
+	}
+	return classes
+}
+
+// JavaSourceDirs returns the directories of the main sources of a
+// Maven or Gradle project, which the paths in a JaCoCo report are
+// relative to
+func JavaSourceDirs(projectDir string) []string {
+	var dirs []string
+	for _, lang := range []string{"java", "kotlin"} {
+		dir := filepath.Join(projectDir, "src", "main", lang)
+		exists, err := fileutil.Exists(dir)
+		if err == nil && exists {
+			dirs = append(dirs, dir)
+		}
+	}
+	if len(dirs) == 0 {
+		dirs = append(dirs, filepath.Join(projectDir, "src", "main", "java"))
+	}
+	return dirs
+}
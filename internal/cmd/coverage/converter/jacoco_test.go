@@ -0,0 +1,69 @@
+package converter
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const jacocoReport = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<!DOCTYPE report PUBLIC "-//JACOCO//DTD Report 1.1//EN" "report.dtd">
+<report name="maven-example">
+  <package name="com/example">
+    <class name="com/example/ExploreMe" sourcefilename="ExploreMe.java">
+      <method name="&lt;init&gt;" desc="(I)V" line="5">
+        <counter type="METHOD" missed="0" covered="1"/>
+      </method>
+      <method name="exploreMe" desc="(Ljava/lang/String;)V" line="9">
+        <counter type="METHOD" missed="0" covered="1"/>
+      </method>
+    </class>
+    <class name="com/example/ExploreMe$Helper" sourcefilename="ExploreMe.java">
+      <method name="help" desc="()V" line="20">
+        <counter type="METHOD" missed="1" covered="0"/>
+      </method>
+    </class>
+    <sourcefile name="ExploreMe.java">
+      <line nr="3" mi="0" ci="2" mb="0" cb="0"/>
+      <line nr="6" mi="0" ci="3" mb="0" cb="0"/>
+      <line nr="10" mi="1" ci="4" mb="1" cb="3"/>
+      <line nr="21" mi="2" ci="0" mb="0" cb="0"/>
+    </sourcefile>
+  </package>
+</report>
+`
+
+func TestParseJacocoXML(t *testing.T) {
+	report, err := ParseJacocoXML(strings.NewReader(jacocoReport), []string{"/project/src/main/java"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"/project/src/main/java"}, report.Sources)
+	require.Len(t, report.Files, 1)
+
+	f := report.Files[0]
+	assert.Equal(t, "com/example/ExploreMe.java", f.Path)
+	assert.Equal(t, "com.example", f.Package)
+	require.Len(t, f.Classes, 2)
+
+	c := f.Classes[0]
+	assert.Equal(t, "com.example.ExploreMe", c.Name)
+	require.Len(t, c.Methods, 2)
+	assert.Equal(t, &Method{Name: "exploreMe", Signature: "(Ljava/lang/String;)V", Line: &Line{Number: 9, Hits: 1}}, c.Methods[1])
+	// The field initializer in line 3 belongs to the outer class
+	assert.Equal(t, []*Line{
+		{Number: 3, Hits: 1},
+		{Number: 6, Hits: 1},
+		{Number: 10, Hits: 1, BranchesFound: 4, BranchesHit: 3},
+	}, c.Lines)
+
+	c = f.Classes[1]
+	assert.Equal(t, "com.example.ExploreMe$Helper", c.Name)
+	assert.Equal(t, []*Line{{Number: 21}}, c.Lines)
+	assert.Equal(t, 0, c.Methods[0].Line.Hits)
+}
+
+func TestParseJacocoXML_Invalid(t *testing.T) {
+	_, err := ParseJacocoXML(strings.NewReader("<report"), nil)
+	assert.Error(t, err)
+}
@@ -0,0 +1,152 @@
+package converter
+
+import (
+	"bufio"
+	"io"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"code-intelligence.com/cifuzz/pkg/log"
+)
+
+// ParseLcov parses the line, branch and function coverage of an lcov
+// tracefile, as created by `llvm-cov export -format=lcov` and bazel.
+// Files are reported relative to the project directory if they are
+// part of it. Each file is reported as a single class.
+func ParseLcov(in io.Reader, projectDir string) (*Report, error) {
+	report := &Report{Sources: []string{filepath.ToSlash(projectDir)}}
+
+	var file *File
+	var class *Class
+	var lines map[int]*Line
+	var methods map[string]*Method
+
+	line := func(number int) *Line {
+		l, ok := lines[number]
+		if !ok {
+			l = &Line{Number: number}
+			lines[number] = l
+			class.Lines = append(class.Lines, l)
+		}
+		return l
+	}
+
+	// The definition of the lcov tracefile format can be viewed
+	// with `man geninfo`
+	scanner := bufio.NewScanner(in)
+	for scanner.Scan() {
+		key, value, _ := strings.Cut(scanner.Text(), ":")
+
+		if key == "SF" {
+			file = &File{Path: relativePath(value, projectDir)}
+			file.Package = packageName(file.Path)
+			class = &Class{Name: path.Base(file.Path)}
+			file.Classes = []*Class{class}
+			lines = make(map[int]*Line)
+			methods = make(map[string]*Method)
+			report.Files = append(report.Files, file)
+			continue
+		}
+		if key == "end_of_record" {
+			if class != nil {
+				sortLines(class.Lines)
+			}
+			file = nil
+			class = nil
+			continue
+		}
+		if file == nil {
+			continue
+		}
+
+		fields := strings.Split(value, ",")
+		switch key {
+		// DA:<line number>,<execution count>[,<checksum>]
+		case "DA":
+			if len(fields) < 2 {
+				log.Debugf("Parsing lcov: invalid line record %q", value)
+				break
+			}
+			number, err := strconv.Atoi(fields[0])
+			if err != nil {
+				return nil, errors.WithStack(err)
+			}
+			hits, err := strconv.Atoi(fields[1])
+			if err != nil {
+				return nil, errors.WithStack(err)
+			}
+			line(number).Hits += hits
+
+		// BRDA:<line number>,<block number>,<branch number>,<taken>
+		case "BRDA":
+			if len(fields) < 4 {
+				log.Debugf("Parsing lcov: invalid branch record %q", value)
+				break
+			}
+			number, err := strconv.Atoi(fields[0])
+			if err != nil {
+				return nil, errors.WithStack(err)
+			}
+			l := line(number)
+			l.BranchesFound++
+			// A taken value of "-" means that the branch was never
+			// executed
+			if fields[3] != "-" && fields[3] != "0" {
+				l.BranchesHit++
+			}
+
+		// FN:<line number>,<function name>
+		case "FN":
+			if len(fields) < 2 {
+				log.Debugf("Parsing lcov: invalid function record %q", value)
+				break
+			}
+			number, err := strconv.Atoi(fields[0])
+			if err != nil {
+				return nil, errors.WithStack(err)
+			}
+			name := strings.Join(fields[1:], ",")
+			m := &Method{Name: name, Line: &Line{Number: number}}
+			methods[name] = m
+			class.Methods = append(class.Methods, m)
+
+		// FNDA:<execution count>,<function name>
+		case "FNDA":
+			if len(fields) < 2 {
+				log.Debugf("Parsing lcov: invalid function record %q", value)
+				break
+			}
+			m, ok := methods[strings.Join(fields[1:], ",")]
+			if !ok {
+				break
+			}
+			hits, err := strconv.Atoi(fields[0])
+			if err != nil {
+				return nil, errors.WithStack(err)
+			}
+			m.Line.Hits += hits
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return report, nil
+}
+
+// relativePath returns the slash-separated path of the file relative
+// to the directory if it is part of it, else the unchanged path
+func relativePath(file, dir string) string {
+	if dir == "" || !filepath.IsAbs(file) {
+		return filepath.ToSlash(file)
+	}
+	rel, err := filepath.Rel(dir, file)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return filepath.ToSlash(file)
+	}
+	return filepath.ToSlash(rel)
+}
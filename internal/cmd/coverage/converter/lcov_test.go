@@ -0,0 +1,62 @@
+package converter
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseLcov(t *testing.T) {
+	projectDir := filepath.FromSlash("/project")
+	lcov := `SF:` + filepath.Join(projectDir, "src", "parser.cpp") + `
+FN:3,_Z5parsePKhm
+FN:10,_Z6unusedv
+FNDA:4,_Z5parsePKhm
+FNDA:0,_Z6unusedv
+DA:4,4
+DA:3,4
+DA:10,0
+BRDA:4,0,0,3
+BRDA:4,0,1,-
+LF:3
+LH:2
+end_of_record
+SF:/usr/include/vector
+DA:1,1
+end_of_record
+`
+	report, err := ParseLcov(strings.NewReader(lcov), projectDir)
+	require.NoError(t, err)
+	require.Len(t, report.Files, 2)
+
+	f := report.Files[0]
+	assert.Equal(t, "src/parser.cpp", f.Path)
+	assert.Equal(t, "src", f.Package)
+	require.Len(t, f.Classes, 1)
+	assert.Equal(t, "parser.cpp", f.Classes[0].Name)
+	assert.Equal(t, []*Line{
+		{Number: 3, Hits: 4},
+		{Number: 4, Hits: 4, BranchesFound: 2, BranchesHit: 1},
+		{Number: 10},
+	}, f.Lines())
+	require.Len(t, f.Classes[0].Methods, 2)
+	assert.Equal(t, &Method{Name: "_Z5parsePKhm", Line: &Line{Number: 3, Hits: 4}}, f.Classes[0].Methods[0])
+	assert.Equal(t, 0, f.Classes[0].Methods[1].Line.Hits)
+
+	// Files outside of the project directory keep their absolute path
+	assert.Equal(t, "/usr/include/vector", report.Files[1].Path)
+	assert.Equal(t, "usr.include", report.Files[1].Package)
+
+	counts := report.Counts()
+	assert.Equal(t, &Counts{LinesFound: 4, LinesHit: 3, BranchesFound: 2, BranchesHit: 1}, counts)
+}
+
+func TestParseLcov_Empty(t *testing.T) {
+	report, err := ParseLcov(strings.NewReader(""), "")
+	require.NoError(t, err)
+	assert.Empty(t, report.Files)
+	assert.Equal(t, 1.0, report.Counts().LineRate())
+}
@@ -0,0 +1,127 @@
+package converter
+
+import (
+	"path"
+	"sort"
+	"strings"
+)
+
+// Report is the line coverage of a fuzz test, independent of the
+// format of the coverage data it was parsed from
+type Report struct {
+	// The directories which the paths of the files are relative to
+	Sources []string
+	Files   []*File
+}
+
+type File struct {
+	// The slash-separated path of the file, relative to one of the
+	// sources of the report or absolute if it's not part of any of them
+	Path    string
+	Package string
+	Classes []*Class
+}
+
+type Class struct {
+	Name    string
+	Methods []*Method
+	Lines   []*Line
+}
+
+type Method struct {
+	Name      string
+	Signature string
+	// The first line of the method
+	Line *Line
+}
+
+type Line struct {
+	Number        int
+	Hits          int
+	BranchesFound int
+	BranchesHit   int
+}
+
+type Counts struct {
+	LinesFound    int
+	LinesHit      int
+	BranchesFound int
+	BranchesHit   int
+}
+
+func (c *Counts) add(lines []*Line) {
+	for _, line := range lines {
+		c.LinesFound++
+		if line.Hits > 0 {
+			c.LinesHit++
+		}
+		c.BranchesFound += line.BranchesFound
+		c.BranchesHit += line.BranchesHit
+	}
+}
+
+// LineRate returns the ratio of hit lines, which is 1 if there are no
+// lines
+func (c *Counts) LineRate() float64 {
+	return rate(c.LinesHit, c.LinesFound)
+}
+
+// BranchRate returns the ratio of hit branches, which is 1 if there
+// are no branches
+func (c *Counts) BranchRate() float64 {
+	return rate(c.BranchesHit, c.BranchesFound)
+}
+
+func rate(hit, found int) float64 {
+	if found == 0 {
+		return 1
+	}
+	return float64(hit) / float64(found)
+}
+
+func (c *Class) Counts() *Counts {
+	counts := &Counts{}
+	counts.add(c.Lines)
+	return counts
+}
+
+// Lines returns the lines of all classes of the file, sorted by line
+// number
+func (f *File) Lines() []*Line {
+	var lines []*Line
+	for _, c := range f.Classes {
+		lines = append(lines, c.Lines...)
+	}
+	sortLines(lines)
+	return lines
+}
+
+func (f *File) Counts() *Counts {
+	counts := &Counts{}
+	counts.add(f.Lines())
+	return counts
+}
+
+func (r *Report) Counts() *Counts {
+	counts := &Counts{}
+	for _, f := range r.Files {
+		counts.add(f.Lines())
+	}
+	return counts
+}
+
+// packageName returns a package name for a C/C++ source file, which is
+// the dot-separated directory of the file
+func packageName(filePath string) string {
+	dir := strings.TrimPrefix(path.Dir(filePath), "/")
+	if dir == "" {
+		return "."
+	}
+	return strings.ReplaceAll(dir, "/", ".")
+}
+
+func sortLines(lines []*Line) {
+	sort.SliceStable(lines, func(i, j int) bool {
+		return lines[i].Number < lines[j].Number
+	})
+}
@@ -0,0 +1,95 @@
+package converter
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+
+	"code-intelligence.com/cifuzz/pkg/log"
+	"code-intelligence.com/cifuzz/util/fileutil"
+)
+
+type sonarQubeCoverage struct {
+	XMLName xml.Name         `xml:"coverage"`
+	Version int              `xml:"version,attr"`
+	Files   []*sonarQubeFile `xml:"file"`
+}
+
+type sonarQubeFile struct {
+	Path  string           `xml:"path,attr"`
+	Lines []*sonarQubeLine `xml:"lineToCover"`
+}
+
+type sonarQubeLine struct {
+	LineNumber      int  `xml:"lineNumber,attr"`
+	Covered         bool `xml:"covered,attr"`
+	BranchesToCover int  `xml:"branchesToCover,attr,omitempty"`
+	CoveredBranches *int `xml:"coveredBranches,attr"`
+}
+
+// WriteSonarQube writes the report in the SonarQube generic coverage
+// format, see https://docs.sonarsource.com/sonarqube/latest/analyzing-source-code/test-coverage/generic-test-data/.
+// SonarQube fails the analysis if the report contains files which are
+// not part of the project, so the paths are made relative to the
+// project directory and files which don't exist in it are omitted.
+func (r *Report) WriteSonarQube(w io.Writer, projectDir string) error {
+	coverage := &sonarQubeCoverage{Version: 1}
+	for _, f := range r.Files {
+		path := r.projectPath(f, projectDir)
+		if path == "" {
+			log.Debugf("Omitting file %s from the SonarQube report, it's not part of the project", f.Path)
+			continue
+		}
+		file := &sonarQubeFile{Path: path}
+		for _, l := range f.Lines() {
+			line := &sonarQubeLine{LineNumber: l.Number, Covered: l.Hits > 0}
+			if l.BranchesFound > 0 {
+				line.BranchesToCover = l.BranchesFound
+				branchesHit := l.BranchesHit
+				line.CoveredBranches = &branchesHit
+			}
+			file.Lines = append(file.Lines, line)
+		}
+		coverage.Files = append(coverage.Files, file)
+	}
+
+	out, err := xml.MarshalIndent(coverage, "", "  ")
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	_, err = fmt.Fprintf(w, "%s%s\n", xml.Header, out)
+	return errors.WithStack(err)
+}
+
+// projectPath returns the slash-separated path of the file relative to
+// the project directory, or an empty string if the file doesn't exist
+// in the project directory
+func (r *Report) projectPath(f *File, projectDir string) string {
+	var candidates []string
+	if filepath.IsAbs(filepath.FromSlash(f.Path)) {
+		candidates = append(candidates, filepath.FromSlash(f.Path))
+	} else {
+		for _, source := range r.Sources {
+			candidates = append(candidates, filepath.Join(filepath.FromSlash(source), filepath.FromSlash(f.Path)))
+		}
+	}
+
+	for _, path := range candidates {
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(projectDir, path)
+		}
+		exists, err := fileutil.Exists(path)
+		if err != nil || !exists {
+			continue
+		}
+		rel := relativePath(path, projectDir)
+		if filepath.IsAbs(filepath.FromSlash(rel)) {
+			return ""
+		}
+		return rel
+	}
+	return ""
+}
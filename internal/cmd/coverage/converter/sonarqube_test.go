@@ -0,0 +1,48 @@
+package converter
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteSonarQube(t *testing.T) {
+	projectDir := t.TempDir()
+	sourceDir := filepath.Join(projectDir, "src", "main", "java")
+	err := os.MkdirAll(filepath.Join(sourceDir, "com", "example"), 0o755)
+	require.NoError(t, err)
+	err = os.WriteFile(filepath.Join(sourceDir, "com", "example", "ExploreMe.java"), nil, 0o644)
+	require.NoError(t, err)
+
+	jacoco := strings.Replace(jacocoReport, "</report>", `  <package name="org/dependency">
+    <sourcefile name="Missing.java">
+      <line nr="1" mi="0" ci="1" mb="0" cb="0"/>
+    </sourcefile>
+  </package>
+</report>`, 1)
+	report, err := ParseJacocoXML(strings.NewReader(jacoco), []string{sourceDir})
+	require.NoError(t, err)
+	require.Len(t, report.Files, 2)
+
+	out := &bytes.Buffer{}
+	err = report.WriteSonarQube(out, projectDir)
+	require.NoError(t, err)
+
+	// Files which are not part of the project are omitted
+	expected := `<?xml version="1.0" encoding="UTF-8"?>
+<coverage version="1">
+  <file path="src/main/java/com/example/ExploreMe.java">
+    <lineToCover lineNumber="3" covered="true"></lineToCover>
+    <lineToCover lineNumber="6" covered="true"></lineToCover>
+    <lineToCover lineNumber="10" covered="true" branchesToCover="4" coveredBranches="3"></lineToCover>
+    <lineToCover lineNumber="21" covered="false"></lineToCover>
+  </file>
+</coverage>
+`
+	assert.Equal(t, expected, out.String())
+}
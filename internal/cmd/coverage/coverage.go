@@ -6,6 +6,7 @@ import (
 	"path/filepath"
 	"runtime"
 	"strings"
+	"time"
 
 	"github.com/pkg/browser"
 	"github.com/pkg/errors"
@@ -25,8 +26,11 @@ import (
 	"code-intelligence.com/cifuzz/internal/completion"
 	"code-intelligence.com/cifuzz/internal/config"
 	"code-intelligence.com/cifuzz/internal/coverage"
+	"code-intelligence.com/cifuzz/internal/sonarqube"
 	"code-intelligence.com/cifuzz/pkg/dependencies"
+	"code-intelligence.com/cifuzz/pkg/finding"
 	"code-intelligence.com/cifuzz/pkg/log"
+	"code-intelligence.com/cifuzz/util/fileutil"
 	"code-intelligence.com/cifuzz/util/stringutil"
 )
 
@@ -36,14 +40,15 @@ type Generator interface {
 }
 
 type coverageOptions struct {
-	OutputFormat   string   `mapstructure:"format"`
-	OutputPath     string   `mapstructure:"output"`
-	BuildSystem    string   `mapstructure:"build-system"`
-	BuildCommand   string   `mapstructure:"build-command"`
-	CleanCommand   string   `mapstructure:"clean-command"`
-	NumBuildJobs   uint     `mapstructure:"build-jobs"`
-	SeedCorpusDirs []string `mapstructure:"seed-corpus-dirs"`
-	UseSandbox     bool     `mapstructure:"use-sandbox"`
+	OutputFormat        string   `mapstructure:"format"`
+	OutputPath          string   `mapstructure:"output"`
+	TestExecutionReport string   `mapstructure:"test-execution-report"`
+	BuildSystem         string   `mapstructure:"build-system"`
+	BuildCommand        string   `mapstructure:"build-command"`
+	CleanCommand        string   `mapstructure:"clean-command"`
+	NumBuildJobs        uint     `mapstructure:"build-jobs"`
+	SeedCorpusDirs      []string `mapstructure:"seed-corpus-dirs"`
+	UseSandbox          bool     `mapstructure:"use-sandbox"`
 
 	ResolveSourceFilePath bool
 	Preset                string
@@ -120,7 +125,9 @@ can be found in the help message of the run command.
 Additional arguments for CMake and Bazel can be passed after a "--".
 
 The output can be displayed in the browser or written as a HTML
-or a lcov trace file.
+report, a lcov trace file, a JaCoCo XML report, a Cobertura XML
+report or a SonarQube generic coverage report. The Cobertura and
+SonarQube formats are supported for all build systems.
 
 ` + pterm.Style{pterm.Reset, pterm.Bold}.Sprint("Browser") + `
     cifuzz coverage <fuzz test>
@@ -133,6 +140,17 @@ or a lcov trace file.
 
 ` + pterm.Style{pterm.Reset, pterm.Bold}.Sprint("XML (Jacoco Report)") + `
     cifuzz coverage --format=jacocoxml <fuzz test>
+
+` + pterm.Style{pterm.Reset, pterm.Bold}.Sprint("XML (Cobertura Report)") + `
+    cifuzz coverage --format=cobertura <fuzz test>
+
+` + pterm.Style{pterm.Reset, pterm.Bold}.Sprint("XML (SonarQube Generic Coverage)") + `
+    cifuzz coverage --format=sonarqube <fuzz test>
+
+To import the fuzz test and its findings into SonarQube as test
+results, additionally write a generic test execution report:
+
+    cifuzz coverage --format=sonarqube --test-execution-report=test-executions.xml <fuzz test>
 `,
 		ValidArgsFunction: completion.ValidFuzzTests,
 		PreRunE: func(cmd *cobra.Command, args []string) error {
@@ -142,6 +160,7 @@ or a lcov trace file.
 			bindFlags()
 			cmdutils.ViperMustBindPFlag("format", cmd.Flags().Lookup("format"))
 			cmdutils.ViperMustBindPFlag("output", cmd.Flags().Lookup("output"))
+			cmdutils.ViperMustBindPFlag("test-execution-report", cmd.Flags().Lookup("test-execution-report"))
 
 			var lenFuzzTestArgs int
 			var argsToPass []string
@@ -208,8 +227,11 @@ or a lcov trace file.
 	if err != nil {
 		panic(err)
 	}
-	cmd.Flags().StringP("format", "f", "html", "Output format of the coverage report (html/lcov).")
+	cmd.Flags().StringP("format", "f", "html", "Output format of the coverage report (html/lcov/jacocoxml/cobertura/sonarqube).")
 	cmd.Flags().StringP("output", "o", "", "Output path of the coverage report.")
+	cmd.Flags().String("test-execution-report", "",
+		"Write a SonarQube generic test execution report to the specified `path`.\n"+
+			"The fuzz test and each of its findings are reported as a test case.")
 	err = cmd.RegisterFlagCompletionFunc("format", completion.ValidCoverageOutputFormat)
 	if err != nil {
 		panic(err)
@@ -297,9 +319,10 @@ func (c *coverageCmd) run() error {
 		}
 
 		gen = &gradleCoverage.CoverageGenerator{
-			OutputPath: c.opts.OutputPath,
-			FuzzTest:   c.opts.fuzzTest,
-			ProjectDir: c.opts.ProjectDir,
+			OutputFormat: c.opts.OutputFormat,
+			OutputPath:   c.opts.OutputPath,
+			FuzzTest:     c.opts.fuzzTest,
+			ProjectDir:   c.opts.ProjectDir,
 			Parallel: gradle.ParallelOptions{
 				Enabled: viper.IsSet("build-jobs"),
 			},
@@ -314,9 +337,10 @@ func (c *coverageCmd) run() error {
 		}
 
 		gen = &mavenCoverage.CoverageGenerator{
-			OutputPath: c.opts.OutputPath,
-			FuzzTest:   c.opts.fuzzTest,
-			ProjectDir: c.opts.ProjectDir,
+			OutputFormat: c.opts.OutputFormat,
+			OutputPath:   c.opts.OutputPath,
+			FuzzTest:     c.opts.fuzzTest,
+			ProjectDir:   c.opts.ProjectDir,
 			Parallel: maven.ParallelOptions{
 				Enabled: viper.IsSet("build-jobs"),
 				NumJobs: c.opts.NumBuildJobs,
@@ -329,6 +353,7 @@ func (c *coverageCmd) run() error {
 		return errors.Errorf("Unsupported build system \"%s\"", c.opts.BuildSystem)
 	}
 
+	start := time.Now()
 	err = gen.BuildFuzzTestForCoverage()
 	if err != nil {
 		if logging.ShouldLogBuildToFile() {
@@ -360,6 +385,13 @@ func (c *coverageCmd) run() error {
 		return err
 	}
 
+	if c.opts.TestExecutionReport != "" {
+		err = c.writeTestExecutionReport(time.Since(start))
+		if err != nil {
+			return err
+		}
+	}
+
 	switch c.opts.OutputFormat {
 	case coverage.FormatHTML:
 		return c.handleHTMLReport(reportPath)
@@ -369,11 +401,96 @@ func (c *coverageCmd) run() error {
 	case coverage.FormatJacocoXML:
 		log.Successf("Created jacoco.xml coverage report: %s", reportPath)
 		return nil
+	case coverage.FormatCobertura:
+		log.Successf("Created Cobertura coverage report: %s", reportPath)
+		return nil
+	case coverage.FormatSonarQube:
+		log.Successf("Created SonarQube coverage report: %s", reportPath)
+		return nil
 	default:
 		return errors.Errorf("Unsupported output format")
 	}
 }
 
+// writeTestExecutionReport writes a SonarQube generic test execution
+// report with the fuzz test and its findings. The duration of the fuzz
+// test is the duration of the coverage run.
+func (c *coverageCmd) writeTestExecutionReport(duration time.Duration) error {
+	findings, err := finding.ListFindings(c.opts.ProjectDir, nil)
+	if err != nil {
+		return err
+	}
+	var fuzzTestFindings []*finding.Finding
+	for _, f := range findings {
+		if f.FuzzTest == c.opts.fuzzTest {
+			fuzzTestFindings = append(fuzzTestFindings, f)
+		}
+	}
+
+	sourceFile, err := c.fuzzTestSourceFile(fuzzTestFindings)
+	if err != nil {
+		return err
+	}
+	if sourceFile == "" {
+		log.Warnf(`Not creating the test execution report: Failed to determine the source file of %s.
+SonarQube requires the source file of each test case.`, c.opts.fuzzTest)
+		return nil
+	}
+
+	suppressions, err := finding.LoadSuppressions(c.opts.ProjectDir)
+	if err != nil {
+		return err
+	}
+
+	results := []*sonarqube.FuzzTestResult{{
+		FuzzTest:     c.opts.fuzzTest,
+		SourceFile:   sourceFile,
+		Duration:     duration,
+		Findings:     fuzzTestFindings,
+		Suppressions: suppressions,
+	}}
+	err = sonarqube.WriteTestExecutionReport(c.opts.TestExecutionReport, results)
+	if err != nil {
+		return err
+	}
+	log.Infof("Created SonarQube test execution report: %s", fileutil.PrettifyPath(c.opts.TestExecutionReport))
+	return nil
+}
+
+// fuzzTestSourceFile returns the source file of the fuzz test relative
+// to the project directory. If it can't be determined from the build
+// system configuration, the source file of the harness function in the
+// stack traces of the findings is used.
+func (c *coverageCmd) fuzzTestSourceFile(findings []*finding.Finding) (string, error) {
+	sourceFile, err := resolve.SourceFile(c.opts.fuzzTest, c.opts.BuildSystem, c.opts.ProjectDir)
+	if err != nil || sourceFile != "" {
+		return sourceFile, err
+	}
+
+	for _, f := range findings {
+		path := f.HarnessSourceFile()
+		if path == "" {
+			continue
+		}
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(c.opts.ProjectDir, path)
+		}
+		exists, err := fileutil.Exists(path)
+		if err != nil {
+			return "", err
+		}
+		if !exists {
+			continue
+		}
+		rel, err := filepath.Rel(c.opts.ProjectDir, path)
+		if err != nil || strings.HasPrefix(rel, "..") {
+			continue
+		}
+		return rel, nil
+	}
+	return "", nil
+}
+
 func (c *coverageCmd) handleHTMLReport(reportPath string) error {
 	htmlFile := filepath.Join(reportPath, "index.html")
 
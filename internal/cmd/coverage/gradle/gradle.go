@@ -12,6 +12,7 @@ import (
 	"github.com/pkg/errors"
 
 	"code-intelligence.com/cifuzz/internal/build/gradle"
+	"code-intelligence.com/cifuzz/internal/cmd/coverage/converter"
 	"code-intelligence.com/cifuzz/internal/cmd/coverage/summary"
 	"code-intelligence.com/cifuzz/internal/cmdutils"
 	"code-intelligence.com/cifuzz/internal/coverage"
@@ -64,7 +65,9 @@ func (cov *CoverageGenerator) BuildFuzzTestForCoverage() error {
 
 	gradleArgs = append(gradleArgs, GradleReportTask, fmt.Sprintf("-Pcifuzz.report.output=%s", cov.OutputPath))
 
-	if cov.OutputFormat == coverage.FormatJacocoXML {
+	// Reports in the converted formats are created from the JaCoCo XML
+	// report
+	if cov.OutputFormat == coverage.FormatJacocoXML || stringutil.Contains(coverage.ConvertedFormats, cov.OutputFormat) {
 		gradleArgs = append(gradleArgs, fmt.Sprintf("-Pcifuzz.report.format=%s", coverage.FormatJacocoXML))
 	}
 
@@ -84,9 +87,32 @@ func (cov *CoverageGenerator) GenerateCoverageReport() (string, error) {
 		return filepath.Join(cov.OutputPath, "jacoco.xml"), nil
 	}
 
+	if stringutil.Contains(coverage.ConvertedFormats, cov.OutputFormat) {
+		return cov.generateConvertedReport(reportPath)
+	}
+
 	return filepath.Join(cov.OutputPath, "html"), nil
 }
 
+func (cov *CoverageGenerator) generateConvertedReport(jacocoReportPath string) (string, error) {
+	jacocoReport, err := os.Open(jacocoReportPath)
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+	defer jacocoReport.Close()
+	report, err := converter.ParseJacocoXML(jacocoReport, converter.JavaSourceDirs(cov.ProjectDir))
+	if err != nil {
+		return "", err
+	}
+
+	reportPath := filepath.Join(cov.OutputPath, converter.FileName(cov.OutputFormat))
+	err = report.WriteFile(reportPath, cov.OutputFormat, cov.ProjectDir)
+	if err != nil {
+		return "", err
+	}
+	return reportPath, nil
+}
+
 func (cov *CoverageGenerator) runGradleCommand(args []string) error {
 	gradleCmd, err := gradle.GetGradleCommand(cov.ProjectDir)
 	if err != nil {
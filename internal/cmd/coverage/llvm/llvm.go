@@ -18,9 +18,11 @@ import (
 	"code-intelligence.com/cifuzz/internal/build"
 	"code-intelligence.com/cifuzz/internal/build/cmake"
 	"code-intelligence.com/cifuzz/internal/build/other"
+	"code-intelligence.com/cifuzz/internal/cmd/coverage/converter"
 	"code-intelligence.com/cifuzz/internal/cmd/coverage/summary"
 	"code-intelligence.com/cifuzz/internal/cmdutils"
 	"code-intelligence.com/cifuzz/internal/config"
+	"code-intelligence.com/cifuzz/internal/coverage"
 	"code-intelligence.com/cifuzz/pkg/binary"
 	"code-intelligence.com/cifuzz/pkg/log"
 	"code-intelligence.com/cifuzz/pkg/minijail"
@@ -329,6 +331,14 @@ func (cov *CoverageGenerator) report() (string, error) {
 		if err != nil {
 			return "", err
 		}
+
+	default:
+		if stringutil.Contains(coverage.ConvertedFormats, cov.OutputFormat) {
+			reportPath, err = cov.generateConvertedReport()
+			if err != nil {
+				return "", err
+			}
+		}
 	}
 
 	return reportPath, nil
@@ -512,6 +522,38 @@ func (cov *CoverageGenerator) generateLcovReport() (string, error) {
 	return outputPath, nil
 }
 
+func (cov *CoverageGenerator) generateConvertedReport() (string, error) {
+	args := []string{"export", "-format=lcov"}
+	ignoreCIFuzzIncludesArgs, err := cov.getIgnoreCIFuzzIncludesArgs()
+	if err != nil {
+		return "", err
+	}
+	args = append(args, ignoreCIFuzzIncludesArgs...)
+	lcovReport, err := cov.runLlvmCov(args)
+	if err != nil {
+		return "", err
+	}
+	report, err := converter.ParseLcov(strings.NewReader(lcovReport), cov.ProjectDir)
+	if err != nil {
+		return "", err
+	}
+
+	outputPath := cov.OutputPath
+	if cov.OutputPath == "" {
+		// Like lcov reports, converted reports are created in the
+		// current working directory by default
+		outputPath = cov.executableName() + "." + converter.FileName(cov.OutputFormat)
+	}
+
+	err = report.WriteFile(outputPath, cov.OutputFormat, cov.ProjectDir)
+	if err != nil {
+		return "", err
+	}
+
+	log.Debugf("Created %s report: %s", cov.OutputFormat, outputPath)
+	return outputPath, nil
+}
+
 func (cov *CoverageGenerator) lcovReportSummary() (string, error) {
 	args := []string{"export", "-format=lcov", "-summary-only"}
 	ignoreCIFuzzIncludesArgs, err := cov.getIgnoreCIFuzzIncludesArgs()
@@ -12,6 +12,7 @@ import (
 	"github.com/pkg/errors"
 
 	"code-intelligence.com/cifuzz/internal/build/maven"
+	"code-intelligence.com/cifuzz/internal/cmd/coverage/converter"
 	"code-intelligence.com/cifuzz/internal/cmd/coverage/summary"
 	"code-intelligence.com/cifuzz/internal/cmdutils"
 	"code-intelligence.com/cifuzz/internal/coverage"
@@ -80,7 +81,9 @@ func (cov *CoverageGenerator) BuildFuzzTestForCoverage() error {
 		fmt.Sprintf("-Dcifuzz.report.output=%s", cov.OutputPath),
 	}
 
-	if cov.OutputFormat == coverage.FormatJacocoXML {
+	// Reports in the converted formats are created from the JaCoCo XML
+	// report
+	if cov.OutputFormat == coverage.FormatJacocoXML || stringutil.Contains(coverage.ConvertedFormats, cov.OutputFormat) {
 		mavenReportArgs = append(mavenReportArgs, "-Dcifuzz.report.format=XML")
 	} else {
 		mavenReportArgs = append(mavenReportArgs, "-Dcifuzz.report.format=XML,HTML")
@@ -102,9 +105,32 @@ func (cov *CoverageGenerator) GenerateCoverageReport() (string, error) {
 		return filepath.Join(cov.OutputPath, "jacoco.xml"), nil
 	}
 
+	if stringutil.Contains(coverage.ConvertedFormats, cov.OutputFormat) {
+		return cov.generateConvertedReport(reportPath)
+	}
+
 	return cov.OutputPath, nil
 }
 
+func (cov *CoverageGenerator) generateConvertedReport(jacocoReportPath string) (string, error) {
+	jacocoReport, err := os.Open(jacocoReportPath)
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+	defer jacocoReport.Close()
+	report, err := converter.ParseJacocoXML(jacocoReport, converter.JavaSourceDirs(cov.ProjectDir))
+	if err != nil {
+		return "", err
+	}
+
+	reportPath := filepath.Join(cov.OutputPath, converter.FileName(cov.OutputFormat))
+	err = report.WriteFile(reportPath, cov.OutputFormat, cov.ProjectDir)
+	if err != nil {
+		return "", err
+	}
+	return reportPath, nil
+}
+
 func (cov *CoverageGenerator) runMavenCommand(args []string) error {
 	mavenCmd, err := cov.runfilesFinder.MavenPath()
 	if err != nil {
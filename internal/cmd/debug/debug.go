@@ -0,0 +1,443 @@
+package debug
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"code-intelligence.com/cifuzz/internal/build"
+	"code-intelligence.com/cifuzz/internal/build/fuzztest"
+	"code-intelligence.com/cifuzz/internal/cmdutils"
+	"code-intelligence.com/cifuzz/internal/completion"
+	"code-intelligence.com/cifuzz/internal/config"
+	"code-intelligence.com/cifuzz/internal/ldd"
+	"code-intelligence.com/cifuzz/pkg/finding"
+	"code-intelligence.com/cifuzz/pkg/log"
+	cifuzz_options "code-intelligence.com/cifuzz/pkg/options"
+	"code-intelligence.com/cifuzz/pkg/parser/libfuzzer/stacktrace"
+	fuzzer_runner "code-intelligence.com/cifuzz/pkg/runner"
+	"code-intelligence.com/cifuzz/pkg/runner/jazzer"
+	"code-intelligence.com/cifuzz/pkg/runner/libfuzzer"
+	"code-intelligence.com/cifuzz/util/envutil"
+	"code-intelligence.com/cifuzz/util/fileutil"
+)
+
+const (
+	DebuggerGDB  = "gdb"
+	DebuggerLLDB = "lldb"
+	DebuggerJDB  = "jdb"
+)
+
+var validDebuggers = []string{DebuggerGDB, DebuggerLLDB, DebuggerJDB}
+
+type options struct {
+	BuildSystem  string `mapstructure:"build-system"`
+	BuildCommand string `mapstructure:"build-command"`
+	CleanCommand string `mapstructure:"clean-command"`
+	NumBuildJobs uint   `mapstructure:"build-jobs"`
+	ProjectDir   string `mapstructure:"project-dir"`
+	ConfigDir    string `mapstructure:"config-dir"`
+
+	Debugger string
+	VSCode   bool
+}
+
+func (opts *options) validate() error {
+	var err error
+
+	if opts.BuildSystem == "" {
+		opts.BuildSystem, err = config.DetermineBuildSystem(opts.ProjectDir)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = config.ValidateBuildSystem(opts.BuildSystem)
+	if err != nil {
+		log.Error(err)
+		return cmdutils.WrapSilentError(err)
+	}
+
+	// To build with other build systems, a build command must be provided
+	if opts.BuildSystem == config.BuildSystemOther && opts.BuildCommand == "" {
+		msg := "Flag \"build-command\" must be set when using build system type \"other\""
+		return cmdutils.WrapIncorrectUsageError(errors.New(msg))
+	}
+
+	isJava := opts.BuildSystem == config.BuildSystemMaven || opts.BuildSystem == config.BuildSystemGradle
+	if opts.Debugger == "" {
+		opts.Debugger = defaultDebugger(isJava)
+	}
+	switch opts.Debugger {
+	case DebuggerGDB, DebuggerLLDB:
+		if isJava {
+			msg := fmt.Sprintf("Debugger %q can't be used with build system %q, use %q instead",
+				opts.Debugger, opts.BuildSystem, DebuggerJDB)
+			return cmdutils.WrapIncorrectUsageError(errors.New(msg))
+		}
+	case DebuggerJDB:
+		if !isJava {
+			msg := fmt.Sprintf("Debugger %q can only be used with Maven and Gradle", opts.Debugger)
+			return cmdutils.WrapIncorrectUsageError(errors.New(msg))
+		}
+	default:
+		msg := fmt.Sprintf("Invalid debugger %q, valid debuggers are: %s",
+			opts.Debugger, strings.Join(validDebuggers, ", "))
+		return cmdutils.WrapIncorrectUsageError(errors.New(msg))
+	}
+
+	return nil
+}
+
+// defaultDebugger returns jdb for Java projects. Else, it returns gdb
+// if it's installed and the platform is not macOS, and lldb otherwise.
+func defaultDebugger(isJava bool) string {
+	if isJava {
+		return DebuggerJDB
+	}
+	if runtime.GOOS != "darwin" {
+		if _, err := exec.LookPath(DebuggerGDB); err == nil {
+			return DebuggerGDB
+		}
+	}
+	return DebuggerLLDB
+}
+
+type debugCmd struct {
+	*cobra.Command
+	opts *options
+}
+
+func New() *cobra.Command {
+	return newWithOptions(&options{})
+}
+
+func newWithOptions(opts *options) *cobra.Command {
+	var bindFlags func()
+
+	cmd := &cobra.Command{
+		Use:   "debug <finding>",
+		Short: "Debug a finding",
+		Long: `This command builds the fuzz test of a finding and starts a debugger
+which executes the fuzz test with the crashing input of the finding.
+The environment is the same as with 'cifuzz run', except that the fuzz
+test is not executed in a sandbox and the sanitizers abort on errors,
+so that the debugger stops at the error.
+
+A breakpoint is set at the top in-project frame of the stack trace of
+the finding.
+
+For CMake, Bazel and other build systems, gdb is used if it's
+installed, else lldb. For Maven and Gradle, jdb is used. Use
+--debugger to choose a different debugger.
+
+With --vscode, no debugger is started. Instead, a configuration which
+can be added to the .vscode/launch.json file is printed.
+`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completion.ValidFindings,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			// Bind viper keys to flags. We can't do this in the New
+			// function, because that would re-bind viper keys which
+			// were bound to the flags of other commands before.
+			bindFlags()
+			err := config.FindAndParseProjectConfig(opts)
+			if err != nil {
+				log.Errorf(err, "Failed to parse cifuzz.yaml: %v", err.Error())
+				return cmdutils.WrapSilentError(err)
+			}
+			return opts.validate()
+		},
+		RunE: func(c *cobra.Command, args []string) error {
+			cmd := debugCmd{Command: c, opts: opts}
+			return cmd.run(args[0])
+		},
+	}
+
+	bindFlags = cmdutils.AddFlags(cmd,
+		cmdutils.AddBuildCommandFlag,
+		cmdutils.AddCleanCommandFlag,
+		cmdutils.AddBuildJobsFlag,
+		cmdutils.AddProjectDirFlag,
+	)
+	cmd.Flags().StringVar(&opts.Debugger, "debugger", "",
+		fmt.Sprintf("The debugger to use (%s).", strings.Join(validDebuggers, ", ")))
+	cmd.Flags().BoolVar(&opts.VSCode, "vscode", false,
+		"Print a VS Code launch configuration instead of starting the debugger.")
+
+	return cmd
+}
+
+func (c *debugCmd) run(findingName string) error {
+	f, err := finding.LoadFinding(c.opts.ProjectDir, findingName, nil)
+	if finding.IsNotExistError(err) {
+		log.Errorf(err, "Finding %s does not exist", findingName)
+		return cmdutils.WrapSilentError(err)
+	}
+	if err != nil {
+		return err
+	}
+	if f.FuzzTest == "" {
+		err = errors.Errorf("The fuzz test of finding %s is unknown", findingName)
+		log.Error(err)
+		return cmdutils.WrapSilentError(err)
+	}
+
+	// The input must stay available after this command exits when a
+	// VS Code launch configuration is created, so we use the one in
+	// the finding directory
+	inputPath, err := f.InputPath(c.opts.ProjectDir)
+	if err != nil {
+		return err
+	}
+
+	tempDir, err := os.MkdirTemp("", "cifuzz-debug-")
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defer fileutil.Cleanup(tempDir)
+
+	var buildOutput io.Writer = io.Discard
+	if viper.GetBool("verbose") {
+		buildOutput = c.ErrOrStderr()
+	}
+	log.Infof("Building %s", f.FuzzTest)
+	buildOpts := &fuzztest.BuildOptions{
+		BuildSystem:  c.opts.BuildSystem,
+		BuildCommand: c.opts.BuildCommand,
+		CleanCommand: c.opts.CleanCommand,
+		NumBuildJobs: c.opts.NumBuildJobs,
+		ProjectDir:   c.opts.ProjectDir,
+		FuzzTest:     f.FuzzTest,
+		TempDir:      tempDir,
+		Stdout:       buildOutput,
+		Stderr:       buildOutput,
+	}
+	buildResult, err := fuzztest.Build(buildOpts)
+	if err != nil {
+		return err
+	}
+
+	target, err := c.debugTarget(f, buildResult, inputPath)
+	if err != nil {
+		return err
+	}
+
+	if c.opts.VSCode {
+		s, err := vscodeLaunchConfig(f.Name, c.opts.Debugger, target)
+		if err != nil {
+			return err
+		}
+		_, _ = fmt.Fprintln(c.OutOrStdout(), s)
+		if target.breakpoint != nil {
+			log.Infof("Set a breakpoint at %s to stop at the top in-project frame", target.breakpoint.Location())
+		}
+		return nil
+	}
+
+	args, stdin := debuggerCommand(c.opts.Debugger, target)
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Env, err = envutil.Copy(os.Environ(), target.env)
+	if err != nil {
+		return err
+	}
+	cmd.Dir = c.opts.ProjectDir
+	cmd.Stdin = stdin
+	cmd.Stdout = c.OutOrStdout()
+	cmd.Stderr = c.ErrOrStderr()
+	log.Debugf("Command: %s", envutil.QuotedCommandWithEnv(cmd.Args, target.env))
+	err = cmd.Run()
+	if err != nil {
+		return cmdutils.WrapExecError(errors.WithStack(err), cmd)
+	}
+	return nil
+}
+
+// debugTarget is the program which is debugged, i.e. the fuzz test
+// executable or the JVM which runs Jazzer
+type debugTarget struct {
+	program string
+	args    []string
+	env     []string
+	// The main class and class path when debugging Java
+	mainClass  string
+	classPaths []string
+
+	breakpoint *stacktrace.StackFrame
+}
+
+func (c *debugCmd) debugTarget(f *finding.Finding, buildResult *build.Result, inputPath string) (*debugTarget, error) {
+	var err error
+
+	target := &debugTarget{breakpoint: topInProjectFrame(f)}
+
+	var libraryPaths []string
+	if runtime.GOOS != "windows" && buildResult.Executable != "" {
+		libraryPaths, err = ldd.LibraryPaths(buildResult.Executable)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+	}
+
+	runnerOpts := &libfuzzer.RunnerOptions{
+		EnvVars:     []string{"NO_CIFUZZ=1"},
+		FuzzTarget:  buildResult.Executable,
+		LibraryDirs: libraryPaths,
+		ProjectDir:  c.opts.ProjectDir,
+	}
+
+	switch c.opts.BuildSystem {
+	case config.BuildSystemMaven, config.BuildSystemGradle:
+		runner := jazzer.NewRunner(&jazzer.RunnerOptions{
+			TargetClass:      f.FuzzTest,
+			TargetMethod:     f.FuzzTestMethod(),
+			ClassPaths:       buildResult.RuntimeDeps,
+			LibfuzzerOptions: runnerOpts,
+		})
+		target.env, err = runner.FuzzerEnvironment()
+		if err != nil {
+			return nil, err
+		}
+		target.mainClass = cifuzz_options.JazzerMainClass
+		target.classPaths = buildResult.RuntimeDeps
+		target.args = []string{
+			cifuzz_options.JazzerTargetClassFlag(runner.TargetClass),
+			cifuzz_options.JazzerTargetMethodFlag(runner.TargetMethod),
+			inputPath,
+		}
+	default:
+		target.env, err = libfuzzer.NewRunner(runnerOpts).FuzzerEnvironment()
+		if err != nil {
+			return nil, err
+		}
+		// Let the sanitizers abort on errors, so that the debugger
+		// stops at the error instead of the process exiting
+		overrideOptions := map[string]string{"abort_on_error": "1"}
+		target.env, err = fuzzer_runner.SetASANOptions(target.env, nil, overrideOptions)
+		if err != nil {
+			return nil, err
+		}
+		ubsanOptions := envutil.Getenv(target.env, "UBSAN_OPTIONS")
+		ubsanOptions = fuzzer_runner.SetSanitizerOptions(ubsanOptions, nil, overrideOptions)
+		target.env, err = envutil.Setenv(target.env, "UBSAN_OPTIONS", ubsanOptions)
+		if err != nil {
+			return nil, err
+		}
+		target.program = buildResult.Executable
+		target.args = []string{inputPath}
+	}
+
+	return target, nil
+}
+
+// topInProjectFrame returns the top stack frame of the finding which
+// doesn't belong to the runtimes of libFuzzer, the sanitizers or
+// Jazzer. The stack trace of a finding only contains frames of source
+// files in the project directory.
+func topInProjectFrame(f *finding.Finding) *stacktrace.StackFrame {
+	for _, frame := range f.StackTrace {
+		if frame.SourceFile != "" && frame.Line != 0 && !finding.IsRuntimeFrame(frame) {
+			return frame
+		}
+	}
+	return nil
+}
+
+// debuggerCommand returns the command which starts the debugger and,
+// for debuggers which read commands from stdin, the reader which
+// should be used as stdin.
+func debuggerCommand(debugger string, target *debugTarget) ([]string, io.Reader) {
+	switch debugger {
+	case DebuggerGDB:
+		args := []string{"gdb", "-q"}
+		if target.breakpoint != nil {
+			args = append(args, "-ex", "break "+gdbLocation(target.breakpoint))
+		}
+		args = append(args, "-ex", "run", "--args", target.program)
+		return append(args, target.args...), os.Stdin
+	case DebuggerLLDB:
+		args := []string{"lldb"}
+		if target.breakpoint != nil {
+			args = append(args, "-o", fmt.Sprintf("breakpoint set --file %s --line %d",
+				target.breakpoint.SourceFile, target.breakpoint.Line))
+		}
+		args = append(args, "-o", "run", "--", target.program)
+		return append(args, target.args...), os.Stdin
+	case DebuggerJDB:
+		args := []string{"jdb", "-classpath", strings.Join(target.classPaths, string(os.PathListSeparator)), target.mainClass}
+		args = append(args, target.args...)
+		// jdb doesn't support passing commands as arguments, so we
+		// write them to its stdin before forwarding the user's input
+		var commands string
+		if target.breakpoint != nil {
+			commands += fmt.Sprintf("stop at %s:%d\n", target.breakpoint.SourceFile, target.breakpoint.Line)
+		}
+		commands += "run\n"
+		return args, io.MultiReader(strings.NewReader(commands), os.Stdin)
+	}
+	return nil, nil
+}
+
+// gdbLocation returns the location of the stack frame in the format
+// expected by gdb's break command
+func gdbLocation(frame *stacktrace.StackFrame) string {
+	return fmt.Sprintf("%s:%d", frame.SourceFile, frame.Line)
+}
+
+// vscodeLaunchConfig returns a configuration for the .vscode/launch.json
+// file which launches the debug target. C/C++ configurations use the
+// "cppdbg" type of the Microsoft C/C++ extension, Java configurations
+// the "java" type of the Debugger for Java extension.
+func vscodeLaunchConfig(findingName, debugger string, target *debugTarget) (string, error) {
+	envMap := make(map[string]string)
+	for _, e := range target.env {
+		split := strings.SplitN(e, "=", 2)
+		if len(split) == 2 {
+			envMap[split[0]] = split[1]
+		}
+	}
+
+	config := map[string]interface{}{
+		"name":    "cifuzz: debug " + findingName,
+		"request": "launch",
+		"args":    target.args,
+		"cwd":     "${workspaceFolder}",
+	}
+
+	if debugger == DebuggerJDB {
+		config["type"] = "java"
+		config["mainClass"] = target.mainClass
+		config["classPaths"] = target.classPaths
+		config["env"] = envMap
+	} else {
+		config["type"] = "cppdbg"
+		config["program"] = target.program
+		config["MIMode"] = debugger
+		// The cppdbg type expects a list of name-value pairs
+		keys := make([]string, 0, len(envMap))
+		for key := range envMap {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		environment := []map[string]string{}
+		for _, key := range keys {
+			environment = append(environment, map[string]string{"name": key, "value": envMap[key]})
+		}
+		config["environment"] = environment
+	}
+
+	bytes, err := json.MarshalIndent(config, "", "  ")
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+	return string(bytes), nil
+}
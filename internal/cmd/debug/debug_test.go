@@ -0,0 +1,117 @@
+package debug
+
+import (
+	"encoding/json"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"code-intelligence.com/cifuzz/internal/config"
+	"code-intelligence.com/cifuzz/pkg/finding"
+	"code-intelligence.com/cifuzz/pkg/parser/libfuzzer/stacktrace"
+)
+
+func TestTopInProjectFrame(t *testing.T) {
+	f := &finding.Finding{
+		StackTrace: []*stacktrace.StackFrame{
+			{Function: "__asan_memcpy", SourceFile: "asan_interceptors.cpp", Line: 22},
+			{Function: "parse", SourceFile: "src/parser.cpp", Line: 11},
+			{Function: "LLVMFuzzerTestOneInputNoReturn", SourceFile: "fuzz_test.cpp", Line: 5},
+		},
+	}
+	frame := topInProjectFrame(f)
+	require.NotNil(t, frame)
+	assert.Equal(t, "parse", frame.Function)
+
+	assert.Nil(t, topInProjectFrame(&finding.Finding{}))
+}
+
+func TestDebuggerCommand(t *testing.T) {
+	target := &debugTarget{
+		program:    "/build/my_fuzz_test",
+		args:       []string{"/project/.cifuzz-findings/test_finding/crashing-input"},
+		breakpoint: &stacktrace.StackFrame{Function: "parse", SourceFile: "src/parser.cpp", Line: 11},
+	}
+
+	args, _ := debuggerCommand(DebuggerGDB, target)
+	assert.Equal(t, []string{
+		"gdb", "-q", "-ex", "break src/parser.cpp:11", "-ex", "run",
+		"--args", "/build/my_fuzz_test", "/project/.cifuzz-findings/test_finding/crashing-input",
+	}, args)
+
+	args, _ = debuggerCommand(DebuggerLLDB, target)
+	assert.Equal(t, []string{
+		"lldb", "-o", "breakpoint set --file src/parser.cpp --line 11", "-o", "run",
+		"--", "/build/my_fuzz_test", "/project/.cifuzz-findings/test_finding/crashing-input",
+	}, args)
+
+	javaTarget := &debugTarget{
+		mainClass:  "com.code_intelligence.jazzer.Jazzer",
+		classPaths: []string{"/deps/a.jar"},
+		args:       []string{"--target_class=com.example.FuzzTest", "--target_method=fuzz", "input"},
+		breakpoint: &stacktrace.StackFrame{Function: "parse", SourceFile: "com.example.Parser", Line: 12},
+	}
+	args, stdin := debuggerCommand(DebuggerJDB, javaTarget)
+	assert.Equal(t, []string{
+		"jdb", "-classpath", "/deps/a.jar", "com.code_intelligence.jazzer.Jazzer",
+		"--target_class=com.example.FuzzTest", "--target_method=fuzz", "input",
+	}, args)
+	// The commands are written to stdin before the user's input
+	buf := make([]byte, len("stop at com.example.Parser:12\nrun\n"))
+	_, err := io.ReadFull(stdin, buf)
+	require.NoError(t, err)
+	assert.Equal(t, "stop at com.example.Parser:12\nrun\n", string(buf))
+}
+
+func TestVSCodeLaunchConfig(t *testing.T) {
+	target := &debugTarget{
+		program: "/build/my_fuzz_test",
+		args:    []string{"input"},
+		env:     []string{"ASAN_OPTIONS=abort_on_error=1", "LD_LIBRARY_PATH=/build/lib"},
+	}
+	s, err := vscodeLaunchConfig("test_finding", DebuggerGDB, target)
+	require.NoError(t, err)
+
+	var launchConfig map[string]interface{}
+	err = json.Unmarshal([]byte(s), &launchConfig)
+	require.NoError(t, err)
+	assert.Equal(t, "cppdbg", launchConfig["type"])
+	assert.Equal(t, "gdb", launchConfig["MIMode"])
+	assert.Equal(t, "/build/my_fuzz_test", launchConfig["program"])
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"name": "ASAN_OPTIONS", "value": "abort_on_error=1"},
+		map[string]interface{}{"name": "LD_LIBRARY_PATH", "value": "/build/lib"},
+	}, launchConfig["environment"])
+
+	javaTarget := &debugTarget{
+		mainClass:  "com.code_intelligence.jazzer.Jazzer",
+		classPaths: []string{"/deps/a.jar"},
+		args:       []string{"input"},
+		env:        []string{"JAVA_HOME=/jdk"},
+	}
+	s, err = vscodeLaunchConfig("test_finding", DebuggerJDB, javaTarget)
+	require.NoError(t, err)
+	launchConfig = nil
+	err = json.Unmarshal([]byte(s), &launchConfig)
+	require.NoError(t, err)
+	assert.Equal(t, "java", launchConfig["type"])
+	assert.Equal(t, "com.code_intelligence.jazzer.Jazzer", launchConfig["mainClass"])
+	assert.Equal(t, map[string]interface{}{"JAVA_HOME": "/jdk"}, launchConfig["env"])
+}
+
+func TestValidate_Debugger(t *testing.T) {
+	opts := &options{BuildSystem: config.BuildSystemMaven}
+	require.NoError(t, opts.validate())
+	assert.Equal(t, DebuggerJDB, opts.Debugger)
+
+	opts = &options{BuildSystem: config.BuildSystemMaven, Debugger: DebuggerGDB}
+	require.Error(t, opts.validate())
+
+	opts = &options{BuildSystem: config.BuildSystemCMake, Debugger: DebuggerJDB}
+	require.Error(t, opts.validate())
+
+	opts = &options{BuildSystem: config.BuildSystemCMake, Debugger: "foo"}
+	require.Error(t, opts.validate())
+}
@@ -0,0 +1,297 @@
+package bisect
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"code-intelligence.com/cifuzz/internal/build/fuzztest"
+	"code-intelligence.com/cifuzz/internal/cmdutils"
+	"code-intelligence.com/cifuzz/internal/completion"
+	"code-intelligence.com/cifuzz/internal/config"
+	"code-intelligence.com/cifuzz/internal/replayer"
+	"code-intelligence.com/cifuzz/pkg/finding"
+	"code-intelligence.com/cifuzz/pkg/log"
+	"code-intelligence.com/cifuzz/pkg/parser/errorid"
+	"code-intelligence.com/cifuzz/pkg/vcs"
+	"code-intelligence.com/cifuzz/util/fileutil"
+)
+
+type options struct {
+	BuildSystem     string               `mapstructure:"build-system"`
+	BuildCommand    string               `mapstructure:"build-command"`
+	CleanCommand    string               `mapstructure:"clean-command"`
+	NumBuildJobs    uint                 `mapstructure:"build-jobs"`
+	ProjectDir      string               `mapstructure:"project-dir"`
+	ConfigDir       string               `mapstructure:"config-dir"`
+	Dedup           finding.DedupOptions `mapstructure:"dedup"`
+	ErrorIDMatchers errorid.UserMatchers `mapstructure:"error-ids"`
+
+	Good string
+	Bad  string
+}
+
+func (opts *options) validate() error {
+	var err error
+
+	if opts.BuildSystem == "" {
+		opts.BuildSystem, err = config.DetermineBuildSystem(opts.ProjectDir)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = config.ValidateBuildSystem(opts.BuildSystem)
+	if err != nil {
+		log.Error(err)
+		return cmdutils.WrapSilentError(err)
+	}
+
+	// To build with other build systems, a build command must be provided
+	if opts.BuildSystem == config.BuildSystemOther && opts.BuildCommand == "" {
+		msg := "Flag \"build-command\" must be set when using build system type \"other\""
+		return cmdutils.WrapIncorrectUsageError(errors.New(msg))
+	}
+
+	err = opts.Dedup.Validate()
+	if err != nil {
+		log.Error(err)
+		return cmdutils.WrapSilentError(err)
+	}
+
+	err = opts.ErrorIDMatchers.Validate()
+	if err != nil {
+		log.Error(err)
+		return cmdutils.WrapSilentError(err)
+	}
+
+	return nil
+}
+
+type bisectCmd struct {
+	*cobra.Command
+	opts *options
+
+	finding   *finding.Finding
+	inputFile string
+	tempDir   string
+
+	// testCommit checks whether the finding can be reproduced with the
+	// currently checked out commit
+	testCommit func(commit string) (vcs.BisectResult, error)
+}
+
+func New() *cobra.Command {
+	return newWithOptions(&options{})
+}
+
+func newWithOptions(opts *options) *cobra.Command {
+	var bindFlags func()
+
+	cmd := &cobra.Command{
+		Use:   "bisect <name> --good <revision>",
+		Short: "Find the commit which introduced a finding",
+		Long: `This command uses 'git bisect' to find the commit which introduced a
+finding. For each commit that git checks out, the fuzz test of the
+finding is built with the project's build system and executed with
+the crashing input of the finding. Commits which fail to build are
+skipped.
+
+The commit specified via --good must not contain the bug. By default,
+the commit in which the finding was found (or HEAD, if that is not
+known) is used as the bad commit.
+
+The first bad commit is stored in the finding and printed by
+'cifuzz finding <name> --json'.
+
+The working tree must not have uncommitted changes, because git
+bisect checks out other commits.
+`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completion.ValidFindings,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			// Bind viper keys to flags. We can't do this in the New
+			// function, because that would re-bind viper keys which
+			// were bound to the flags of other commands before.
+			bindFlags()
+			err := config.FindAndParseProjectConfig(opts)
+			if err != nil {
+				log.Errorf(err, "Failed to parse cifuzz.yaml: %v", err.Error())
+				return cmdutils.WrapSilentError(err)
+			}
+			return opts.validate()
+		},
+		RunE: func(c *cobra.Command, args []string) error {
+			cmd := &bisectCmd{Command: c, opts: opts}
+			cmd.testCommit = cmd.buildAndReplay
+			return cmd.run(args[0])
+		},
+	}
+
+	bindFlags = cmdutils.AddFlags(cmd,
+		cmdutils.AddBuildCommandFlag,
+		cmdutils.AddCleanCommandFlag,
+		cmdutils.AddBuildJobsFlag,
+		cmdutils.AddProjectDirFlag,
+	)
+	cmd.Flags().StringVar(&opts.Good, "good", "", "A revision which doesn't contain the bug.")
+	cmd.Flags().StringVar(&opts.Bad, "bad", "", "A revision which contains the bug.")
+	err := cmd.MarkFlagRequired("good")
+	if err != nil {
+		panic(err)
+	}
+
+	return cmd
+}
+
+func (c *bisectCmd) run(findingName string) error {
+	var err error
+
+	// Don't enhance the finding with error details, because it's
+	// saved again
+	c.finding, err = finding.LoadFinding(c.opts.ProjectDir, findingName, nil)
+	if finding.IsNotExistError(err) {
+		log.Errorf(err, "Finding %s does not exist", findingName)
+		return cmdutils.WrapSilentError(err)
+	}
+	if err != nil {
+		return err
+	}
+	if c.finding.FuzzTest == "" {
+		err = errors.Errorf("The fuzz test of finding %s is unknown", findingName)
+		log.Error(err)
+		return cmdutils.WrapSilentError(err)
+	}
+
+	if vcs.GitHasUncommittedChanges(c.opts.ProjectDir) {
+		err = errors.New("The working tree has uncommitted changes. Please commit or stash them before bisecting.")
+		log.Error(err)
+		return cmdutils.WrapSilentError(err)
+	}
+
+	c.tempDir, err = os.MkdirTemp("", "cifuzz-bisect-")
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defer fileutil.Cleanup(c.tempDir)
+
+	// Copy the crashing input to the temporary directory, so that it
+	// stays available when git checks out other commits
+	c.inputFile = filepath.Join(c.tempDir, "crashing-input")
+	input, err := c.finding.ReadInput(c.opts.ProjectDir)
+	if err != nil {
+		return err
+	}
+	err = os.WriteFile(c.inputFile, input, 0o644)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	bad := c.opts.Bad
+	if bad == "" {
+		bad = "HEAD"
+		if c.finding.GitRevision != nil && c.finding.GitRevision.Commit != "" {
+			bad = c.finding.GitRevision.Commit
+		}
+	}
+
+	log.Infof("Bisecting finding %s between %s (good) and %s (bad)", c.finding.Name, c.opts.Good, bad)
+	firstBadCommit, err := vcs.GitBisect(c.opts.ProjectDir, c.opts.Good, bad, c.testCommit)
+	if err != nil {
+		log.Error(err)
+		return cmdutils.WrapSilentError(err)
+	}
+
+	if c.finding.GitRevision == nil {
+		c.finding.GitRevision = &finding.GitRevision{}
+	}
+	c.finding.GitRevision.FirstBadCommit = firstBadCommit
+	err = c.finding.Save(c.opts.ProjectDir)
+	if err != nil {
+		return err
+	}
+
+	log.Successf("The first bad commit of finding %s is %s", c.finding.Name, firstBadCommit)
+	return nil
+}
+
+// buildAndReplay builds the fuzz test and executes it with the crashing
+// input. The commit is bad if this reproduces the finding and good if
+// it doesn't trigger any finding. If the build fails or the input
+// triggers a different finding, the commit is skipped.
+func (c *bisectCmd) buildAndReplay(commit string) (vcs.BisectResult, error) {
+	var buildOutput io.Writer = io.Discard
+	if viper.GetBool("verbose") {
+		buildOutput = c.ErrOrStderr()
+	}
+
+	log.Infof("Testing commit %s", commit)
+	buildOpts := &fuzztest.BuildOptions{
+		BuildSystem:  c.opts.BuildSystem,
+		BuildCommand: c.opts.BuildCommand,
+		CleanCommand: c.opts.CleanCommand,
+		NumBuildJobs: c.opts.NumBuildJobs,
+		ProjectDir:   c.opts.ProjectDir,
+		FuzzTest:     c.finding.FuzzTest,
+		TempDir:      c.tempDir,
+		Stdout:       buildOutput,
+		Stderr:       buildOutput,
+	}
+	buildResult, err := fuzztest.Build(buildOpts)
+	if err != nil {
+		log.Warnf("Skipping commit %s because the build failed", commit)
+		log.Debugf("Build error: %+v", err)
+		return vcs.BisectSkip, nil
+	}
+
+	findings, err := replayer.Replay(context.Background(), &replayer.Options{
+		BuildSystem: c.opts.BuildSystem,
+		BuildResult: buildResult,
+		FuzzTest:    buildOpts.FuzzTest,
+		ProjectDir:  c.opts.ProjectDir,
+		InputFiles:  []string{c.inputFile},
+	})
+	if err != nil {
+		log.Warnf("Skipping commit %s because the crashing input could not be executed", commit)
+		log.Debugf("Replay error: %+v", err)
+		return vcs.BisectSkip, nil
+	}
+
+	if len(findings) == 0 {
+		return vcs.BisectGood, nil
+	}
+
+	if c.reproduces(findings) {
+		return vcs.BisectBad, nil
+	}
+
+	log.Warnf("Skipping commit %s because the crashing input triggers a different finding: %s",
+		commit, findings[0].ShortDescription())
+	return vcs.BisectSkip, nil
+}
+
+// reproduces returns true if one of the findings which were produced by
+// replaying the crashing input has the same dedup key as the bisected
+// finding
+func (c *bisectCmd) reproduces(findings []*finding.Finding) bool {
+	key := c.opts.Dedup.Key(c.finding)
+	for _, f := range findings {
+		// Classify the finding like the findings of `cifuzz run`
+		if d := c.opts.ErrorIDMatchers.ErrorDetails(f); d != nil {
+			f.MoreDetails = d
+		}
+		// The replayed findings were triggered by the crashing input of
+		// the bisected finding, but the replayer doesn't set these fields
+		f.FuzzTest = c.finding.FuzzTest
+		f.InputData = c.finding.InputData
+		if c.opts.Dedup.Key(f) == key {
+			return true
+		}
+	}
+	return false
+}
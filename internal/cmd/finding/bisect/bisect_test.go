@@ -0,0 +1,121 @@
+package bisect
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"code-intelligence.com/cifuzz/internal/testutil"
+	"code-intelligence.com/cifuzz/pkg/finding"
+	"code-intelligence.com/cifuzz/pkg/log"
+	"code-intelligence.com/cifuzz/pkg/parser/errorid"
+	"code-intelligence.com/cifuzz/pkg/parser/libfuzzer/stacktrace"
+	"code-intelligence.com/cifuzz/pkg/vcs"
+	"code-intelligence.com/cifuzz/util/fileutil"
+)
+
+var logOutput io.ReadWriter
+
+func TestMain(m *testing.M) {
+	logOutput = bytes.NewBuffer([]byte{})
+	log.Output = logOutput
+
+	m.Run()
+}
+
+func TestBisect(t *testing.T) {
+	projectDir := testutil.BootstrapEmptyProject(t, "test-bisect-")
+	defer fileutil.Cleanup(projectDir)
+	// The project directory is deliberately not the working directory,
+	// to verify that git is run in the project directory
+
+	testutil.InitGitRepo(t, projectDir)
+	err := os.WriteFile(filepath.Join(projectDir, ".gitignore"), []byte("/.cifuzz-findings/\n"), 0o644)
+	require.NoError(t, err)
+
+	var commits []string
+	for _, content := range []string{"ok", "ok", "bug", "bug"} {
+		err = os.WriteFile(filepath.Join(projectDir, "state"), []byte(content+"\n"+string(rune('a'+len(commits)))), 0o644)
+		require.NoError(t, err)
+		testutil.RunGit(t, projectDir, "add", "-A")
+		testutil.RunGit(t, projectDir, "commit", "-m", "commit")
+		commit, err := vcs.GitCommit(projectDir)
+		require.NoError(t, err)
+		commits = append(commits, commit)
+	}
+
+	f := &finding.Finding{
+		Name:      "test_finding",
+		FuzzTest:  "my_fuzz_test",
+		InputData: []byte("crash"),
+	}
+	err = f.Save(projectDir)
+	require.NoError(t, err)
+
+	opts := &options{}
+	c := &bisectCmd{Command: newWithOptions(opts), opts: opts}
+	// Set the options after creating the command, because adding the
+	// flags resets them to their default values
+	opts.ProjectDir = projectDir
+	opts.Good = commits[0]
+	c.testCommit = func(commit string) (vcs.BisectResult, error) {
+		// The crashing input must be available
+		input, err := os.ReadFile(c.inputFile)
+		if err != nil {
+			return "", err
+		}
+		require.Equal(t, "crash", string(input))
+
+		state, err := os.ReadFile(filepath.Join(projectDir, "state"))
+		if err != nil {
+			return "", err
+		}
+		if bytes.HasPrefix(state, []byte("bug")) {
+			return vcs.BisectBad, nil
+		}
+		return vcs.BisectGood, nil
+	}
+	err = c.run("test_finding")
+	require.NoError(t, err)
+
+	f, err = finding.LoadFinding(projectDir, "test_finding", nil)
+	require.NoError(t, err)
+	require.Equal(t, commits[2], f.GitRevision.FirstBadCommit)
+}
+
+func TestBisect_ReproducesWithUserErrorID(t *testing.T) {
+	matchers := errorid.UserMatchers{{ID: "assertion_failure", Substrings: []string{"ASSERT failed"}}}
+	err := matchers.Validate()
+	require.NoError(t, err)
+
+	// The finding was classified via the user matcher by `cifuzz run`
+	f := &finding.Finding{
+		FuzzTest:    "my_fuzz_test",
+		InputData:   []byte("crash"),
+		Details:     "deadly signal",
+		Logs:        []string{"ASSERT failed: size > 0"},
+		MoreDetails: &finding.ErrorDetails{ID: "assertion_failure"},
+		StackTrace:  []*stacktrace.StackFrame{{Function: "parse", SourceFile: "src/parser.cpp", Line: 10}},
+	}
+	opts := &options{ErrorIDMatchers: matchers, Dedup: finding.DedupOptions{Frames: finding.DefaultDedupFrames}}
+	c := &bisectCmd{opts: opts, finding: f}
+
+	replayed := &finding.Finding{
+		Details:     "deadly signal",
+		Logs:        []string{"ASSERT failed: size > 0"},
+		MoreDetails: &finding.ErrorDetails{ID: "deadly_signal"},
+		StackTrace:  []*stacktrace.StackFrame{{Function: "parse", SourceFile: "src/parser.cpp", Line: 10}},
+	}
+	require.True(t, c.reproduces([]*finding.Finding{replayed}))
+
+	other := &finding.Finding{
+		Details:     "deadly signal",
+		MoreDetails: &finding.ErrorDetails{ID: "deadly_signal"},
+		StackTrace:  []*stacktrace.StackFrame{{Function: "parse", SourceFile: "src/parser.cpp", Line: 10}},
+	}
+	require.False(t, c.reproduces([]*finding.Finding{other}))
+}
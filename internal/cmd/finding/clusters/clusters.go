@@ -0,0 +1,150 @@
+package clusters
+
+import (
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"code-intelligence.com/cifuzz/internal/cmdutils"
+	"code-intelligence.com/cifuzz/internal/config"
+	"code-intelligence.com/cifuzz/pkg/finding"
+	"code-intelligence.com/cifuzz/pkg/log"
+	"code-intelligence.com/cifuzz/util/stringutil"
+)
+
+type options struct {
+	PrintJSON  bool   `mapstructure:"print-json"`
+	ProjectDir string `mapstructure:"project-dir"`
+	ConfigDir  string `mapstructure:"config-dir"`
+
+	NumFrames int
+	ShowAll   bool
+}
+
+func (opts *options) validate() error {
+	if opts.NumFrames < 0 {
+		msg := fmt.Sprintf("Invalid value %d for --frames, must not be negative", opts.NumFrames)
+		return cmdutils.WrapIncorrectUsageError(errors.New(msg))
+	}
+	return nil
+}
+
+type clustersCmd struct {
+	*cobra.Command
+	opts *options
+}
+
+func New() *cobra.Command {
+	return newWithOptions(&options{})
+}
+
+func newWithOptions(opts *options) *cobra.Command {
+	var bindFlags func()
+
+	cmd := &cobra.Command{
+		Use:   "clusters",
+		Short: "Group findings of all fuzz tests by root cause",
+		Long: `This command groups the findings of all fuzz tests by their root cause
+and prints one row per cluster with the findings which belong to it.
+
+The same bug is often found by several fuzz tests, which call the code
+under test via different harness functions. Unlike duplicates (see
+'cifuzz finding dedupe'), these findings have different stack traces.
+Findings are in the same cluster if they have the same error ID and
+the same top crashing frames (configurable via --frames), ignoring the
+frames of the fuzz test harness and of the libFuzzer, sanitizer and
+Jazzer runtimes. Findings without crashing frames, like most timeouts
+and leaks, are only in the same cluster as duplicates found by the
+same fuzz test.
+
+To change the status of all findings of a cluster, use
+'cifuzz finding set-status <name> <status> --cluster' with the name of
+one of the findings.
+
+By default, closed and suppressed findings are not included.
+`,
+		Args: cobra.NoArgs,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			// Bind viper keys to flags. We can't do this in the New
+			// function, because that would re-bind viper keys which
+			// were bound to the flags of other commands before.
+			bindFlags()
+			err := config.FindAndParseProjectConfig(opts)
+			if err != nil {
+				log.Errorf(err, "Failed to parse cifuzz.yaml: %v", err.Error())
+				return cmdutils.WrapSilentError(err)
+			}
+			return opts.validate()
+		},
+		RunE: func(c *cobra.Command, args []string) error {
+			cmd := clustersCmd{Command: c, opts: opts}
+			return cmd.run()
+		},
+	}
+
+	bindFlags = cmdutils.AddFlags(cmd,
+		cmdutils.AddPrintJSONFlag,
+		cmdutils.AddProjectDirFlag,
+	)
+	cmd.Flags().IntVar(&opts.NumFrames, "frames", finding.DefaultClusterFrames,
+		"Number of top crashing frames which findings must share to be in the same cluster.")
+	cmd.Flags().BoolVarP(&opts.ShowAll, "all", "a", false, "Include closed and suppressed findings.")
+
+	return cmd
+}
+
+func (c *clustersCmd) run() error {
+	findings, err := finding.ListClusteredFindings(c.opts.ProjectDir, c.opts.ShowAll)
+	if err != nil {
+		return err
+	}
+	// The findings are not saved again, so they can be enhanced with
+	// the error details shipped with cifuzz, like the severity
+	for _, f := range findings {
+		f.EnhanceWithLocalErrorDetails()
+	}
+	clusters := finding.ClusterFindings(findings, c.opts.NumFrames)
+
+	if c.opts.PrintJSON {
+		s, err := stringutil.ToJSONString(clusters)
+		if err != nil {
+			return err
+		}
+		_, _ = fmt.Fprintln(c.OutOrStdout(), s)
+		return nil
+	}
+
+	if len(clusters) == 0 {
+		log.Print("This project doesn't have any findings yet")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(c.OutOrStdout(), 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "Cluster\tError\tLocation\tFuzz Tests\tFindings")
+	for _, cluster := range clusters {
+		var names []string
+		for _, f := range cluster.Findings {
+			names = append(names, f.Name)
+		}
+		location := cluster.Location()
+		if location == "" {
+			location = "n/a"
+		}
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n",
+			cluster.ID,
+			cluster.ErrorID,
+			location,
+			strings.Join(cluster.FuzzTests(), ", "),
+			strings.Join(names, ", "))
+	}
+	err = w.Flush()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	log.Printf("%d findings in %d clusters", len(findings), len(clusters))
+	return nil
+}
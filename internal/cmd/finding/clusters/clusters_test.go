@@ -0,0 +1,53 @@
+package clusters
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"code-intelligence.com/cifuzz/internal/cmdutils"
+	"code-intelligence.com/cifuzz/internal/testutil"
+	"code-intelligence.com/cifuzz/internal/testutil/findingtest"
+	"code-intelligence.com/cifuzz/pkg/finding"
+	"code-intelligence.com/cifuzz/pkg/log"
+)
+
+var logOutput io.ReadWriter
+
+func TestMain(m *testing.M) {
+	logOutput = bytes.NewBuffer([]byte{})
+	log.Output = logOutput
+
+	m.Run()
+}
+
+func TestClustersCmd(t *testing.T) {
+	projectDir := testutil.BootstrapEmptyProject(t, "test-clusters-cmd-")
+	findingtest.SaveFinding(t, projectDir, "first_finding", "decode_fuzzer", "heap_buffer_overflow", "parse")
+	findingtest.SaveFinding(t, projectDir, "second_finding", "stream_fuzzer", "heap_buffer_overflow", "parse")
+	findingtest.SaveFinding(t, projectDir, "other_finding", "stream_fuzzer", "heap_use_after_free", "parse")
+	fixed := findingtest.SaveFinding(t, projectDir, "fixed_finding", "decode_fuzzer", "heap_buffer_overflow", "parse")
+	fixed.SetStatus(finding.StatusFixed)
+	err := fixed.Save(projectDir)
+	require.NoError(t, err)
+
+	opts := &options{ProjectDir: projectDir, ConfigDir: projectDir}
+	output, err := cmdutils.ExecuteCommand(t, newWithOptions(opts), os.Stdin)
+	require.NoError(t, err)
+	require.Regexp(t, `[0-9a-f]{8}  heap_buffer_overflow  parse \(src/parser.cpp\)  decode_fuzzer, stream_fuzzer  (first|second)_finding, (first|second)_finding\n`, output)
+	require.Regexp(t, `heap_use_after_free\s+parse \(src/parser.cpp\)\s+stream_fuzzer\s+other_finding`, output)
+	require.NotContains(t, output, "fixed_finding")
+
+	opts = &options{ProjectDir: projectDir, ConfigDir: projectDir}
+	output, err = cmdutils.ExecuteCommand(t, newWithOptions(opts), os.Stdin, "--json", "--all")
+	require.NoError(t, err)
+	var clusters []*finding.Cluster
+	err = json.Unmarshal([]byte(output), &clusters)
+	require.NoError(t, err)
+	require.Len(t, clusters, 2)
+	require.Len(t, clusters[0].Findings, 3)
+}
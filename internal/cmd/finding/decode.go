@@ -0,0 +1,216 @@
+package finding
+
+import (
+	"context"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/viper"
+
+	"code-intelligence.com/cifuzz/internal/build/fuzztest"
+	"code-intelligence.com/cifuzz/internal/cmdutils"
+	"code-intelligence.com/cifuzz/internal/config"
+	"code-intelligence.com/cifuzz/internal/replayer"
+	"code-intelligence.com/cifuzz/pkg/finding"
+	"code-intelligence.com/cifuzz/pkg/log"
+	"code-intelligence.com/cifuzz/pkg/runfiles"
+	"code-intelligence.com/cifuzz/util/fileutil"
+)
+
+// dataProviderHooksClass is the class of the Jazzer hooks which log the
+// calls of Jazzer's FuzzedDataProvider (tools/jazzer-hooks)
+const dataProviderHooksClass = "com.code_intelligence.cifuzz.DataProviderLoggingHooks"
+
+func (opts *options) validateDecode() error {
+	var err error
+
+	if opts.BuildSystem == "" {
+		opts.BuildSystem, err = config.DetermineBuildSystem(opts.ProjectDir)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = config.ValidateBuildSystem(opts.BuildSystem)
+	if err != nil {
+		log.Error(err)
+		return cmdutils.WrapSilentError(err)
+	}
+
+	// To build with other build systems, a build command must be provided
+	if opts.BuildSystem == config.BuildSystemOther && opts.BuildCommand == "" {
+		msg := "Flag \"build-command\" must be set when using build system type \"other\""
+		return cmdutils.WrapIncorrectUsageError(errors.New(msg))
+	}
+
+	return nil
+}
+
+// decodeAndSave determines the calls of the FuzzedDataProvider with
+// which the fuzz test consumes the crashing input of the finding and
+// stores them in the finding
+func (cmd *findingCmd) decodeAndSave(f *finding.Finding) error {
+	if f.FuzzTest == "" {
+		err := errors.Errorf("The fuzz test of finding %s is unknown", f.Name)
+		log.Error(err)
+		return cmdutils.WrapSilentError(err)
+	}
+
+	calls, err := cmd.decode(f)
+	if err != nil {
+		return err
+	}
+	if len(calls) == 0 {
+		err = errors.Errorf("The fuzz test %s didn't consume the crashing input via the FuzzedDataProvider "+
+			"(for C/C++ fuzz tests, the one shipped with cifuzz in include/fuzzer/FuzzedDataProvider.h)", f.FuzzTest)
+		log.Error(err)
+		return cmdutils.WrapSilentError(err)
+	}
+
+	// Load the finding again without the error details, because it's
+	// saved again
+	saved, err := finding.LoadFinding(cmd.opts.ProjectDir, f.Name, nil)
+	if err != nil {
+		return err
+	}
+	saved.SetDataProviderCalls(calls)
+	err = saved.Save(cmd.opts.ProjectDir)
+	if err != nil {
+		return err
+	}
+	f.SetDataProviderCalls(calls)
+	return nil
+}
+
+// buildAndDecode builds the fuzz test of the finding and executes it
+// with the crashing input, with the FuzzedDataProvider logging the
+// values which the fuzz test consumes
+func (cmd *findingCmd) buildAndDecode(f *finding.Finding) ([]string, error) {
+	tempDir, err := os.MkdirTemp("", "cifuzz-decode-")
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	defer fileutil.Cleanup(tempDir)
+
+	inputFile := filepath.Join(tempDir, "crashing-input")
+	input, err := f.ReadInput(cmd.opts.ProjectDir)
+	if err != nil {
+		return nil, err
+	}
+	err = os.WriteFile(inputFile, input, 0o644)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	var buildOutput io.Writer = io.Discard
+	if viper.GetBool("verbose") {
+		buildOutput = cmd.ErrOrStderr()
+	}
+
+	// For Java fuzz tests, the fuzz test of the finding can include the
+	// fuzz test method
+	fuzzTest, targetMethod := f.FuzzTest, ""
+	if cmd.isJava() {
+		if class, method, found := strings.Cut(f.FuzzTest, "::"); found {
+			fuzzTest, targetMethod = class, method
+		}
+	}
+
+	log.Infof("Building %s", fuzzTest)
+	buildOpts := &fuzztest.BuildOptions{
+		BuildSystem:  cmd.opts.BuildSystem,
+		BuildCommand: cmd.opts.BuildCommand,
+		CleanCommand: cmd.opts.CleanCommand,
+		NumBuildJobs: cmd.opts.NumBuildJobs,
+		ProjectDir:   cmd.opts.ProjectDir,
+		FuzzTest:     fuzzTest,
+		TempDir:      tempDir,
+		Stdout:       buildOutput,
+		Stderr:       buildOutput,
+	}
+	buildResult, err := fuzztest.Build(buildOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	var engineArgs []string
+	if cmd.isJava() {
+		// Jazzer's FuzzedDataProvider doesn't log its calls, so we
+		// compile hooks which log them and pass them to Jazzer
+		hooksDir, err := compileDataProviderHooks(tempDir, buildResult.RuntimeDeps)
+		if err != nil {
+			return nil, err
+		}
+		buildResult.RuntimeDeps = append(buildResult.RuntimeDeps, hooksDir)
+		engineArgs = append(engineArgs, "--custom_hooks="+dataProviderHooksClass)
+	}
+
+	log.Infof("Decoding the crashing input of finding %s", f.Name)
+	logFile := filepath.Join(tempDir, "data-provider-calls")
+	// The findings reported when replaying the input are not relevant
+	// here, we only need the calls which were logged before the crash
+	_, err = replayer.Replay(context.Background(), &replayer.Options{
+		BuildSystem:  cmd.opts.BuildSystem,
+		BuildResult:  buildResult,
+		FuzzTest:     buildOpts.FuzzTest,
+		TargetMethod: targetMethod,
+		ProjectDir:   cmd.opts.ProjectDir,
+		InputFiles:   []string{inputFile},
+		EngineArgs:   engineArgs,
+		EnvVars:      []string{finding.DataProviderLogEnvVar + "=" + logFile},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	exists, err := fileutil.Exists(logFile)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, nil
+	}
+	return finding.ReadDataProviderLog(logFile)
+}
+
+func (cmd *findingCmd) isJava() bool {
+	return cmd.opts.BuildSystem == config.BuildSystemMaven || cmd.opts.BuildSystem == config.BuildSystemGradle
+}
+
+// compileDataProviderHooks compiles the Jazzer hooks shipped with cifuzz
+// which log the calls of Jazzer's FuzzedDataProvider and returns the
+// directory containing the class files
+func compileDataProviderHooks(tempDir string, classPaths []string) (string, error) {
+	source, err := runfiles.Finder.DataProviderHooksSourcePath()
+	if err != nil {
+		return "", err
+	}
+	javaHome, err := runfiles.Finder.JavaHomePath()
+	if err != nil {
+		return "", err
+	}
+	javac := filepath.Join(javaHome, "bin", "javac")
+	if runtime.GOOS == "windows" {
+		javac = filepath.Join(javaHome, "bin", "javac.exe")
+	}
+
+	hooksDir := filepath.Join(tempDir, "hooks")
+	cmd := exec.Command(javac,
+		"-cp", strings.Join(classPaths, string(os.PathListSeparator)),
+		"-d", hooksDir,
+		source,
+	)
+	log.Debugf("Command: %s", cmd.String())
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		err = errors.Errorf("Failed to compile the data provider hooks: %v\n%s", err, out)
+		log.Error(err)
+		return "", cmdutils.WrapSilentError(err)
+	}
+	return hooksDir, nil
+}
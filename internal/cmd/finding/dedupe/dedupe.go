@@ -0,0 +1,118 @@
+package dedupe
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"code-intelligence.com/cifuzz/internal/cmdutils"
+	"code-intelligence.com/cifuzz/internal/config"
+	"code-intelligence.com/cifuzz/pkg/finding"
+	"code-intelligence.com/cifuzz/pkg/log"
+)
+
+type options struct {
+	ProjectDir string               `mapstructure:"project-dir"`
+	ConfigDir  string               `mapstructure:"config-dir"`
+	Dedup      finding.DedupOptions `mapstructure:"dedup"`
+	DryRun     bool
+}
+
+type dedupeCmd struct {
+	*cobra.Command
+	opts *options
+}
+
+func New() *cobra.Command {
+	return newWithOptions(&options{})
+}
+
+func newWithOptions(opts *options) *cobra.Command {
+	var bindFlags func()
+
+	cmd := &cobra.Command{
+		Use:   "dedupe",
+		Short: "Merge duplicate findings",
+		Long: `This command merges findings which have the same root cause.
+
+Two findings are considered duplicates if they were found by the same
+fuzz test and have the same error ID and the same normalized function
+names in the top in-project stack frames, ignoring frames of the
+libFuzzer, sanitizer and Jazzer runtimes. The number of frames which are
+compared can be configured via the "dedup" setting in cifuzz.yaml.
+Findings without in-project stack frames, like most timeouts and leaks,
+are only considered duplicates if they have the same stack trace and
+crashing input.
+
+Of each group of duplicates, the oldest finding is kept and the names
+of the other findings are recorded in it. The other findings are
+deleted.
+`,
+		Args: cobra.NoArgs,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			// Bind viper keys to flags. We can't do this in the New
+			// function, because that would re-bind viper keys which
+			// were bound to the flags of other commands before.
+			bindFlags()
+			err := config.FindAndParseProjectConfig(opts)
+			if err != nil {
+				log.Errorf(err, "Failed to parse cifuzz.yaml: %v", err.Error())
+				return cmdutils.WrapSilentError(err)
+			}
+			err = opts.Dedup.Validate()
+			if err != nil {
+				log.Error(err)
+				return cmdutils.WrapSilentError(err)
+			}
+			return nil
+		},
+		RunE: func(c *cobra.Command, args []string) error {
+			cmd := dedupeCmd{Command: c, opts: opts}
+			return cmd.run()
+		},
+	}
+
+	bindFlags = cmdutils.AddFlags(cmd,
+		cmdutils.AddProjectDirFlag,
+	)
+	cmd.Flags().BoolVar(&opts.DryRun, "dry-run", false, "Only print the duplicates, don't merge them.")
+
+	return cmd
+}
+
+func (c *dedupeCmd) run() error {
+	// Don't enhance the findings with error details, because the
+	// findings are saved again when they are merged
+	findings, err := finding.ListFindings(c.opts.ProjectDir, nil)
+	if err != nil {
+		return err
+	}
+
+	groups := finding.FindDuplicates(findings, &c.opts.Dedup)
+	if len(groups) == 0 {
+		log.Print("No duplicate findings found")
+		return nil
+	}
+
+	numDuplicates := 0
+	for _, group := range groups {
+		for _, d := range group.Duplicates {
+			_, _ = fmt.Fprintf(c.OutOrStdout(), "%s is a duplicate of %s\n", d.Name, group.Finding.Name)
+			numDuplicates++
+		}
+	}
+
+	if c.opts.DryRun {
+		return nil
+	}
+
+	for _, group := range groups {
+		err = group.Merge(c.opts.ProjectDir)
+		if err != nil {
+			return err
+		}
+	}
+
+	log.Successf("Merged %d duplicate findings into %d findings", numDuplicates, len(groups))
+	return nil
+}
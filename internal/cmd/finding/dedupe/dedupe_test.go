@@ -0,0 +1,61 @@
+package dedupe
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"code-intelligence.com/cifuzz/internal/cmdutils"
+	"code-intelligence.com/cifuzz/internal/testutil"
+	"code-intelligence.com/cifuzz/pkg/finding"
+	"code-intelligence.com/cifuzz/pkg/log"
+	"code-intelligence.com/cifuzz/pkg/parser/libfuzzer/stacktrace"
+)
+
+var logOutput io.ReadWriter
+
+func TestMain(m *testing.M) {
+	logOutput = bytes.NewBuffer([]byte{})
+	log.Output = logOutput
+
+	m.Run()
+}
+
+func TestDedupeCmd(t *testing.T) {
+	projectDir := testutil.BootstrapEmptyProject(t, "test-dedupe-cmd-")
+
+	for i, name := range []string{"first_finding", "second_finding"} {
+		f := &finding.Finding{
+			Name:        name,
+			CreatedAt:   time.Now().Add(time.Duration(i) * time.Minute),
+			MoreDetails: &finding.ErrorDetails{ID: "heap_buffer_overflow"},
+			StackTrace: []*stacktrace.StackFrame{
+				{Function: "parse", SourceFile: "src/parser.cpp", Line: uint32(10 + i)},
+			},
+		}
+		err := f.Save(projectDir)
+		require.NoError(t, err)
+	}
+
+	// A dry run doesn't change the findings
+	opts := &options{ProjectDir: projectDir, ConfigDir: projectDir}
+	output, err := cmdutils.ExecuteCommand(t, newWithOptions(opts), os.Stdin, "--dry-run")
+	require.NoError(t, err)
+	require.Contains(t, output, "second_finding is a duplicate of first_finding")
+	findings, err := finding.ListFindings(projectDir, nil)
+	require.NoError(t, err)
+	require.Len(t, findings, 2)
+
+	opts = &options{ProjectDir: projectDir, ConfigDir: projectDir}
+	_, err = cmdutils.ExecuteCommand(t, newWithOptions(opts), os.Stdin)
+	require.NoError(t, err)
+	findings, err = finding.ListFindings(projectDir, nil)
+	require.NoError(t, err)
+	require.Len(t, findings, 1)
+	require.Equal(t, "first_finding", findings[0].Name)
+	require.Equal(t, []string{"second_finding"}, findings[0].Duplicates)
+}
@@ -0,0 +1,210 @@
+package diff
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"code-intelligence.com/cifuzz/internal/cmdutils"
+	"code-intelligence.com/cifuzz/internal/config"
+	"code-intelligence.com/cifuzz/pkg/finding"
+	"code-intelligence.com/cifuzz/pkg/log"
+	"code-intelligence.com/cifuzz/pkg/vcs"
+	"code-intelligence.com/cifuzz/util/fileutil"
+	"code-intelligence.com/cifuzz/util/stringutil"
+)
+
+type options struct {
+	PrintJSON  bool                 `mapstructure:"print-json"`
+	ProjectDir string               `mapstructure:"project-dir"`
+	ConfigDir  string               `mapstructure:"config-dir"`
+	Dedup      finding.DedupOptions `mapstructure:"dedup"`
+
+	Base           string
+	FailOnSeverity string
+	MinReproRate   float64
+
+	minSeverity float32
+}
+
+func (opts *options) validate() error {
+	var err error
+
+	err = opts.Dedup.Validate()
+	if err != nil {
+		log.Error(err)
+		return cmdutils.WrapSilentError(err)
+	}
+
+	if opts.Base == "" {
+		return cmdutils.WrapIncorrectUsageError(errors.New("Flag --base must be set"))
+	}
+
+	if opts.FailOnSeverity != "" {
+		opts.minSeverity, err = finding.ParseSeverity(opts.FailOnSeverity)
+		if err != nil {
+			return cmdutils.WrapIncorrectUsageError(err)
+		}
+	}
+
+	if opts.MinReproRate < 0 || opts.MinReproRate > 1 {
+		msg := fmt.Sprintf("Invalid value %v for --min-repro-rate, must be between 0 and 1", opts.MinReproRate)
+		return cmdutils.WrapIncorrectUsageError(errors.New(msg))
+	}
+
+	return nil
+}
+
+type diffCmd struct {
+	*cobra.Command
+	opts *options
+}
+
+func New() *cobra.Command {
+	return newWithOptions(&options{})
+}
+
+func newWithOptions(opts *options) *cobra.Command {
+	var bindFlags func()
+
+	cmd := &cobra.Command{
+		Use:   "diff --base <snapshot|git revision>",
+		Short: "Compare the current findings to a base",
+		Long: `This command compares the current findings to the findings of a base
+and reports which findings are new, which are still present and which
+were fixed. This can be used to only fail pull request checks on
+findings which were introduced by the pull request.
+
+The base is either a snapshot file created by 'cifuzz finding snapshot'
+or a Git revision, in which case the findings committed in that
+revision are used. Findings are compared by their dedup key (see
+'cifuzz finding dedupe'), so that a finding which was found again under
+a different name is not reported as new.
+
+The command exits with a non-zero exit code if there are new findings.
+Use --fail-on-severity to only fail on new findings with at least the
+specified severity. New findings with an unknown severity always cause
+a failure. Use --min-repro-rate to not fail on flaky new findings, i.e.
+findings which reproduced in less than the specified fraction of the
+attempts to replay them after they were found. Findings are only
+replayed if --repro-attempts of 'cifuzz run' is set. Findings which
+were not replayed always cause a failure.
+`,
+		Args: cobra.NoArgs,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			// Bind viper keys to flags. We can't do this in the New
+			// function, because that would re-bind viper keys which
+			// were bound to the flags of other commands before.
+			bindFlags()
+			err := config.FindAndParseProjectConfig(opts)
+			if err != nil {
+				log.Errorf(err, "Failed to parse cifuzz.yaml: %v", err.Error())
+				return cmdutils.WrapSilentError(err)
+			}
+			return opts.validate()
+		},
+		RunE: func(c *cobra.Command, args []string) error {
+			cmd := diffCmd{Command: c, opts: opts}
+			return cmd.run()
+		},
+	}
+
+	bindFlags = cmdutils.AddFlags(cmd,
+		cmdutils.AddPrintJSONFlag,
+		cmdutils.AddProjectDirFlag,
+	)
+	cmd.Flags().StringVar(&opts.Base, "base", "", "Snapshot file or Git revision to compare the findings to.")
+	cmd.Flags().StringVar(&opts.FailOnSeverity, "fail-on-severity", "",
+		"Only fail on new findings with at least the specified severity score (0-10) or level (critical, high, medium, low).")
+	cmd.Flags().Float64Var(&opts.MinReproRate, "min-repro-rate", 0,
+		"Only fail on new findings which reproduced in at least the specified fraction (0-1) of the replay attempts.")
+
+	return cmd
+}
+
+func (c *diffCmd) run() error {
+	base, err := c.loadBase()
+	if err != nil {
+		return err
+	}
+
+	// The dedup keys of the current findings must be computed the same
+	// way as those of the base
+	head, err := finding.CreateSnapshot(c.opts.ProjectDir, base.DedupOptions())
+	if err != nil {
+		return err
+	}
+
+	diff := finding.DiffSnapshots(base, head)
+
+	if c.opts.PrintJSON {
+		s, err := stringutil.ToJSONString(diff)
+		if err != nil {
+			return err
+		}
+		_, _ = fmt.Fprintln(c.OutOrStdout(), s)
+	} else {
+		printEntries(c.OutOrStdout(), "New findings", diff.New)
+		printEntries(c.OutOrStdout(), "Still present findings", diff.StillPresent)
+		printEntries(c.OutOrStdout(), "Fixed findings", diff.Fixed)
+	}
+
+	numFailing := 0
+	for _, e := range diff.New {
+		if !e.ExceedsSeverity(c.opts.minSeverity) {
+			continue
+		}
+		if !e.MeetsReproRate(c.opts.MinReproRate) {
+			log.Warnf("Not failing on new finding %s because it's flaky (reproduction rate %.0f%%)", e.Name, *e.ReproRate*100)
+			continue
+		}
+		numFailing++
+	}
+	if numFailing > 0 {
+		err = errors.Errorf("Found %d new findings", numFailing)
+		if c.opts.FailOnSeverity != "" {
+			err = errors.Errorf("Found %d new findings with severity %s or higher", numFailing, c.opts.FailOnSeverity)
+		}
+		log.Error(err)
+		return cmdutils.WrapSilentError(err)
+	}
+
+	log.Successf("No new findings (%d still present, %d fixed)", len(diff.StillPresent), len(diff.Fixed))
+	return nil
+}
+
+func (c *diffCmd) loadBase() (*finding.Snapshot, error) {
+	exists, err := fileutil.Exists(c.opts.Base)
+	if err != nil {
+		return nil, err
+	}
+	if exists && !fileutil.IsDir(c.opts.Base) {
+		return finding.LoadSnapshot(c.opts.Base)
+	}
+
+	if !vcs.GitRevisionExists(c.opts.ProjectDir, c.opts.Base) {
+		err := errors.Errorf("Base %q is neither a snapshot file nor a Git revision", c.opts.Base)
+		log.Error(err)
+		return nil, cmdutils.WrapSilentError(err)
+	}
+	return finding.CreateSnapshotAtRevision(c.opts.ProjectDir, c.opts.Base, &c.opts.Dedup)
+}
+
+func printEntries(w io.Writer, title string, entries []*finding.SnapshotEntry) {
+	_, _ = fmt.Fprintf(w, "%s (%d):\n", title, len(entries))
+	for _, e := range entries {
+		_, _ = fmt.Fprintf(w, "  [%s] %s", e.Name, e.Description)
+		if e.FuzzTest != "" {
+			_, _ = fmt.Fprintf(w, " (fuzz test: %s)", e.FuzzTest)
+		}
+		if e.Severity != nil {
+			_, _ = fmt.Fprintf(w, " (severity: %.1f)", e.Severity.Score)
+		}
+		if e.ReproRate != nil && *e.ReproRate < 1 {
+			_, _ = fmt.Fprintf(w, " (flaky, reproduction rate: %.0f%%)", *e.ReproRate*100)
+		}
+		_, _ = fmt.Fprintln(w)
+	}
+}
@@ -0,0 +1,178 @@
+package diff
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"code-intelligence.com/cifuzz/internal/cmdutils"
+	"code-intelligence.com/cifuzz/internal/testutil"
+	"code-intelligence.com/cifuzz/internal/testutil/findingtest"
+	"code-intelligence.com/cifuzz/pkg/finding"
+	"code-intelligence.com/cifuzz/pkg/log"
+	"code-intelligence.com/cifuzz/util/stringutil"
+)
+
+var logOutput io.ReadWriter
+
+func TestMain(m *testing.M) {
+	logOutput = bytes.NewBuffer([]byte{})
+	log.Output = logOutput
+
+	m.Run()
+}
+
+func TestDiffCmd_SnapshotBase(t *testing.T) {
+	projectDir := testutil.BootstrapEmptyProject(t, "test-diff-cmd-")
+
+	findingtest.SaveFinding(t, projectDir, "fixed_finding", "", "heap_buffer_overflow", "parse")
+	findingtest.SaveFinding(t, projectDir, "present_finding", "", "heap_buffer_overflow", "lex")
+	base, err := finding.CreateSnapshot(projectDir, &finding.DedupOptions{Frames: finding.DefaultDedupFrames})
+	require.NoError(t, err)
+	s, err := stringutil.ToJSONString(base)
+	require.NoError(t, err)
+	basePath := filepath.Join(projectDir, "base.json")
+	err = os.WriteFile(basePath, []byte(s), 0o644)
+	require.NoError(t, err)
+
+	// Without new findings, the command succeeds
+	err = os.RemoveAll(filepath.Join(projectDir, ".cifuzz-findings", "fixed_finding"))
+	require.NoError(t, err)
+	opts := &options{ProjectDir: projectDir, ConfigDir: projectDir}
+	output, err := cmdutils.ExecuteCommand(t, newWithOptions(opts), os.Stdin, "--base", basePath)
+	require.NoError(t, err)
+	require.Contains(t, output, "New findings (0)")
+	require.Contains(t, output, "Still present findings (1):\n  [present_finding]")
+	require.Contains(t, output, "Fixed findings (1):\n  [fixed_finding]")
+
+	// A new finding (with severity 4.0) makes the command fail...
+	findingtest.SaveFinding(t, projectDir, "new_finding", "", "memory_leak", "alloc")
+	opts = &options{ProjectDir: projectDir, ConfigDir: projectDir}
+	output, err = cmdutils.ExecuteCommand(t, newWithOptions(opts), os.Stdin, "--base", basePath, "--json")
+	require.Error(t, err)
+	// The output is followed by the error printed by cobra
+	var diff finding.Diff
+	err = json.NewDecoder(strings.NewReader(output)).Decode(&diff)
+	require.NoError(t, err)
+	require.Len(t, diff.New, 1)
+	require.Equal(t, "new_finding", diff.New[0].Name)
+
+	// ... unless it's below the severity threshold
+	opts = &options{ProjectDir: projectDir, ConfigDir: projectDir}
+	_, err = cmdutils.ExecuteCommand(t, newWithOptions(opts), os.Stdin, "--base", basePath, "--fail-on-severity", "medium")
+	require.Error(t, err)
+	opts = &options{ProjectDir: projectDir, ConfigDir: projectDir}
+	_, err = cmdutils.ExecuteCommand(t, newWithOptions(opts), os.Stdin, "--base", basePath, "--fail-on-severity", "high")
+	require.NoError(t, err)
+}
+
+func TestDiffCmd_FindingsWithoutProjectFrames(t *testing.T) {
+	projectDir := testutil.BootstrapEmptyProject(t, "test-diff-cmd-")
+
+	saveTimeout := func(name, fuzzTest string) {
+		f := &finding.Finding{
+			Name:        name,
+			FuzzTest:    fuzzTest,
+			MoreDetails: &finding.ErrorDetails{ID: "timeout"},
+			InputData:   []byte("A"),
+		}
+		err := f.Save(projectDir)
+		require.NoError(t, err)
+	}
+	saveTimeout("old_timeout", "decode_fuzzer")
+	base, err := finding.CreateSnapshot(projectDir, &finding.DedupOptions{Frames: finding.DefaultDedupFrames})
+	require.NoError(t, err)
+	s, err := stringutil.ToJSONString(base)
+	require.NoError(t, err)
+	basePath := filepath.Join(projectDir, "base.json")
+	err = os.WriteFile(basePath, []byte(s), 0o644)
+	require.NoError(t, err)
+
+	// A timeout in another fuzz test is a new finding, even though the
+	// base contains a timeout with the same input
+	saveTimeout("new_timeout", "stream_fuzzer")
+	opts := &options{ProjectDir: projectDir, ConfigDir: projectDir}
+	output, err := cmdutils.ExecuteCommand(t, newWithOptions(opts), os.Stdin, "--base", basePath)
+	require.Error(t, err)
+	require.Contains(t, output, "New findings (1):\n  [new_timeout]")
+	require.Contains(t, output, "Still present findings (1):\n  [old_timeout]")
+}
+
+func TestDiffCmd_MinReproRate(t *testing.T) {
+	projectDir := testutil.BootstrapEmptyProject(t, "test-diff-cmd-")
+	base, err := finding.CreateSnapshot(projectDir, &finding.DedupOptions{Frames: finding.DefaultDedupFrames})
+	require.NoError(t, err)
+	s, err := stringutil.ToJSONString(base)
+	require.NoError(t, err)
+	basePath := filepath.Join(projectDir, "base.json")
+	err = os.WriteFile(basePath, []byte(s), 0o644)
+	require.NoError(t, err)
+
+	// A new finding which reproduced in 1 of 4 attempts
+	f := &finding.Finding{
+		Name:        "flaky_finding",
+		MoreDetails: &finding.ErrorDetails{ID: "data_race"},
+		Reproducibility: finding.NewReproducibility([]*finding.ReproAttempt{
+			{Reproduced: true}, {}, {}, {},
+		}),
+	}
+	err = f.Save(projectDir)
+	require.NoError(t, err)
+
+	opts := &options{ProjectDir: projectDir, ConfigDir: projectDir}
+	output, err := cmdutils.ExecuteCommand(t, newWithOptions(opts), os.Stdin, "--base", basePath)
+	require.Error(t, err)
+	require.Contains(t, output, "[flaky_finding]  (severity: 7.0) (flaky, reproduction rate: 25%)")
+
+	opts = &options{ProjectDir: projectDir, ConfigDir: projectDir}
+	_, err = cmdutils.ExecuteCommand(t, newWithOptions(opts), os.Stdin, "--base", basePath, "--min-repro-rate", "0.2")
+	require.Error(t, err)
+
+	opts = &options{ProjectDir: projectDir, ConfigDir: projectDir}
+	_, err = cmdutils.ExecuteCommand(t, newWithOptions(opts), os.Stdin, "--base", basePath, "--min-repro-rate", "0.5")
+	require.NoError(t, err)
+
+	opts = &options{ProjectDir: projectDir, ConfigDir: projectDir}
+	_, err = cmdutils.ExecuteCommand(t, newWithOptions(opts), os.Stdin, "--base", basePath, "--min-repro-rate", "50")
+	require.Error(t, err)
+}
+
+func TestDiffCmd_GitRevisionBase(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not found")
+	}
+	projectDir := testutil.BootstrapEmptyProject(t, "test-diff-cmd-")
+
+	testutil.InitGitRepo(t, projectDir)
+	findingtest.SaveFinding(t, projectDir, "old_finding", "", "heap_buffer_overflow", "parse")
+	testutil.RunGit(t, projectDir, "add", ".")
+	testutil.RunGit(t, projectDir, "commit", "-m", "Add finding")
+
+	opts := &options{ProjectDir: projectDir, ConfigDir: projectDir}
+	_, err := cmdutils.ExecuteCommand(t, newWithOptions(opts), os.Stdin, "--base", "HEAD")
+	require.NoError(t, err)
+
+	// The same bug found under a different name is not new
+	findingtest.SaveFinding(t, projectDir, "renamed_finding", "", "heap_buffer_overflow", "parse")
+	opts = &options{ProjectDir: projectDir, ConfigDir: projectDir}
+	_, err = cmdutils.ExecuteCommand(t, newWithOptions(opts), os.Stdin, "--base", "HEAD")
+	require.NoError(t, err)
+
+	findingtest.SaveFinding(t, projectDir, "new_finding", "", "heap_buffer_overflow", "lex")
+	opts = &options{ProjectDir: projectDir, ConfigDir: projectDir}
+	output, err := cmdutils.ExecuteCommand(t, newWithOptions(opts), os.Stdin, "--base", "HEAD")
+	require.Error(t, err)
+	require.Contains(t, output, "New findings (1):\n  [new_finding]")
+
+	opts = &options{ProjectDir: projectDir, ConfigDir: projectDir}
+	_, err = cmdutils.ExecuteCommand(t, newWithOptions(opts), os.Stdin, "--base", "no-such-revision")
+	require.Error(t, err)
+	testutil.CheckOutput(t, logOutput, "neither a snapshot file nor a Git revision")
+}
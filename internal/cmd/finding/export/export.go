@@ -0,0 +1,529 @@
+package export
+
+import (
+	"bytes"
+	"crypto/sha256"
+	_ "embed"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"code-intelligence.com/cifuzz/internal/cmdutils"
+	"code-intelligence.com/cifuzz/internal/completion"
+	"code-intelligence.com/cifuzz/internal/config"
+	"code-intelligence.com/cifuzz/pkg/finding"
+	"code-intelligence.com/cifuzz/pkg/log"
+	"code-intelligence.com/cifuzz/util/fileutil"
+	"code-intelligence.com/cifuzz/util/sliceutil"
+	"code-intelligence.com/cifuzz/util/stringutil"
+)
+
+const (
+	IssueFormatGitHub   = "github"
+	IssueFormatGitLab   = "gitlab"
+	IssueFormatJiraJSON = "jira-json"
+)
+
+var validIssueFormats = []string{IssueFormatGitHub, IssueFormatGitLab, IssueFormatJiraJSON}
+
+// The prefix of the dedup marker of the issues, which is added to the
+// body and the labels of each issue
+const markerPrefix = "cifuzz-finding-"
+
+//go:embed issue.md.tmpl
+var markdownTemplate string
+
+//go:embed issue.jira.tmpl
+var jiraTemplate string
+
+type options struct {
+	ProjectDir string                `mapstructure:"project-dir"`
+	ConfigDir  string                `mapstructure:"config-dir"`
+	Dedup      finding.DedupOptions  `mapstructure:"dedup"`
+	Redact     finding.RedactOptions `mapstructure:"redact"`
+
+	IssueFormat  string
+	TemplatePath string
+	OutputDir    string
+	JiraProject  string
+	ShowAll      bool
+}
+
+func (opts *options) validate() error {
+	if !sliceutil.Contains(validIssueFormats, opts.IssueFormat) {
+		msg := fmt.Sprintf("Invalid value %q for --issue-format, valid values are: %s",
+			opts.IssueFormat, strings.Join(validIssueFormats, ", "))
+		return cmdutils.WrapIncorrectUsageError(errors.New(msg))
+	}
+
+	if opts.JiraProject != "" && opts.IssueFormat != IssueFormatJiraJSON {
+		msg := fmt.Sprintf("Flag \"jira-project\" can only be used with --issue-format=%s", IssueFormatJiraJSON)
+		return cmdutils.WrapIncorrectUsageError(errors.New(msg))
+	}
+
+	err := opts.Dedup.Validate()
+	if err != nil {
+		log.Error(err)
+		return cmdutils.WrapSilentError(err)
+	}
+
+	err = opts.Redact.Validate()
+	if err != nil {
+		log.Error(err)
+		return cmdutils.WrapSilentError(err)
+	}
+
+	return nil
+}
+
+type exportCmd struct {
+	*cobra.Command
+	opts *options
+}
+
+// issueData is the data which is passed to the issue templates
+type issueData struct {
+	*finding.Finding
+	Description   string
+	Location      string
+	Severity      string
+	SeverityLevel string
+	// The path of the crashing input which should be attached to the
+	// issue
+	InputPath         string
+	ReproductionSteps []*reproductionStep
+	// Identifies the bug in the issue tracker. It's derived from the
+	// dedup key, so duplicates of a finding get the same marker.
+	Marker string
+}
+
+type reproductionStep struct {
+	Text    string
+	Command string
+}
+
+type issue struct {
+	Title  string
+	Body   string
+	Labels []string
+	Marker string
+}
+
+func New() *cobra.Command {
+	return newWithOptions(&options{})
+}
+
+func newWithOptions(opts *options) *cobra.Command {
+	var bindFlags func()
+
+	cmd := &cobra.Command{
+		Use:   "export [name]... --issue-format <format>",
+		Short: "Export findings as issue tracker tickets",
+		Long: `This command renders findings as issues for GitHub, GitLab or Jira.
+Each issue contains a title, a description, the stack trace, steps to
+reproduce the finding, severity labels and the path of the crashing
+input, which should be attached to the issue.
+
+The issues are printed as a JSON array in the format expected by the
+REST API of the issue tracker:
+
+  github     {"title", "body", "labels"}
+  gitlab     {"title", "description", "labels"}
+  jira-json  {"fields": {"summary", "description", "labels", ...}}
+
+If --output-dir is specified, each issue is written to a separate file
+instead, together with a copy of the crashing input.
+
+By default, all open findings which are not suppressed are exported.
+Duplicates of a finding (see 'cifuzz finding dedupe') are exported as
+a single issue. Each issue contains a marker which is derived from the
+dedup key of the finding, as a label and in the body. Use it to check
+if an issue was already created before creating it again. Re-exporting
+to the same output directory overwrites the existing files instead of
+creating new ones.
+
+Sensitive data like access tokens, email addresses and home directories
+is redacted from the logs, details and stack traces of the findings, as
+configured via the "redact" setting in cifuzz.yaml. Use
+'cifuzz finding redact' to check what is redacted.
+
+The body is rendered from a Go template (see https://pkg.go.dev/text/template),
+which can be replaced via --template. A custom template can also
+redefine the title via {{define "title"}}...{{end}}.
+`,
+		ValidArgsFunction: completion.ValidFindings,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			// Bind viper keys to flags. We can't do this in the New
+			// function, because that would re-bind viper keys which
+			// were bound to the flags of other commands before.
+			bindFlags()
+			err := config.FindAndParseProjectConfig(opts)
+			if err != nil {
+				log.Errorf(err, "Failed to parse cifuzz.yaml: %v", err.Error())
+				return cmdutils.WrapSilentError(err)
+			}
+			return opts.validate()
+		},
+		RunE: func(c *cobra.Command, args []string) error {
+			cmd := exportCmd{Command: c, opts: opts}
+			return cmd.run(args)
+		},
+	}
+
+	bindFlags = cmdutils.AddFlags(cmd,
+		cmdutils.AddProjectDirFlag,
+	)
+	cmd.Flags().StringVar(&opts.IssueFormat, "issue-format", "",
+		fmt.Sprintf("Format of the issues (%s).", strings.Join(validIssueFormats, ", ")))
+	cmd.Flags().StringVar(&opts.TemplatePath, "template", "", "Path of a custom Go template for the issue body.")
+	cmd.Flags().StringVarP(&opts.OutputDir, "output-dir", "o", "",
+		"Write each issue and its crashing input to a separate file in this directory.")
+	cmd.Flags().StringVar(&opts.JiraProject, "jira-project", "", "Key of the Jira project of the issues.")
+	cmd.Flags().BoolVarP(&opts.ShowAll, "all", "a", false, "Export closed findings (fixed, ignored, wontfix) as well.")
+	err := cmd.MarkFlagRequired("issue-format")
+	if err != nil {
+		panic(err)
+	}
+
+	return cmd
+}
+
+func (c *exportCmd) run(names []string) error {
+	findings, err := c.findings(names)
+	if err != nil {
+		return err
+	}
+	if len(findings) == 0 {
+		log.Print("There are no findings to export")
+		return nil
+	}
+
+	t, err := c.template()
+	if err != nil {
+		return err
+	}
+
+	if c.opts.OutputDir != "" {
+		err = os.MkdirAll(c.opts.OutputDir, 0o755)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+	}
+
+	var issues []*issue
+	seen := make(map[string]bool)
+	for _, f := range findings {
+		data, err := c.newIssueData(f)
+		if err != nil {
+			return err
+		}
+		// Duplicates of a finding are exported as a single issue
+		if seen[data.Marker] {
+			log.Debugf("Skipping finding %s, it's a duplicate of another exported finding", f.Name)
+			continue
+		}
+		seen[data.Marker] = true
+
+		i, err := render(t, data)
+		if err != nil {
+			return err
+		}
+		issues = append(issues, i)
+	}
+
+	if c.opts.OutputDir == "" {
+		var payloads []interface{}
+		for _, i := range issues {
+			payloads = append(payloads, c.payload(i))
+		}
+		s, err := stringutil.ToJSONString(payloads)
+		if err != nil {
+			return err
+		}
+		_, _ = fmt.Fprintln(c.OutOrStdout(), s)
+		return nil
+	}
+
+	numUpdated := 0
+	for _, i := range issues {
+		path := filepath.Join(c.opts.OutputDir, i.Marker+".json")
+		exists, err := fileutil.Exists(path)
+		if err != nil {
+			return err
+		}
+		if exists {
+			numUpdated++
+		}
+		s, err := stringutil.ToJSONString(c.payload(i))
+		if err != nil {
+			return err
+		}
+		err = os.WriteFile(path, []byte(s+"\n"), 0o644)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+	}
+	log.Successf("Exported %d issues to %s (%d of them were exported before and have been updated)",
+		len(issues), fileutil.PrettifyPath(c.opts.OutputDir), numUpdated)
+	return nil
+}
+
+// findings returns the findings with the given names or, if no names
+// are given, all open findings which are not suppressed
+func (c *exportCmd) findings(names []string) ([]*finding.Finding, error) {
+	findings, err := c.loadFindings(names)
+	if err != nil {
+		return nil, err
+	}
+	// The error details shipped with cifuzz are used for the severity
+	// and the description of the issues
+	for _, f := range findings {
+		f.EnhanceWithLocalErrorDetails()
+	}
+	return findings, nil
+}
+
+func (c *exportCmd) loadFindings(names []string) ([]*finding.Finding, error) {
+	if len(names) > 0 {
+		var findings []*finding.Finding
+		for _, name := range names {
+			f, err := finding.LoadFinding(c.opts.ProjectDir, name, nil)
+			if finding.IsNotExistError(err) {
+				log.Errorf(err, "Finding %s does not exist", name)
+				return nil, cmdutils.WrapSilentError(err)
+			}
+			if err != nil {
+				return nil, err
+			}
+			findings = append(findings, f)
+		}
+		return findings, nil
+	}
+
+	findings, err := finding.ListFindings(c.opts.ProjectDir, nil)
+	if err != nil {
+		return nil, err
+	}
+	suppressions, err := finding.LoadSuppressions(c.opts.ProjectDir)
+	if err != nil {
+		return nil, err
+	}
+	var result []*finding.Finding
+	for _, f := range findings {
+		if suppressions.Match(f) != nil {
+			continue
+		}
+		if f.GetStatus().IsClosed() && !c.opts.ShowAll {
+			continue
+		}
+		result = append(result, f)
+	}
+	// Export the oldest finding of duplicates, like
+	// `cifuzz finding dedupe` keeps the oldest one
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].CreatedAt.Before(result[j].CreatedAt)
+	})
+	return result, nil
+}
+
+// template parses the built-in template of the issue format and, if
+// specified, the custom template, which replaces the body and can
+// redefine the title
+func (c *exportCmd) template() (*template.Template, error) {
+	builtin := markdownTemplate
+	if c.opts.IssueFormat == IssueFormatJiraJSON {
+		builtin = jiraTemplate
+	}
+	t, err := template.New("body").Funcs(templateFuncs).Parse(builtin)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if c.opts.TemplatePath == "" {
+		return t, nil
+	}
+
+	custom, err := os.ReadFile(c.opts.TemplatePath)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	t, err = t.Parse(string(custom))
+	if err != nil {
+		err = errors.Wrapf(err, "Failed to parse template %s", c.opts.TemplatePath)
+		log.Error(err)
+		return nil, cmdutils.WrapSilentError(err)
+	}
+	return t, nil
+}
+
+var templateFuncs = template.FuncMap{
+	"join": strings.Join,
+	"date": func(t time.Time) string {
+		return t.Format("2006-01-02 15:04:05 MST")
+	},
+	"inc": func(i int) int {
+		return i + 1
+	},
+}
+
+func (c *exportCmd) newIssueData(f *finding.Finding) (*issueData, error) {
+	// The marker is derived from the original finding, so that it
+	// doesn't change when the redaction rules are changed
+	marker := Marker(c.opts.Dedup.Key(f))
+	f, _ = c.opts.Redact.Redact(f)
+
+	data := &issueData{
+		Finding:     f,
+		Description: f.ShortDescriptionColumns()[0],
+		Severity:    "n/a",
+		Marker:      marker,
+	}
+	if len(f.StackTrace) > 0 {
+		data.Location = fmt.Sprintf("%s (%s)", f.StackTrace[0].Function, f.StackTrace[0].Location())
+	}
+	if f.MoreDetails != nil && f.MoreDetails.Severity != nil {
+		data.Severity = fmt.Sprintf("%.1f", f.MoreDetails.Severity.Score)
+		data.SeverityLevel = strings.ToLower(string(f.MoreDetails.Severity.Level))
+	}
+
+	inputPath, err := f.InputPath(c.opts.ProjectDir)
+	if err != nil {
+		return nil, err
+	}
+	if c.opts.OutputDir != "" {
+		// Copy the crashing input to the output directory, so that
+		// all files which belong to the issue are in one place
+		input, err := os.ReadFile(inputPath)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		inputPath = filepath.Join(c.opts.OutputDir, data.Marker+"-crashing-input")
+		err = os.WriteFile(inputPath, input, 0o644)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+	} else {
+		inputPath, err = filepath.Rel(c.opts.ProjectDir, inputPath)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+	}
+	data.InputPath = filepath.ToSlash(inputPath)
+
+	if f.GitRevision != nil && f.GitRevision.Commit != "" {
+		data.ReproductionSteps = append(data.ReproductionSteps, &reproductionStep{
+			Text:    "Check out the commit in which the finding was found",
+			Command: "git checkout " + f.GitRevision.Commit,
+		})
+	}
+	data.ReproductionSteps = append(data.ReproductionSteps,
+		&reproductionStep{
+			Text: "Download the attached crashing input",
+		},
+		&reproductionStep{
+			Text:    "Execute the fuzz test with the crashing input",
+			Command: fmt.Sprintf("cifuzz finding import %s --fuzz-test %s", filepath.Base(inputPath), f.FuzzTest),
+		},
+		&reproductionStep{
+			Text:    "Debug the imported finding",
+			Command: "cifuzz debug <finding name>",
+		},
+	)
+
+	return data, nil
+}
+
+// Marker returns the dedup marker of an issue for the finding with the
+// given dedup key
+func Marker(dedupKey string) string {
+	hash := sha256.Sum256([]byte(dedupKey))
+	return markerPrefix + hex.EncodeToString(hash[:])[:16]
+}
+
+func render(t *template.Template, data *issueData) (*issue, error) {
+	var title, body bytes.Buffer
+	err := t.ExecuteTemplate(&title, "title", data)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	err = t.ExecuteTemplate(&body, "body", data)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	labels := []string{"cifuzz"}
+	if data.SeverityLevel != "" {
+		labels = append(labels, "severity:"+data.SeverityLevel)
+	}
+	labels = append(labels, data.Marker)
+
+	return &issue{
+		Title:  strings.TrimSpace(title.String()),
+		Body:   strings.TrimSpace(body.String()) + "\n",
+		Labels: labels,
+		Marker: data.Marker,
+	}, nil
+}
+
+// The issue formats expected by the REST APIs of the issue trackers
+type gitHubIssue struct {
+	Title  string   `json:"title"`
+	Body   string   `json:"body"`
+	Labels []string `json:"labels"`
+}
+
+type gitLabIssue struct {
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	// GitLab expects a comma-separated list of labels
+	Labels string `json:"labels"`
+}
+
+type jiraIssue struct {
+	Fields *jiraFields `json:"fields"`
+}
+
+type jiraFields struct {
+	Project     *jiraKey  `json:"project,omitempty"`
+	Summary     string    `json:"summary"`
+	Description string    `json:"description"`
+	IssueType   *jiraName `json:"issuetype"`
+	Labels      []string  `json:"labels"`
+}
+
+type jiraKey struct {
+	Key string `json:"key"`
+}
+
+type jiraName struct {
+	Name string `json:"name"`
+}
+
+// payload returns the issue in the format expected by the REST API of
+// the issue tracker
+func (c *exportCmd) payload(i *issue) interface{} {
+	switch c.opts.IssueFormat {
+	case IssueFormatGitLab:
+		return &gitLabIssue{Title: i.Title, Description: i.Body, Labels: strings.Join(i.Labels, ",")}
+	case IssueFormatJiraJSON:
+		fields := &jiraFields{
+			Summary:     i.Title,
+			Description: i.Body,
+			IssueType:   &jiraName{Name: "Bug"},
+			Labels:      i.Labels,
+		}
+		if c.opts.JiraProject != "" {
+			fields.Project = &jiraKey{Key: c.opts.JiraProject}
+		}
+		return &jiraIssue{Fields: fields}
+	default:
+		return &gitHubIssue{Title: i.Title, Body: i.Body, Labels: i.Labels}
+	}
+}
@@ -0,0 +1,195 @@
+package export
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"code-intelligence.com/cifuzz/internal/cmdutils"
+	"code-intelligence.com/cifuzz/internal/testutil"
+	"code-intelligence.com/cifuzz/pkg/finding"
+	"code-intelligence.com/cifuzz/pkg/log"
+	"code-intelligence.com/cifuzz/pkg/parser/libfuzzer/stacktrace"
+)
+
+var logOutput io.ReadWriter
+
+func TestMain(m *testing.M) {
+	logOutput = bytes.NewBuffer([]byte{})
+	log.Output = logOutput
+
+	m.Run()
+}
+
+func saveFindings(t *testing.T, projectDir string) {
+	for i, name := range []string{"first_finding", "duplicate_finding", "other_finding"} {
+		function := "parse"
+		if name == "other_finding" {
+			function = "lex"
+		}
+		f := &finding.Finding{
+			Name:        name,
+			Type:        finding.ErrorTypeCrash,
+			Details:     "heap-buffer-overflow on address 0x1234",
+			FuzzTest:    "my_fuzz_test",
+			InputData:   []byte("crash"),
+			CreatedAt:   time.Date(2023, 1, 1, 0, i, 0, 0, time.UTC),
+			MoreDetails: &finding.ErrorDetails{ID: "heap_buffer_overflow"},
+			GitRevision: &finding.GitRevision{Commit: "abc123"},
+			StackTrace: []*stacktrace.StackFrame{
+				{Function: function, SourceFile: "src/parser.cpp", Line: uint32(10 + i), FrameNumber: 0},
+			},
+		}
+		err := f.Save(projectDir)
+		require.NoError(t, err)
+	}
+}
+
+func TestExport_GitHub(t *testing.T) {
+	projectDir := testutil.BootstrapEmptyProject(t, "test-export-")
+	saveFindings(t, projectDir)
+
+	opts := &options{ProjectDir: projectDir, ConfigDir: projectDir}
+	output, err := cmdutils.ExecuteCommand(t, newWithOptions(opts), os.Stdin, "--issue-format", "github")
+	require.NoError(t, err)
+
+	var issues []*gitHubIssue
+	err = json.Unmarshal([]byte(output), &issues)
+	require.NoError(t, err, output)
+	// The duplicate finding is exported as a single issue
+	require.Len(t, issues, 2)
+
+	issue := issues[0]
+	require.Equal(t, "heap buffer overflow in parse (src/parser.cpp:10)", issue.Title)
+	require.Contains(t, issue.Body, "| Finding | `first_finding` |")
+	require.Contains(t, issue.Body, "| Severity | 9.0 (critical) |")
+	require.Contains(t, issue.Body, "#0 parse src/parser.cpp:10")
+	require.Contains(t, issue.Body, "1. Check out the commit in which the finding was found: `git checkout abc123`")
+	require.Contains(t, issue.Body, "`cifuzz finding import crashing-input --fuzz-test my_fuzz_test`")
+	require.Contains(t, issue.Body, "The crashing input is attached as `.cifuzz-findings/first_finding/crashing-input`.")
+	require.Len(t, issue.Labels, 3)
+	require.Equal(t, []string{"cifuzz", "severity:critical"}, issue.Labels[:2])
+	marker := issue.Labels[2]
+	require.Regexp(t, `^cifuzz-finding-[0-9a-f]{16}$`, marker)
+	require.Contains(t, issue.Body, "<!-- "+marker+" -->")
+
+	require.Equal(t, "heap buffer overflow in lex (src/parser.cpp:12)", issues[1].Title)
+	require.NotEqual(t, marker, issues[1].Labels[2])
+}
+
+func TestExport_JiraOutputDir(t *testing.T) {
+	projectDir := testutil.BootstrapEmptyProject(t, "test-export-")
+	saveFindings(t, projectDir)
+	outputDir := filepath.Join(projectDir, "issues")
+
+	for i := 0; i < 2; i++ {
+		opts := &options{ProjectDir: projectDir, ConfigDir: projectDir}
+		_, err := cmdutils.ExecuteCommand(t, newWithOptions(opts), os.Stdin,
+			"first_finding", "--issue-format", "jira-json", "--jira-project", "SEC", "--output-dir", outputDir)
+		require.NoError(t, err)
+	}
+	// Re-exporting overwrites the existing files
+	testutil.CheckOutput(t, logOutput, "Exported 1 issues", "1 of them were exported before")
+
+	entries, err := os.ReadDir(outputDir)
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	var issuePath string
+	for _, entry := range entries {
+		if filepath.Ext(entry.Name()) == ".json" {
+			issuePath = filepath.Join(outputDir, entry.Name())
+		}
+	}
+	require.NotEmpty(t, issuePath)
+
+	content, err := os.ReadFile(issuePath)
+	require.NoError(t, err)
+	var issue *jiraIssue
+	err = json.Unmarshal(content, &issue)
+	require.NoError(t, err)
+	require.Equal(t, "SEC", issue.Fields.Project.Key)
+	require.Equal(t, "Bug", issue.Fields.IssueType.Name)
+	require.Contains(t, issue.Fields.Description, "h2. Stack Trace")
+	require.Contains(t, issue.Fields.Description, "# Check out the commit in which the finding was found: {{git checkout abc123}}")
+
+	// The crashing input is copied to the output directory
+	marker := issue.Fields.Labels[2]
+	require.Equal(t, marker+".json", filepath.Base(issuePath))
+	input, err := os.ReadFile(filepath.Join(outputDir, marker+"-crashing-input"))
+	require.NoError(t, err)
+	require.Equal(t, "crash", string(input))
+}
+
+func TestExport_CustomTemplate(t *testing.T) {
+	projectDir := testutil.BootstrapEmptyProject(t, "test-export-")
+	saveFindings(t, projectDir)
+	templatePath := filepath.Join(projectDir, "issue.tmpl")
+	err := os.WriteFile(templatePath, []byte(`{{define "title"}}Fuzzing bug {{.Name}}{{end}}
+Found by {{.FuzzTest}}, input: {{.InputPath}}
+`), 0o644)
+	require.NoError(t, err)
+
+	opts := &options{ProjectDir: projectDir, ConfigDir: projectDir}
+	output, err := cmdutils.ExecuteCommand(t, newWithOptions(opts), os.Stdin,
+		"other_finding", "--issue-format", "gitlab", "--template", templatePath)
+	require.NoError(t, err)
+
+	var issues []*gitLabIssue
+	err = json.Unmarshal([]byte(output), &issues)
+	require.NoError(t, err, output)
+	require.Len(t, issues, 1)
+	require.Equal(t, "Fuzzing bug other_finding", issues[0].Title)
+	require.Equal(t, "Found by my_fuzz_test, input: .cifuzz-findings/other_finding/crashing-input\n", issues[0].Description)
+	require.Regexp(t, `^cifuzz,severity:critical,cifuzz-finding-[0-9a-f]{16}$`, issues[0].Labels)
+}
+
+func TestExport_Redacted(t *testing.T) {
+	projectDir := testutil.BootstrapEmptyProject(t, "test-export-")
+	f := &finding.Finding{
+		Name:      "leaky_finding",
+		Type:      finding.ErrorTypeCrash,
+		FuzzTest:  "my_fuzz_test",
+		InputData: []byte("crash"),
+		Logs:      []string{"API_TOKEN=s3cr3t", "mail alice@example.com"},
+		StackTrace: []*stacktrace.StackFrame{
+			{Function: "parse", SourceFile: "/home/alice/project/src/parser.cpp", Line: 10},
+		},
+	}
+	err := f.Save(projectDir)
+	require.NoError(t, err)
+	templatePath := filepath.Join(projectDir, "issue.tmpl")
+	err = os.WriteFile(templatePath, []byte(`{{range .Logs}}{{.}}
+{{end}}`), 0o644)
+	require.NoError(t, err)
+
+	opts := &options{ProjectDir: projectDir, ConfigDir: projectDir}
+	output, err := cmdutils.ExecuteCommand(t, newWithOptions(opts), os.Stdin,
+		"leaky_finding", "--issue-format", "github", "--template", templatePath)
+	require.NoError(t, err)
+
+	var issues []*gitHubIssue
+	err = json.Unmarshal([]byte(output), &issues)
+	require.NoError(t, err, output)
+	require.Len(t, issues, 1)
+	require.Contains(t, issues[0].Title, "parse (<home>/project/src/parser.cpp:10)")
+	require.Equal(t, "API_TOKEN=<token>\nmail <email>\n", issues[0].Body)
+
+	// The stored finding is not modified
+	f, err = finding.LoadFinding(projectDir, "leaky_finding", nil)
+	require.NoError(t, err)
+	require.Equal(t, []string{"API_TOKEN=s3cr3t", "mail alice@example.com"}, f.Logs)
+}
+
+func TestExport_InvalidFormat(t *testing.T) {
+	projectDir := testutil.BootstrapEmptyProject(t, "test-export-")
+	opts := &options{ProjectDir: projectDir, ConfigDir: projectDir}
+	_, err := cmdutils.ExecuteCommand(t, newWithOptions(opts), os.Stdin, "--issue-format", "bugzilla")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "Invalid value \"bugzilla\" for --issue-format")
+}
@@ -0,0 +1,199 @@
+package exporttest
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"code-intelligence.com/cifuzz/internal/cmdutils"
+	"code-intelligence.com/cifuzz/internal/completion"
+	"code-intelligence.com/cifuzz/internal/config"
+	"code-intelligence.com/cifuzz/pkg/finding"
+	"code-intelligence.com/cifuzz/pkg/log"
+	"code-intelligence.com/cifuzz/pkg/stubs"
+)
+
+type options struct {
+	BuildSystem string `mapstructure:"build-system"`
+	ProjectDir  string `mapstructure:"project-dir"`
+	ConfigDir   string `mapstructure:"config-dir"`
+
+	OutputPath string
+	Framework  string
+	Method     string
+}
+
+func (opts *options) validate() error {
+	var err error
+
+	if opts.BuildSystem == "" {
+		opts.BuildSystem, err = config.DetermineBuildSystem(opts.ProjectDir)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = config.ValidateBuildSystem(opts.BuildSystem)
+	if err != nil {
+		log.Error(err)
+		return cmdutils.WrapSilentError(err)
+	}
+
+	if opts.Framework != stubs.TestFrameworkGoogleTest && opts.Framework != stubs.TestFrameworkDoctest {
+		msg := fmt.Sprintf("Invalid test framework %q, valid frameworks are: %s",
+			opts.Framework, strings.Join(stubs.ValidTestFrameworks, ", "))
+		return cmdutils.WrapIncorrectUsageError(errors.New(msg))
+	}
+
+	return nil
+}
+
+type exportTestCmd struct {
+	*cobra.Command
+	opts *options
+}
+
+func New() *cobra.Command {
+	return newWithOptions(&options{})
+}
+
+func newWithOptions(opts *options) *cobra.Command {
+	var bindFlags func()
+
+	cmd := &cobra.Command{
+		Use:   "export-test <name>",
+		Short: "Create a regression unit test from a finding",
+		Long: `This command creates a unit test which executes the fuzz test of a
+finding with its crashing input. Adding the test to the project's test
+suite makes sure that the bug stays fixed.
+
+For CMake, Bazel and other build systems, a GoogleTest (default) or
+doctest test case is created which calls LLVMFuzzerTestOneInput with
+the crashing input. It must be linked with the fuzz test, but not with
+libFuzzer. The test is created in the current directory by default.
+
+For Maven and Gradle, a JUnit 5 test is created which calls the
+@FuzzTest method with the crashing input. The test is created in the
+package of the fuzz test below src/test/java by default. The @FuzzTest
+method is determined from the stack trace of the finding, if that's not
+possible, it has to be specified via --method.
+`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completion.ValidFindings,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			// Bind viper keys to flags. We can't do this in the New
+			// function, because that would re-bind viper keys which
+			// were bound to the flags of other commands before.
+			bindFlags()
+			err := config.FindAndParseProjectConfig(opts)
+			if err != nil {
+				log.Errorf(err, "Failed to parse cifuzz.yaml: %v", err.Error())
+				return cmdutils.WrapSilentError(err)
+			}
+			return opts.validate()
+		},
+		RunE: func(c *cobra.Command, args []string) error {
+			cmd := exportTestCmd{Command: c, opts: opts}
+			return cmd.run(args[0])
+		},
+	}
+
+	bindFlags = cmdutils.AddFlags(cmd,
+		cmdutils.AddProjectDirFlag,
+	)
+	cmd.Flags().StringVarP(&opts.OutputPath, "output", "o", "", "File path of the regression test.")
+	cmd.Flags().StringVar(&opts.Framework, "framework", stubs.TestFrameworkGoogleTest,
+		fmt.Sprintf("Test framework of C/C++ regression tests (%s).", strings.Join(stubs.ValidTestFrameworks, ", ")))
+	cmd.Flags().StringVar(&opts.Method, "method", "", "Name of the @FuzzTest method (Maven and Gradle only).")
+
+	return cmd
+}
+
+func (c *exportTestCmd) run(findingName string) error {
+	f, err := finding.LoadFinding(c.opts.ProjectDir, findingName, nil)
+	if finding.IsNotExistError(err) {
+		log.Errorf(err, "Finding %s does not exist", findingName)
+		return cmdutils.WrapSilentError(err)
+	}
+	if err != nil {
+		return err
+	}
+
+	input, err := f.ReadInput(c.opts.ProjectDir)
+	if err != nil {
+		return err
+	}
+
+	testOpts := &stubs.RegressionTestOptions{
+		FindingName: f.Name,
+		FuzzTest:    f.FuzzTest,
+		Input:       input,
+		Framework:   c.opts.Framework,
+	}
+
+	var testType config.FuzzTestType
+	switch c.opts.BuildSystem {
+	case config.BuildSystemMaven, config.BuildSystemGradle:
+		testType = config.Java
+		err = c.setJavaOptions(f, testOpts)
+		if err != nil {
+			return err
+		}
+	default:
+		testType = config.CPP
+		if c.opts.OutputPath == "" {
+			c.opts.OutputPath = f.Name + "_regression_test.cpp"
+		}
+	}
+
+	err = os.MkdirAll(filepath.Dir(c.opts.OutputPath), 0o755)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	err = stubs.CreateRegressionTest(c.opts.OutputPath, testType, testOpts)
+	if err != nil {
+		log.Errorf(err, "Failed to create regression test %s: %s", c.opts.OutputPath, err.Error())
+		return cmdutils.ErrSilent
+	}
+
+	log.Successf("Created regression test %s for finding %s", c.opts.OutputPath, f.Name)
+	return nil
+}
+
+func (c *exportTestCmd) setJavaOptions(f *finding.Finding, testOpts *stubs.RegressionTestOptions) error {
+	if f.FuzzTest == "" {
+		err := errors.Errorf("The fuzz test of finding %s is unknown", f.Name)
+		log.Error(err)
+		return cmdutils.WrapSilentError(err)
+	}
+	testOpts.FuzzTestClass = f.FuzzTest
+
+	testOpts.FuzzTestMethod = c.opts.Method
+	if testOpts.FuzzTestMethod == "" {
+		testOpts.FuzzTestMethod = f.FuzzTestMethod()
+	}
+	if testOpts.FuzzTestMethod == "" {
+		err := errors.Errorf("The @FuzzTest method of finding %s could not be determined, please specify it via --method", f.Name)
+		log.Error(err)
+		return cmdutils.WrapSilentError(err)
+	}
+
+	testOpts.ClassName = stubs.RegressionTestClassName(f.FuzzTest, f.Name)
+	if i := strings.LastIndex(f.FuzzTest, "."); i != -1 {
+		testOpts.PackageName = f.FuzzTest[:i]
+	}
+
+	if c.opts.OutputPath == "" {
+		c.opts.OutputPath = filepath.Join(c.opts.ProjectDir, "src", "test", "java",
+			filepath.FromSlash(strings.ReplaceAll(testOpts.PackageName, ".", "/")),
+			testOpts.ClassName+".java")
+	} else {
+		// The name of a public Java class must match the file name
+		testOpts.ClassName = strings.TrimSuffix(filepath.Base(c.opts.OutputPath), ".java")
+	}
+	return nil
+}
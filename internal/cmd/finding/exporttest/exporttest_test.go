@@ -0,0 +1,97 @@
+package exporttest
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"code-intelligence.com/cifuzz/internal/cmdutils"
+	"code-intelligence.com/cifuzz/internal/config"
+	"code-intelligence.com/cifuzz/internal/testutil"
+	"code-intelligence.com/cifuzz/pkg/finding"
+	"code-intelligence.com/cifuzz/pkg/log"
+	"code-intelligence.com/cifuzz/pkg/parser/libfuzzer/stacktrace"
+)
+
+var logOutput io.ReadWriter
+
+func TestMain(m *testing.M) {
+	logOutput = bytes.NewBuffer([]byte{})
+	log.Output = logOutput
+
+	m.Run()
+}
+
+func TestExportTest_CMake(t *testing.T) {
+	projectDir := testutil.BootstrapEmptyProject(t, "test-export-test-")
+
+	f := &finding.Finding{
+		Name:      "test_finding",
+		FuzzTest:  "my_fuzz_test",
+		InputData: []byte("crash"),
+	}
+	err := f.Save(projectDir)
+	require.NoError(t, err)
+
+	outputPath := filepath.Join(projectDir, "test", "regression_test.cpp")
+	opts := &options{
+		BuildSystem: config.BuildSystemCMake,
+		ProjectDir:  projectDir,
+		ConfigDir:   projectDir,
+	}
+	_, err = cmdutils.ExecuteCommand(t, newWithOptions(opts), os.Stdin,
+		"test_finding", "--framework", "doctest", "-o", outputPath)
+	require.NoError(t, err)
+
+	content, err := os.ReadFile(outputPath)
+	require.NoError(t, err)
+	require.Contains(t, string(content), "#include <doctest/doctest.h>")
+	require.Contains(t, string(content), "0x63, 0x72, 0x61, 0x73, 0x68,")
+}
+
+func TestExportTest_Maven(t *testing.T) {
+	projectDir := testutil.BootstrapEmptyProject(t, "test-export-test-")
+
+	f := &finding.Finding{
+		Name:      "test_finding",
+		FuzzTest:  "com.example.ParserFuzzTest",
+		InputData: []byte("crash"),
+		StackTrace: []*stacktrace.StackFrame{
+			{SourceFile: "com.example.Parser", Function: "parse", Line: 12},
+			{SourceFile: "com.example.ParserFuzzTest", Function: "fuzzParser", Line: 20},
+		},
+	}
+	err := f.Save(projectDir)
+	require.NoError(t, err)
+
+	opts := &options{
+		BuildSystem: config.BuildSystemMaven,
+		ProjectDir:  projectDir,
+		ConfigDir:   projectDir,
+	}
+	_, err = cmdutils.ExecuteCommand(t, newWithOptions(opts), os.Stdin, "test_finding")
+	require.NoError(t, err)
+
+	outputPath := filepath.Join(projectDir, "src", "test", "java", "com", "example",
+		"ParserFuzzTestTestFindingRegressionTest.java")
+	content, err := os.ReadFile(outputPath)
+	require.NoError(t, err)
+	require.Contains(t, string(content), "package com.example;")
+	require.Contains(t, string(content), `method.getName().equals("fuzzParser")`)
+}
+
+func TestExportTest_NonExistentFinding(t *testing.T) {
+	projectDir := testutil.BootstrapEmptyProject(t, "test-export-test-")
+
+	opts := &options{
+		BuildSystem: config.BuildSystemCMake,
+		ProjectDir:  projectDir,
+		ConfigDir:   projectDir,
+	}
+	_, err := cmdutils.ExecuteCommand(t, newWithOptions(opts), os.Stdin, "does_not_exist")
+	require.Error(t, err)
+}
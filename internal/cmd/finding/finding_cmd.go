@@ -5,6 +5,7 @@ import (
 	"os"
 	"strings"
 	"text/tabwriter"
+	"time"
 
 	"github.com/pkg/errors"
 	"github.com/pterm/pterm"
@@ -13,28 +14,133 @@ import (
 	"golang.org/x/term"
 
 	"code-intelligence.com/cifuzz/internal/api"
+	"code-intelligence.com/cifuzz/internal/cmd/finding/bisect"
+	"code-intelligence.com/cifuzz/internal/cmd/finding/clusters"
+	"code-intelligence.com/cifuzz/internal/cmd/finding/dedupe"
+	"code-intelligence.com/cifuzz/internal/cmd/finding/diff"
+	"code-intelligence.com/cifuzz/internal/cmd/finding/export"
+	"code-intelligence.com/cifuzz/internal/cmd/finding/exporttest"
+	"code-intelligence.com/cifuzz/internal/cmd/finding/importfinding"
+	"code-intelligence.com/cifuzz/internal/cmd/finding/redact"
+	"code-intelligence.com/cifuzz/internal/cmd/finding/setstatus"
+	"code-intelligence.com/cifuzz/internal/cmd/finding/snapshot"
+	"code-intelligence.com/cifuzz/internal/cmd/finding/symbolize"
 	"code-intelligence.com/cifuzz/internal/cmdutils"
 	"code-intelligence.com/cifuzz/internal/cmdutils/auth"
-	"code-intelligence.com/cifuzz/internal/cmdutils/login"
 	"code-intelligence.com/cifuzz/internal/completion"
 	"code-intelligence.com/cifuzz/internal/config"
 	"code-intelligence.com/cifuzz/pkg/finding"
 	"code-intelligence.com/cifuzz/pkg/log"
 	"code-intelligence.com/cifuzz/pkg/messaging"
+	"code-intelligence.com/cifuzz/util/sliceutil"
 	"code-intelligence.com/cifuzz/util/stringutil"
 )
 
 type options struct {
-	PrintJSON   bool   `mapstructure:"print-json"`
-	ProjectDir  string `mapstructure:"project-dir"`
-	ConfigDir   string `mapstructure:"config-dir"`
-	Interactive bool   `mapstructure:"interactive"`
-	Server      string `mapstructure:"server"`
+	PrintJSON    bool   `mapstructure:"print-json"`
+	ProjectDir   string `mapstructure:"project-dir"`
+	ConfigDir    string `mapstructure:"config-dir"`
+	Interactive  bool   `mapstructure:"interactive"`
+	Server       string `mapstructure:"server"`
+	BuildSystem  string `mapstructure:"build-system"`
+	BuildCommand string `mapstructure:"build-command"`
+	CleanCommand string `mapstructure:"clean-command"`
+	NumBuildJobs uint   `mapstructure:"build-jobs"`
+	ShowAll      bool
+
+	IncludeSuppressed bool
+
+	FuzzTest    string
+	Type        string
+	ErrorID     string
+	MinSeverity string
+	Since       string
+	Statuses    []string
+	Owner       string
+	SortBy      string
+	GroupBy     string
+
+	// The number of stack frames for which the source code is shown
+	NumFrames int
+	// Whether the values which the fuzz test consumes from the crashing
+	// input via the FuzzedDataProvider are determined
+	Decode bool
+
+	filter finding.Filter
+}
+
+func (opts *options) validate() error {
+	var err error
+
+	opts.filter = finding.Filter{
+		FuzzTest: opts.FuzzTest,
+		Type:     finding.ErrorType(opts.Type),
+		ErrorID:  opts.ErrorID,
+		Owner:    opts.Owner,
+	}
+
+	if opts.MinSeverity != "" {
+		opts.filter.MinSeverity, err = finding.ParseSeverity(opts.MinSeverity)
+		if err != nil {
+			return cmdutils.WrapIncorrectUsageError(err)
+		}
+	}
+
+	if opts.Since != "" {
+		opts.filter.Since, err = finding.ParseSince(opts.Since, time.Now())
+		if err != nil {
+			return cmdutils.WrapIncorrectUsageError(err)
+		}
+	}
+
+	for _, s := range opts.Statuses {
+		status, err := finding.ParseStatus(s)
+		if err != nil {
+			return cmdutils.WrapIncorrectUsageError(err)
+		}
+		opts.filter.Statuses = append(opts.filter.Statuses, status)
+	}
+
+	if !sliceutil.Contains(finding.ValidSortBy, opts.SortBy) {
+		msg := fmt.Sprintf("Invalid value %q for --sort-by, valid values are: %s",
+			opts.SortBy, strings.Join(finding.ValidSortBy, ", "))
+		return cmdutils.WrapIncorrectUsageError(errors.New(msg))
+	}
+
+	if opts.GroupBy != "" && !sliceutil.Contains(finding.ValidGroupBy, opts.GroupBy) {
+		msg := fmt.Sprintf("Invalid value %q for --group-by, valid values are: %s",
+			opts.GroupBy, strings.Join(finding.ValidGroupBy, ", "))
+		return cmdutils.WrapIncorrectUsageError(errors.New(msg))
+	}
+
+	return opts.validateShowOptions()
+}
+
+// validateShowOptions validates the options which are used to show a
+// single finding
+func (opts *options) validateShowOptions() error {
+	if opts.NumFrames < 0 {
+		msg := fmt.Sprintf("Invalid value %d for --frames, must not be negative", opts.NumFrames)
+		return cmdutils.WrapIncorrectUsageError(errors.New(msg))
+	}
+
+	if opts.Decode {
+		err := opts.validateDecode()
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
 }
 
 type findingCmd struct {
 	*cobra.Command
 	opts *options
+
+	// decode returns the calls of the FuzzedDataProvider with which the
+	// fuzz test consumes the crashing input of the finding
+	decode func(f *finding.Finding) ([]string, error)
 }
 
 func New() *cobra.Command {
@@ -60,23 +166,13 @@ func newWithOptions(opts *options) *cobra.Command {
 				log.Errorf(err, "Failed to parse cifuzz.yaml: %v", err.Error())
 				return cmdutils.WrapSilentError(err)
 			}
-			return nil
+			return opts.validate()
 		},
 		RunE: func(c *cobra.Command, args []string) error {
-			opts.Interactive = viper.GetBool("interactive")
-			// Command should not be interactive when stdin is not a terminal.
-			// TODO: Should this be global? Set on a Viper level for all commands?
-			if opts.Interactive {
-				opts.Interactive = term.IsTerminal(int(os.Stdin.Fd())) && term.IsTerminal(int(os.Stdout.Fd()))
-			}
-			opts.Server = viper.GetString("server")
-
-			var err error
-			opts.Server, err = api.ValidateAndNormalizeServerURL(opts.Server)
+			cmd, err := newFindingCmd(c, opts)
 			if err != nil {
 				return err
 			}
-			cmd := findingCmd{Command: c, opts: opts}
 			return cmd.run(args)
 		},
 	}
@@ -91,22 +187,93 @@ func newWithOptions(opts *options) *cobra.Command {
 		cmdutils.AddServerFlag,
 	)
 
+	cmd.Flags().BoolVarP(&opts.ShowAll, "all", "a", false, "List closed findings (fixed, ignored, wontfix) as well.")
+	cmd.Flags().BoolVar(&opts.IncludeSuppressed, "include-suppressed", false,
+		fmt.Sprintf("List findings which are suppressed via %s as well.", finding.SuppressionsFileName))
+	cmd.Flags().StringVar(&opts.FuzzTest, "fuzz-test", "", "Only list findings of the specified fuzz test.")
+	cmd.Flags().StringVar(&opts.Type, "type", "", "Only list findings of the specified type (e.g. crash or runtime_error).")
+	cmd.Flags().StringVar(&opts.ErrorID, "error-id", "", "Only list findings with the specified error ID.")
+	cmd.Flags().StringVar(&opts.MinSeverity, "min-severity", "",
+		"Only list findings with at least the specified severity score (0-10) or level (critical, high, medium, low).")
+	cmd.Flags().StringVar(&opts.Since, "since", "",
+		"Only list findings found since the specified date (YYYY-MM-DD) or within the specified duration (e.g. 12h or 7d).")
+	cmd.Flags().StringSliceVar(&opts.Statuses, "status", nil,
+		fmt.Sprintf("Only list findings with one of the specified statuses (%s).\nImplies --all.", validStatusesString()))
+	cmd.Flags().StringVar(&opts.Owner, "owner", "",
+		"Only list findings with the specified owner (e.g. @org/team or an email address).")
+	cmd.Flags().StringVar(&opts.SortBy, "sort-by", finding.SortByDate,
+		fmt.Sprintf("Sort the findings by the specified criterion (%s).", strings.Join(finding.ValidSortBy, ", ")))
+	cmd.Flags().StringVar(&opts.GroupBy, "group-by", "",
+		fmt.Sprintf("Group the findings by the specified property (%s).", strings.Join(finding.ValidGroupBy, ", ")))
+	addNumFramesFlag(cmd, opts)
+
+	cmd.AddCommand(bisect.New())
+	cmd.AddCommand(clusters.New())
+	cmd.AddCommand(dedupe.New())
+	cmd.AddCommand(diff.New())
+	cmd.AddCommand(export.New())
+	cmd.AddCommand(exporttest.New())
+	cmd.AddCommand(importfinding.New())
+	cmd.AddCommand(redact.New())
+	cmd.AddCommand(setstatus.New())
+	cmd.AddCommand(newShowCommand())
+	cmd.AddCommand(snapshot.New())
+	cmd.AddCommand(symbolize.New())
+
 	return cmd
 }
 
-func (cmd *findingCmd) run(args []string) error {
+// newFindingCmd creates the findingCmd which is run by `cifuzz finding`
+// and `cifuzz finding show`
+func newFindingCmd(c *cobra.Command, opts *options) (*findingCmd, error) {
+	opts.Interactive = viper.GetBool("interactive")
+	// Command should not be interactive when stdin is not a terminal.
+	// TODO: Should this be global? Set on a Viper level for all commands?
+	if opts.Interactive {
+		opts.Interactive = term.IsTerminal(int(os.Stdin.Fd())) && term.IsTerminal(int(os.Stdout.Fd()))
+	}
+	opts.Server = viper.GetString("server")
+
+	var err error
+	opts.Server, err = api.ValidateAndNormalizeServerURL(opts.Server)
+	if err != nil {
+		return nil, err
+	}
+	cmd := &findingCmd{Command: c, opts: opts}
+	cmd.decode = cmd.buildAndDecode
+	return cmd, nil
+}
+
+func addNumFramesFlag(cmd *cobra.Command, opts *options) {
+	cmd.Flags().IntVar(&opts.NumFrames, "frames", finding.DefaultSourceContextFrames,
+		"Show the source code of the specified number of stack frames of the finding (0 to disable).")
+}
+
+// authenticate offers to log in if the user is not authenticated and
+// the command is interactive. It returns whether the user is
+// authenticated and the error details with which the findings are
+// enhanced.
+func (cmd *findingCmd) authenticate() (bool, *[]finding.ErrorDetails, error) {
 	authenticated, err := auth.GetAuthStatus(cmd.opts.Server)
 	if err != nil {
-		return err
+		return false, nil, err
 	}
 	if !authenticated && cmd.opts.Interactive {
 		_, err = auth.ShowServerConnectionDialog(cmd.opts.Server, messaging.Finding)
 		if err != nil {
-			return err
+			return false, nil, err
 		}
 	}
 
-	errorDetails, err := cmd.checkForErrorDetails()
+	errorDetails, err := auth.GetErrorDetails(cmd.opts.Server, cmd.Command.Root().Version)
+	if err != nil {
+		return false, nil, err
+	}
+	return authenticated, errorDetails, nil
+}
+
+func (cmd *findingCmd) run(args []string) error {
+	authenticated, errorDetails, err := cmd.authenticate()
 	if err != nil {
 		return err
 	}
@@ -119,8 +286,59 @@ func (cmd *findingCmd) run(args []string) error {
 			return err
 		}
 
+		suppressions, err := finding.LoadSuppressions(cmd.opts.ProjectDir)
+		if err != nil {
+			return err
+		}
+
+		// Closed findings are only listed if the --all flag is used (or
+		// the --status flag selects them) and suppressed findings only
+		// if the --include-suppressed flag is used. The suppression
+		// rules are applied again, because they might have changed
+		// since the finding was saved.
+		showClosed := cmd.opts.ShowAll || len(cmd.opts.filter.Statuses) > 0
+		numClosed := 0
+		numSuppressed := 0
+		numFilteredOut := 0
+		visibleFindings := []*finding.Finding{}
+		for _, f := range findings {
+			if !cmd.opts.filter.Matches(f) {
+				numFilteredOut++
+				continue
+			}
+			f.Suppression = suppressions.Match(f)
+			if f.GetStatus().IsClosed() && !showClosed {
+				numClosed++
+				continue
+			}
+			if f.Suppression != nil && !cmd.opts.IncludeSuppressed {
+				numSuppressed++
+				continue
+			}
+			visibleFindings = append(visibleFindings, f)
+		}
+		findings = visibleFindings
+
+		err = finding.SortFindings(findings, cmd.opts.SortBy)
+		if err != nil {
+			return err
+		}
+
+		var groups []*finding.Group
+		if cmd.opts.GroupBy != "" {
+			groups, err = finding.GroupFindings(findings, cmd.opts.GroupBy)
+			if err != nil {
+				return err
+			}
+		}
+
 		if cmd.opts.PrintJSON {
-			s, err := stringutil.ToJSONString(findings)
+			var s string
+			if groups != nil {
+				s, err = stringutil.ToJSONString(groups)
+			} else {
+				s, err = stringutil.ToJSONString(findings)
+			}
 			if err != nil {
 				return err
 			}
@@ -129,60 +347,47 @@ func (cmd *findingCmd) run(args []string) error {
 		}
 
 		if len(findings) == 0 {
+			if numFilteredOut > 0 {
+				log.Printf("No findings match the specified filters (%d findings filtered out)", numFilteredOut)
+				return nil
+			}
+			if numClosed > 0 {
+				log.Printf("This project doesn't have any open findings (%d closed findings, use --all to list them)", numClosed)
+				return nil
+			}
+			if numSuppressed > 0 {
+				log.Printf("This project doesn't have any unsuppressed findings (%d suppressed findings, use --include-suppressed to list them)", numSuppressed)
+				return nil
+			}
 			log.Print("This project doesn't have any findings yet")
 			return nil
 		}
 
-		w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 1, ' ', 0)
-
-		data := [][]string{
-			{"Severity", "Name", "Description", "Location"},
+		if groups == nil {
+			return cmd.printFindingsTable(findings, authenticated)
 		}
-
-		if authenticated {
-			data = [][]string{
-				{"Severity", "Name", "Description", "Fuzz Test", "Location"},
+		for i, group := range groups {
+			if i > 0 {
+				_, _ = fmt.Fprintln(cmd.OutOrStdout())
 			}
-		}
-
-		for _, f := range findings {
-			if authenticated {
-				colorFunc := getColorFunctionForSeverity(f.MoreDetails.Severity.Score)
-				data = append(data, []string{
-					colorFunc(fmt.Sprintf("%.1f", f.MoreDetails.Severity.Score)),
-					f.Name,
-					// FIXME: replace f.ShortDescriptionColumns()[0] with
-					// f.MoreDetails.Name once we cover all bugs with our
-					// error-details.json
-					f.ShortDescriptionColumns()[0],
-					// showing the fuzz test name is a SaaS only feature...
-					f.FuzzTest,
-					f.ShortDescriptionColumns()[1],
-				})
-			} else {
-				data = append(data, []string{
-					"n/a",
-					f.Name,
-					f.ShortDescriptionColumns()[0],
-					f.ShortDescriptionColumns()[1],
-				})
+			_, _ = fmt.Fprintln(cmd.OutOrStdout(), pterm.Style{pterm.Reset, pterm.Bold}.Sprintf(
+				"%s: %s (%d)", cmd.opts.GroupBy, group.Key, len(group.Findings)))
+			err = cmd.printFindingsTable(group.Findings, authenticated)
+			if err != nil {
+				return err
 			}
 		}
-		err = pterm.DefaultTable.WithHasHeader().WithData(data).Render()
-		if err != nil {
-			return err
-		}
-
-		err = w.Flush()
-		if err != nil {
-			return errors.WithStack(err)
-		}
 		return nil
 	}
 
 	// If called with one argument, `cifuzz finding <finding name>`
-	// prints the information available for the specified finding
-	findingName := args[0]
+	// prints the information available for the specified finding, like
+	// `cifuzz finding show <finding name>`
+	return cmd.show(args[0], errorDetails)
+}
+
+// show prints the information available for the specified finding
+func (cmd *findingCmd) show(findingName string, errorDetails *[]finding.ErrorDetails) error {
 	f, err := finding.LoadFinding(cmd.opts.ProjectDir, findingName, errorDetails)
 	if finding.IsNotExistError(err) {
 		log.Errorf(err, "Finding %s does not exist", findingName)
@@ -191,12 +396,111 @@ func (cmd *findingCmd) run(args []string) error {
 	if err != nil {
 		return err
 	}
+	if cmd.opts.Decode {
+		err = cmd.decodeAndSave(f)
+		if err != nil {
+			return err
+		}
+	}
+	suppressions, err := finding.LoadSuppressions(cmd.opts.ProjectDir)
+	if err != nil {
+		return err
+	}
+	f.Suppression = suppressions.Match(f)
 	return cmd.printFinding(f)
 }
 
+func (cmd *findingCmd) printFindingsTable(findings []*finding.Finding, authenticated bool) error {
+	w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 1, ' ', 0)
+
+	data := [][]string{
+		{"Severity", "Name", "Status", "Description", "Location"},
+	}
+
+	if authenticated {
+		data = [][]string{
+			{"Severity", "Name", "Status", "Description", "Fuzz Test", "Location"},
+		}
+	}
+
+	for _, f := range findings {
+		// The severity is also available when not authenticated,
+		// via the error details shipped with cifuzz
+		severity := "n/a"
+		if f.MoreDetails != nil && f.MoreDetails.Severity != nil {
+			colorFunc := getColorFunctionForSeverity(f.MoreDetails.Severity.Score)
+			severity = colorFunc(fmt.Sprintf("%.1f", f.MoreDetails.Severity.Score))
+		}
+		if authenticated {
+			data = append(data, []string{
+				severity,
+				f.Name,
+				statusString(f),
+				// FIXME: replace f.ShortDescriptionColumns()[0] with
+				// f.MoreDetails.Name once we cover all bugs with our
+				// error-details.json
+				f.ShortDescriptionColumns()[0],
+				// showing the fuzz test name is a SaaS only feature...
+				f.FuzzTest,
+				f.ShortDescriptionColumns()[1],
+			})
+		} else {
+			data = append(data, []string{
+				severity,
+				f.Name,
+				statusString(f),
+				f.ShortDescriptionColumns()[0],
+				f.ShortDescriptionColumns()[1],
+			})
+		}
+	}
+	err := pterm.DefaultTable.WithHasHeader().WithData(data).Render()
+	if err != nil {
+		return err
+	}
+
+	err = w.Flush()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
+
+func validStatusesString() string {
+	var statuses []string
+	for _, s := range finding.ValidStatuses {
+		statuses = append(statuses, string(s))
+	}
+	return strings.Join(statuses, ", ")
+}
+
+func statusString(f *finding.Finding) string {
+	s := string(f.GetStatus())
+	if f.Suppression != nil {
+		s += " (suppressed)"
+	}
+	if f.IsUnreliable() {
+		s += fmt.Sprintf(" (flaky, %.0f%%)", f.Reproducibility.Rate*100)
+	}
+	return s
+}
+
+// findingWithSourceContext is the JSON output of `cifuzz finding <name>`.
+// The source context is not part of the finding, because it depends on
+// the current state of the source files.
+type findingWithSourceContext struct {
+	*finding.Finding
+	SourceContext []*finding.SourceContext `json:"source_context,omitempty"`
+}
+
 func (cmd *findingCmd) printFinding(f *finding.Finding) error {
+	var sourceContexts []*finding.SourceContext
+	if cmd.opts.NumFrames > 0 {
+		sourceContexts = f.SourceContexts(cmd.opts.ProjectDir, cmd.opts.NumFrames)
+	}
+
 	if cmd.opts.PrintJSON {
-		s, err := stringutil.ToJSONString(f)
+		s, err := stringutil.ToJSONString(&findingWithSourceContext{Finding: f, SourceContext: sourceContexts})
 		if err != nil {
 			return err
 		}
@@ -207,20 +511,116 @@ func (cmd *findingCmd) printFinding(f *finding.Finding) error {
 	} else {
 		s := pterm.Style{pterm.Reset, pterm.Bold}.Sprint(f.ShortDescriptionWithName())
 		s += fmt.Sprintf("\nDate: %s\n", f.CreatedAt)
+		s += fmt.Sprintf("Status: %s", f.GetStatus())
+		if f.StatusUpdatedAt != nil {
+			s += fmt.Sprintf(" (since %s)", *f.StatusUpdatedAt)
+		}
+		s += "\n"
+		if f.Assignee != "" {
+			s += fmt.Sprintf("Assignee: %s\n", f.Assignee)
+		}
+		if len(f.Owners) > 0 {
+			s += fmt.Sprintf("Owners: %s\n", strings.Join(f.Owners, ", "))
+		}
+		if f.Suppression != nil {
+			s += fmt.Sprintf("Suppressed: %s\n", f.Suppression.Reason)
+		}
+		if f.Reproducibility != nil {
+			s += reproducibilityString(f)
+		}
+		if f.GitRevision != nil && f.GitRevision.FirstBadCommit != "" {
+			s += fmt.Sprintf("First bad commit: %s\n", f.GitRevision.FirstBadCommit)
+		}
+		for _, note := range f.Notes {
+			s += fmt.Sprintf("Note (%s): %s\n", note.CreatedAt, note.Text)
+		}
 		s += fmt.Sprintf("\n  %s\n", strings.Join(f.Logs, "\n  "))
+		if len(f.DataProviderCalls) > 0 {
+			s += "\n" + pterm.Style{pterm.Reset, pterm.Bold}.Sprint("Decoded input:") + "\n"
+			s += fmt.Sprintf("  %s\n", strings.Join(f.DataProviderCalls, "\n  "))
+		}
+		if len(f.Causes) > 0 {
+			s += "\n" + causesString(f)
+		}
+		for _, c := range sourceContexts {
+			s += "\n" + sourceContextString(c)
+		}
 		_, err := fmt.Fprint(cmd.OutOrStdout(), s)
 		if err != nil {
 			return err
 		}
 
-		// only show more details if the user is authenticated
-		if auth, err := auth.GetAuthStatus(cmd.opts.Server); err == nil && auth {
-			PrintMoreDetails(f)
-		}
+		PrintMoreDetails(f)
 	}
 	return nil
 }
 
+// reproducibilityString formats the reproduction rate of the finding
+// and the results of the attempts to reproduce it
+func reproducibilityString(f *finding.Finding) string {
+	s := fmt.Sprintf("Reproduced: %s", f.Reproducibility)
+	if f.IsUnreliable() {
+		s += pterm.Style{pterm.Reset, pterm.FgYellow}.Sprint(" - flaky")
+	}
+	s += "\n"
+	for i, a := range f.Reproducibility.Attempts {
+		result := "not reproduced"
+		if a.Reproduced {
+			result = "reproduced"
+		}
+		if a.ErrorID != "" {
+			result += ", " + a.ErrorID
+		}
+		if a.Error != "" {
+			result += ", " + a.Error
+		}
+		s += fmt.Sprintf("  Attempt %d: %s (%s)\n", i+1, result, a.Duration.Round(time.Millisecond))
+	}
+	return s
+}
+
+// causesString formats the chain of exceptions which caused the Java
+// exception of the finding, with the in-project frames of each cause
+func causesString(f *finding.Finding) string {
+	s := pterm.Style{pterm.Reset, pterm.Bold}.Sprint("Exception causes:") + "\n"
+	for i, cause := range f.Causes {
+		label := "Caused by"
+		if i == len(f.Causes)-1 {
+			label = "Root cause"
+		}
+		s += fmt.Sprintf("  %s: %s", label, cause.Exception)
+		if cause.Message != "" {
+			s += ": " + cause.Message
+		}
+		s += "\n"
+		for _, frame := range cause.StackTrace {
+			s += fmt.Sprintf("    at %s (%s)\n", frame.Function, frame.Location())
+		}
+	}
+	return s
+}
+
+// sourceContextString formats the source code around a stack frame,
+// with the line of the frame highlighted
+func sourceContextString(c *finding.SourceContext) string {
+	header := fmt.Sprintf("%s:%d", c.SourceFile, c.Line)
+	if c.Function != "" {
+		header = fmt.Sprintf("%s in %s", c.Function, header)
+	}
+	s := pterm.Style{pterm.Reset, pterm.Bold}.Sprint(header) + "\n"
+
+	// Align the line numbers
+	width := len(fmt.Sprint(c.Lines[len(c.Lines)-1].Number))
+	for _, line := range c.Lines {
+		if line.Number == c.Line {
+			s += pterm.Style{pterm.Bold, pterm.FgRed}.Sprintf("> %*d | %s", width, line.Number, line.Text) + "\n"
+		} else {
+			s += fmt.Sprintf("  %*d | %s\n", width, line.Number, line.Text)
+		}
+	}
+	return s
+}
+
 func PrintMoreDetails(f *finding.Finding) {
 	if f.MoreDetails == nil {
 		return
@@ -306,28 +706,3 @@ func wrapLongStringToMultiline(s string, maxLineLength int) string {
 	result += currentLine
 	return result
 }
-
-// checkForErrorDetails tries to get error details from the API.
-// If the API is available and the user is logged in, it returns the error details.
-// If the API is not available or the user is not logged in, it returns nil.
-func (cmd *findingCmd) checkForErrorDetails() (*[]finding.ErrorDetails, error) {
-	var errorDetails []finding.ErrorDetails
-	var err error
-
-	token := login.GetToken(cmd.opts.Server)
-	log.Debugf("Checking for error details on server %s", cmd.opts.Server)
-
-	apiClient := api.NewClient(cmd.opts.Server, cmd.Command.Root().Version)
-	errorDetails, err = apiClient.GetErrorDetails(token)
-	if err != nil {
-		var connErr *api.ConnectionError
-		if !errors.As(err, &connErr) {
-			return nil, err
-		} else {
-			log.Warn("Skipping error details.")
-			log.Debugf("Connection error: %v (continiung gracefully)", connErr)
-			return nil, nil
-		}
-	}
-	return &errorDetails, nil
-}
@@ -2,18 +2,23 @@ package finding
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
 	"code-intelligence.com/cifuzz/integration-tests/shared/mockserver"
 	"code-intelligence.com/cifuzz/internal/cmdutils"
+	"code-intelligence.com/cifuzz/internal/config"
 	"code-intelligence.com/cifuzz/internal/testutil"
 	"code-intelligence.com/cifuzz/pkg/finding"
 	"code-intelligence.com/cifuzz/pkg/log"
+	"code-intelligence.com/cifuzz/pkg/parser/libfuzzer/stacktrace"
 	"code-intelligence.com/cifuzz/util/fileutil"
 	"code-intelligence.com/cifuzz/util/stringutil"
 )
@@ -163,6 +168,27 @@ func TestPrintFinding(t *testing.T) {
 	require.NotContains(t, string(outputBuffer), "cifuzz found more extensive information about this finding:")
 }
 
+func TestShowFinding(t *testing.T) {
+	f := &finding.Finding{
+		Name: "test_finding",
+	}
+	projectDir := testutil.BootstrapEmptyProject(t, "test-show-finding-")
+	err := f.Save(projectDir)
+	require.NoError(t, err)
+
+	opts := &options{ProjectDir: projectDir, ConfigDir: projectDir}
+	output, err := cmdutils.ExecuteCommand(t, newShowWithOptions(opts), os.Stdin, f.Name, "--json", "--interactive=false")
+	require.NoError(t, err)
+	jsonString, err := stringutil.ToJSONString(f)
+	require.NoError(t, err)
+	require.Equal(t, jsonString, output)
+
+	// The name of the finding is required
+	opts = &options{ProjectDir: projectDir, ConfigDir: projectDir}
+	_, err = cmdutils.ExecuteCommand(t, newShowWithOptions(opts), os.Stdin, "--interactive=false")
+	require.Error(t, err)
+}
+
 func TestPrintFinding_Authenticated(t *testing.T) {
 	t.Setenv("CIFUZZ_API_TOKEN", "token")
 	server := mockserver.New(t)
@@ -216,3 +242,281 @@ func TestPrintFinding_Authenticated(t *testing.T) {
 	require.NoError(t, err)
 	testutil.CheckOutput(t, logOutput, "cifuzz found more extensive information about this finding:")
 }
+
+func TestPrintFinding_SourceContext(t *testing.T) {
+	projectDir := testutil.BootstrapEmptyProject(t, "test-print-finding-")
+	err := os.WriteFile(filepath.Join(projectDir, "parser.cpp"), []byte("int a;\nint b;\nint c;\n"), 0o644)
+	require.NoError(t, err)
+
+	f := &finding.Finding{
+		Name: "test_finding",
+		StackTrace: []*stacktrace.StackFrame{
+			{SourceFile: "parser.cpp", Line: 2, Function: "parse"},
+		},
+	}
+	err = f.Save(projectDir)
+	require.NoError(t, err)
+
+	opts := &options{ProjectDir: projectDir, ConfigDir: projectDir}
+	output, err := cmdutils.ExecuteCommand(t, newWithOptions(opts), os.Stdin, f.Name, "--json", "--interactive=false")
+	require.NoError(t, err)
+	var result struct {
+		SourceContext []*finding.SourceContext `json:"source_context"`
+	}
+	err = json.Unmarshal([]byte(output), &result)
+	require.NoError(t, err)
+	require.Len(t, result.SourceContext, 1)
+	require.Equal(t, uint32(2), result.SourceContext[0].Line)
+	require.Len(t, result.SourceContext[0].Lines, 3)
+
+	output, err = cmdutils.ExecuteCommand(t, newWithOptions(opts), os.Stdin, f.Name, "--interactive=false")
+	require.NoError(t, err)
+	require.Contains(t, output, "parse in parser.cpp:2")
+	require.Contains(t, output, "> 2 | int b;")
+	require.Contains(t, output, "  3 | int c;")
+
+	// The source context can be disabled
+	output, err = cmdutils.ExecuteCommand(t, newWithOptions(opts), os.Stdin, f.Name, "--json", "--frames=0", "--interactive=false")
+	require.NoError(t, err)
+	jsonString, err := stringutil.ToJSONString(f)
+	require.NoError(t, err)
+	require.Equal(t, jsonString, output)
+}
+
+func TestPrintFinding_Causes(t *testing.T) {
+	projectDir := testutil.BootstrapEmptyProject(t, "test-print-finding-")
+	f := &finding.Finding{
+		Name:    "test_finding",
+		Details: "java.lang.RuntimeException: Failed to parse",
+		Causes: []*stacktrace.ExceptionCause{
+			{Exception: "java.io.UncheckedIOException"},
+			{
+				Exception:  "java.lang.IllegalStateException",
+				Message:    "Unexpected token",
+				StackTrace: []*stacktrace.StackFrame{{SourceFile: "com.example.Lexer", Line: 5, Function: "next"}},
+			},
+		},
+	}
+	err := f.Save(projectDir)
+	require.NoError(t, err)
+
+	opts := &options{ProjectDir: projectDir, ConfigDir: projectDir}
+	output, err := cmdutils.ExecuteCommand(t, newWithOptions(opts), os.Stdin, f.Name, "--interactive=false")
+	require.NoError(t, err)
+	require.Contains(t, output, "Caused by: java.io.UncheckedIOException\n")
+	require.Contains(t, output, "Root cause: java.lang.IllegalStateException: Unexpected token")
+	require.Contains(t, output, "at next (com.example.Lexer:5)")
+}
+
+func TestPrintFinding_Reproducibility(t *testing.T) {
+	projectDir := testutil.BootstrapEmptyProject(t, "test-print-finding-")
+	f := &finding.Finding{
+		Name:        "flaky_finding",
+		MoreDetails: &finding.ErrorDetails{ID: "data_race"},
+		Reproducibility: finding.NewReproducibility([]*finding.ReproAttempt{
+			{Reproduced: true, ErrorID: "data_race", Duration: 1500 * time.Millisecond},
+			{Reproduced: false, Duration: 2 * time.Second},
+		}),
+	}
+	err := f.Save(projectDir)
+	require.NoError(t, err)
+
+	opts := &options{ProjectDir: projectDir, ConfigDir: projectDir}
+	output, err := cmdutils.ExecuteCommand(t, newWithOptions(opts), os.Stdin, f.Name, "--interactive=false")
+	require.NoError(t, err)
+	require.Contains(t, output, "Reproduced: 1/2 attempts (50%)")
+	require.Contains(t, output, "flaky")
+	require.Contains(t, output, "Attempt 1: reproduced, data_race (1.5s)")
+	require.Contains(t, output, "Attempt 2: not reproduced (2s)")
+
+	require.Equal(t, "open (flaky, 50%)", statusString(f))
+}
+
+func TestDecodeAndSave(t *testing.T) {
+	projectDir := testutil.BootstrapEmptyProject(t, "test-decode-finding-")
+	f := &finding.Finding{
+		Name:      "test_finding",
+		FuzzTest:  "my_fuzz_test",
+		InputData: []byte("ab\\x*\x00\x00\x00"),
+	}
+	err := f.Save(projectDir)
+	require.NoError(t, err)
+
+	opts := &options{}
+	cmd := &findingCmd{Command: newShowWithOptions(opts), opts: opts}
+	// Set the options after creating the command, because adding the
+	// flags resets them to their default values
+	opts.ProjectDir = projectDir
+	calls := []string{`ConsumeRandomLengthString() = "ab"`, `ConsumeIntegral<int>() = 42`}
+	cmd.decode = func(f *finding.Finding) ([]string, error) {
+		return calls, nil
+	}
+	err = cmd.decodeAndSave(f)
+	require.NoError(t, err)
+	require.Equal(t, calls, f.DataProviderCalls)
+
+	// The calls are stored in the finding
+	f, err = finding.LoadFinding(projectDir, f.Name, nil)
+	require.NoError(t, err)
+	require.Equal(t, calls, f.DataProviderCalls)
+	require.Equal(t, `ConsumeRandomLengthString() = "ab"; ConsumeIntegral<int>() = 42`, f.HumanReadableInput)
+
+	output, err := cmdutils.ExecuteCommand(t, newShowWithOptions(&options{ProjectDir: projectDir, ConfigDir: projectDir}),
+		os.Stdin, f.Name, "--interactive=false")
+	require.NoError(t, err)
+	require.Contains(t, output, "Decoded input:")
+	require.Contains(t, output, "  ConsumeRandomLengthString() = \"ab\"\n  ConsumeIntegral<int>() = 42")
+
+	// Fuzz tests which don't use the FuzzedDataProvider shipped with
+	// cifuzz don't log any calls
+	cmd.decode = func(f *finding.Finding) ([]string, error) {
+		return nil, nil
+	}
+	err = cmd.decodeAndSave(f)
+	require.Error(t, err)
+	testutil.CheckOutput(t, logOutput, "didn't consume the crashing input via the FuzzedDataProvider")
+}
+
+func TestValidateDecode(t *testing.T) {
+	// Java fuzz tests are decoded via a Jazzer hook
+	opts := &options{BuildSystem: config.BuildSystemMaven}
+	err := opts.validateDecode()
+	require.NoError(t, err)
+
+	opts = &options{BuildSystem: config.BuildSystemOther}
+	err = opts.validateDecode()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "build-command")
+}
+
+func TestListFindings_HidesClosedFindings(t *testing.T) {
+	projectDir := testutil.BootstrapEmptyProject(t, "test-list-findings-")
+
+	open := &finding.Finding{Name: "open_finding"}
+	fixed := &finding.Finding{Name: "fixed_finding"}
+	fixed.SetStatus(finding.StatusFixed)
+	for _, f := range []*finding.Finding{open, fixed} {
+		err := f.Save(projectDir)
+		require.NoError(t, err)
+	}
+
+	opts := &options{ProjectDir: projectDir, ConfigDir: projectDir}
+	output, err := cmdutils.ExecuteCommand(t, newWithOptions(opts), os.Stdin, "--json", "--interactive=false")
+	require.NoError(t, err)
+	var findings []*finding.Finding
+	err = json.Unmarshal([]byte(output), &findings)
+	require.NoError(t, err)
+	require.Len(t, findings, 1)
+	require.Equal(t, "open_finding", findings[0].Name)
+
+	opts = &options{ProjectDir: projectDir, ConfigDir: projectDir}
+	output, err = cmdutils.ExecuteCommand(t, newWithOptions(opts), os.Stdin, "--json", "--interactive=false", "--all")
+	require.NoError(t, err)
+	err = json.Unmarshal([]byte(output), &findings)
+	require.NoError(t, err)
+	require.Len(t, findings, 2)
+}
+
+func TestListFindings_HidesSuppressedFindings(t *testing.T) {
+	projectDir := testutil.BootstrapEmptyProject(t, "test-list-findings-")
+
+	f := &finding.Finding{Name: "suppressed_finding"}
+	err := f.Save(projectDir)
+	require.NoError(t, err)
+	err = os.WriteFile(filepath.Join(projectDir, finding.SuppressionsFileName), []byte(`
+suppressions:
+  - finding: suppressed_finding
+    reason: Known bug
+`), 0o644)
+	require.NoError(t, err)
+
+	opts := &options{ProjectDir: projectDir, ConfigDir: projectDir}
+	output, err := cmdutils.ExecuteCommand(t, newWithOptions(opts), os.Stdin, "--json", "--interactive=false")
+	require.NoError(t, err)
+	require.Equal(t, "[]", output)
+
+	opts = &options{ProjectDir: projectDir, ConfigDir: projectDir}
+	output, err = cmdutils.ExecuteCommand(t, newWithOptions(opts), os.Stdin, "--json", "--interactive=false", "--include-suppressed")
+	require.NoError(t, err)
+	var findings []*finding.Finding
+	err = json.Unmarshal([]byte(output), &findings)
+	require.NoError(t, err)
+	require.Len(t, findings, 1)
+	require.Equal(t, "Known bug", findings[0].Suppression.Reason)
+}
+
+func TestListFindings_FilterSortAndGroup(t *testing.T) {
+	projectDir := testutil.BootstrapEmptyProject(t, "test-list-findings-")
+
+	now := time.Now()
+	findings := []*finding.Finding{
+		{
+			Name:        "old_finding",
+			FuzzTest:    "fuzz_test_a",
+			CreatedAt:   now.AddDate(0, 0, -30),
+			MoreDetails: &finding.ErrorDetails{ID: "id_a", Severity: &finding.Severity{Score: 9.0}},
+		},
+		{
+			Name:        "new_finding",
+			FuzzTest:    "fuzz_test_b",
+			CreatedAt:   now.Add(-time.Hour),
+			MoreDetails: &finding.ErrorDetails{ID: "id_b", Severity: &finding.Severity{Score: 2.0}},
+			Owners:      []string{"@org/team-x"},
+		},
+		{
+			Name:        "fixed_finding",
+			FuzzTest:    "fuzz_test_a",
+			CreatedAt:   now.Add(-2 * time.Hour),
+			MoreDetails: &finding.ErrorDetails{ID: "id_a", Severity: &finding.Severity{Score: 7.5}},
+		},
+	}
+	findings[2].SetStatus(finding.StatusFixed)
+	for _, f := range findings {
+		err := f.Save(projectDir)
+		require.NoError(t, err)
+	}
+
+	listNames := func(args ...string) []string {
+		opts := &options{ProjectDir: projectDir, ConfigDir: projectDir}
+		args = append([]string{"--json", "--interactive=false"}, args...)
+		output, err := cmdutils.ExecuteCommand(t, newWithOptions(opts), os.Stdin, args...)
+		require.NoError(t, err)
+		var listed []*finding.Finding
+		err = json.Unmarshal([]byte(output), &listed)
+		require.NoError(t, err)
+		var names []string
+		for _, f := range listed {
+			names = append(names, f.Name)
+		}
+		return names
+	}
+
+	require.Equal(t, []string{"new_finding", "old_finding"}, listNames())
+	require.Equal(t, []string{"old_finding"}, listNames("--fuzz-test", "fuzz_test_a"))
+	require.Equal(t, []string{"new_finding"}, listNames("--error-id", "id_b"))
+	require.Equal(t, []string{"old_finding"}, listNames("--min-severity", "high"))
+	require.Equal(t, []string{"new_finding"}, listNames("--since", "7d"))
+	require.Equal(t, []string{"fixed_finding"}, listNames("--status", "fixed"))
+	require.Equal(t, []string{"new_finding"}, listNames("--owner", "@org/team-x"))
+	require.Equal(t, []string{"old_finding", "fixed_finding", "new_finding"},
+		listNames("--all", "--sort-by", "severity"))
+
+	// Check that grouped findings are printed as groups
+	opts := &options{ProjectDir: projectDir, ConfigDir: projectDir}
+	output, err := cmdutils.ExecuteCommand(t, newWithOptions(opts), os.Stdin,
+		"--json", "--interactive=false", "--all", "--group-by", "error-id")
+	require.NoError(t, err)
+	var groups []*finding.Group
+	err = json.Unmarshal([]byte(output), &groups)
+	require.NoError(t, err)
+	require.Len(t, groups, 2)
+	require.Equal(t, "id_b", groups[0].Key)
+	require.Len(t, groups[0].Findings, 1)
+	require.Equal(t, "id_a", groups[1].Key)
+	require.Len(t, groups[1].Findings, 2)
+
+	// Check that invalid values are rejected
+	opts = &options{ProjectDir: projectDir, ConfigDir: projectDir}
+	_, err = cmdutils.ExecuteCommand(t, newWithOptions(opts), os.Stdin, "--interactive=false", "--sort-by", "name")
+	require.Error(t, err)
+}
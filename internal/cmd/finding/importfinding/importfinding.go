@@ -0,0 +1,336 @@
+package importfinding
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"code-intelligence.com/cifuzz/internal/build"
+	"code-intelligence.com/cifuzz/internal/build/fuzztest"
+	"code-intelligence.com/cifuzz/internal/cmd/run/reporthandler"
+	"code-intelligence.com/cifuzz/internal/cmdutils"
+	"code-intelligence.com/cifuzz/internal/config"
+	"code-intelligence.com/cifuzz/internal/replayer"
+	"code-intelligence.com/cifuzz/pkg/finding"
+	"code-intelligence.com/cifuzz/pkg/log"
+	"code-intelligence.com/cifuzz/pkg/parser/errorid"
+	"code-intelligence.com/cifuzz/pkg/report"
+	"code-intelligence.com/cifuzz/util/fileutil"
+)
+
+type options struct {
+	BuildSystem     string               `mapstructure:"build-system"`
+	BuildCommand    string               `mapstructure:"build-command"`
+	CleanCommand    string               `mapstructure:"clean-command"`
+	NumBuildJobs    uint                 `mapstructure:"build-jobs"`
+	EngineArgs      []string             `mapstructure:"engine-args"`
+	ProjectDir      string               `mapstructure:"project-dir"`
+	ConfigDir       string               `mapstructure:"config-dir"`
+	Dedup           finding.DedupOptions `mapstructure:"dedup"`
+	ErrorIDMatchers errorid.UserMatchers `mapstructure:"error-ids"`
+
+	FuzzTest     string
+	targetMethod string
+}
+
+func (opts *options) validate() error {
+	var err error
+
+	if opts.BuildSystem == "" {
+		opts.BuildSystem, err = config.DetermineBuildSystem(opts.ProjectDir)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = config.ValidateBuildSystem(opts.BuildSystem)
+	if err != nil {
+		log.Error(err)
+		return cmdutils.WrapSilentError(err)
+	}
+
+	// To build with other build systems, a build command must be provided
+	if opts.BuildSystem == config.BuildSystemOther && opts.BuildCommand == "" {
+		msg := "Flag \"build-command\" must be set when using build system type \"other\""
+		return cmdutils.WrapIncorrectUsageError(errors.New(msg))
+	}
+
+	// Check if the fuzz test is a method of a class
+	if strings.Contains(opts.FuzzTest, "::") {
+		split := strings.Split(opts.FuzzTest, "::")
+		opts.FuzzTest, opts.targetMethod = split[0], split[1]
+	}
+
+	err = opts.Dedup.Validate()
+	if err != nil {
+		log.Error(err)
+		return cmdutils.WrapSilentError(err)
+	}
+
+	err = opts.ErrorIDMatchers.Validate()
+	if err != nil {
+		log.Error(err)
+		return cmdutils.WrapSilentError(err)
+	}
+
+	return nil
+}
+
+type importCmd struct {
+	*cobra.Command
+	opts *options
+
+	tempDir     string
+	buildResult *build.Result
+
+	// build builds the fuzz test and returns the seed corpus directory
+	// to which the crashing inputs are copied
+	build func() (string, error)
+	// replay executes the fuzz test with the input file and returns
+	// the findings which were reported
+	replay func(inputFile string) ([]*finding.Finding, error)
+}
+
+func New() *cobra.Command {
+	return newWithOptions(&options{})
+}
+
+func newWithOptions(opts *options) *cobra.Command {
+	var bindFlags func()
+
+	cmd := &cobra.Command{
+		Use:   "import <file>... --fuzz-test <fuzz test>",
+		Short: "Import crash files as findings",
+		Long: `This command imports crashing inputs which were found without cifuzz
+as findings, for example crash-<sha1>, oom-<sha1> and timeout-<sha1>
+files written by libFuzzer or Jazzer and Crash_<sha1>.java reproducers
+written by Jazzer.
+
+The fuzz test is built and executed with each of the files. The
+resulting findings are saved like findings of 'cifuzz run', i.e. they
+are named and deduplicated the same way and their inputs are added to
+the seed corpus of the fuzz test. Files which don't reproduce a finding
+are skipped.
+
+To reproduce timeouts, pass the timeout for single inputs to libFuzzer,
+e.g. via --engine-arg=-timeout=25.
+`,
+		Args: cobra.MinimumNArgs(1),
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			// Bind viper keys to flags. We can't do this in the New
+			// function, because that would re-bind viper keys which
+			// were bound to the flags of other commands before.
+			bindFlags()
+			err := config.FindAndParseProjectConfig(opts)
+			if err != nil {
+				log.Errorf(err, "Failed to parse cifuzz.yaml: %v", err.Error())
+				return cmdutils.WrapSilentError(err)
+			}
+			return opts.validate()
+		},
+		RunE: func(c *cobra.Command, args []string) error {
+			cmd := &importCmd{Command: c, opts: opts}
+			cmd.build = cmd.buildFuzzTest
+			cmd.replay = cmd.replayInput
+			return cmd.run(args)
+		},
+	}
+
+	bindFlags = cmdutils.AddFlags(cmd,
+		cmdutils.AddBuildCommandFlag,
+		cmdutils.AddCleanCommandFlag,
+		cmdutils.AddBuildJobsFlag,
+		cmdutils.AddEngineArgFlag,
+		cmdutils.AddProjectDirFlag,
+	)
+	cmd.Flags().StringVar(&opts.FuzzTest, "fuzz-test", "", "The fuzz test which produced the crash files.")
+	err := cmd.MarkFlagRequired("fuzz-test")
+	if err != nil {
+		panic(err)
+	}
+
+	return cmd
+}
+
+func (c *importCmd) run(files []string) error {
+	var err error
+
+	for _, file := range files {
+		exists, err := fileutil.Exists(file)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			err = errors.Errorf("File %s does not exist", file)
+			log.Error(err)
+			return cmdutils.WrapSilentError(err)
+		}
+	}
+
+	c.tempDir, err = os.MkdirTemp("", "cifuzz-import-")
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defer fileutil.Cleanup(c.tempDir)
+
+	seedCorpusDir, err := c.build()
+	if err != nil {
+		return err
+	}
+
+	suppressions, err := finding.LoadSuppressions(c.opts.ProjectDir)
+	if err != nil {
+		log.Error(err)
+		return cmdutils.WrapSilentError(err)
+	}
+
+	// Findings are saved via the report handler, so that they are
+	// named and deduplicated exactly like findings of 'cifuzz run'
+	reportHandler, err := reporthandler.NewReportHandler(c.opts.FuzzTest, &reporthandler.ReportHandlerOptions{
+		ProjectDir:      c.opts.ProjectDir,
+		SeedCorpusDir:   seedCorpusDir,
+		Dedup:           &c.opts.Dedup,
+		Suppressions:    suppressions,
+		ErrorIDMatchers: c.opts.ErrorIDMatchers,
+	})
+	if err != nil {
+		return err
+	}
+
+	existingFindings, err := finding.ListFindings(c.opts.ProjectDir, nil)
+	if err != nil {
+		return err
+	}
+	existingNames := make(map[string]bool)
+	for _, f := range existingFindings {
+		existingNames[f.Name] = true
+	}
+
+	numImported := 0
+	numDuplicates := 0
+	numSkipped := 0
+	for i, file := range files {
+		f, err := c.importFile(file, i)
+		if err != nil {
+			log.Warnf("Skipping %s: %v", file, err)
+			log.Debugf("Import error: %+v", err)
+			numSkipped++
+			continue
+		}
+		if f == nil {
+			log.Warnf("Skipping %s because it doesn't reproduce a finding", file)
+			numSkipped++
+			continue
+		}
+
+		err = reportHandler.Handle(&report.Report{Finding: f})
+		if err != nil {
+			return err
+		}
+
+		// The report handler names the finding
+		if existingNames[f.Name] {
+			log.Infof("%s is a duplicate of finding %s", file, f.Name)
+			numDuplicates++
+			continue
+		}
+		existingNames[f.Name] = true
+		numImported++
+	}
+
+	if numImported == 0 && numDuplicates == 0 {
+		err = errors.New("None of the files reproduced a finding")
+		log.Error(err)
+		return cmdutils.WrapSilentError(err)
+	}
+
+	log.Successf("Imported %d findings (%d duplicates, %d skipped)", numImported, numDuplicates, numSkipped)
+	return nil
+}
+
+// importFile executes the fuzz test with the crashing input stored in
+// the file and returns the resulting finding, or nil if the input
+// doesn't reproduce a finding
+func (c *importCmd) importFile(file string, index int) (*finding.Finding, error) {
+	input, err := readCrashingInput(file)
+	if err != nil {
+		return nil, err
+	}
+
+	// Use a separate directory per file, to keep the original file
+	// names (which are part of the fuzzer output) even if files of
+	// different directories have the same name
+	inputDir := filepath.Join(c.tempDir, fmt.Sprint(index))
+	err = os.MkdirAll(inputDir, 0o755)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	inputFile := filepath.Join(inputDir, strings.TrimSuffix(filepath.Base(file), ".java"))
+	err = os.WriteFile(inputFile, input, 0o644)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	findings, err := c.replay(inputFile)
+	if err != nil {
+		return nil, err
+	}
+	if len(findings) == 0 {
+		return nil, nil
+	}
+
+	// The finding must contain the imported input, which is not the
+	// case if the fuzzer didn't write it to an artifact file
+	f := findings[0]
+	f.InputData = input
+	f.InputFile = inputFile
+	return f, nil
+}
+
+func (c *importCmd) buildFuzzTest() (string, error) {
+	var buildOutput io.Writer = io.Discard
+	if viper.GetBool("verbose") {
+		buildOutput = c.ErrOrStderr()
+	}
+
+	log.Infof("Building %s", c.opts.FuzzTest)
+	buildOpts := &fuzztest.BuildOptions{
+		BuildSystem:  c.opts.BuildSystem,
+		BuildCommand: c.opts.BuildCommand,
+		CleanCommand: c.opts.CleanCommand,
+		NumBuildJobs: c.opts.NumBuildJobs,
+		ProjectDir:   c.opts.ProjectDir,
+		FuzzTest:     c.opts.FuzzTest,
+		TempDir:      c.tempDir,
+		Stdout:       buildOutput,
+		Stderr:       buildOutput,
+	}
+	var err error
+	c.buildResult, err = fuzztest.Build(buildOpts)
+	if err != nil {
+		return "", err
+	}
+	// The builder might have changed the name of the fuzz test (for
+	// Bazel)
+	c.opts.FuzzTest = buildOpts.FuzzTest
+	return c.buildResult.SeedCorpus, nil
+}
+
+func (c *importCmd) replayInput(inputFile string) ([]*finding.Finding, error) {
+	return replayer.Replay(context.Background(), &replayer.Options{
+		BuildSystem:  c.opts.BuildSystem,
+		BuildResult:  c.buildResult,
+		FuzzTest:     c.opts.FuzzTest,
+		TargetMethod: c.opts.targetMethod,
+		ProjectDir:   c.opts.ProjectDir,
+		InputFiles:   []string{inputFile},
+		EngineArgs:   c.opts.EngineArgs,
+	})
+}
@@ -0,0 +1,149 @@
+package importfinding
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"code-intelligence.com/cifuzz/internal/testutil"
+	"code-intelligence.com/cifuzz/pkg/finding"
+	"code-intelligence.com/cifuzz/pkg/log"
+	"code-intelligence.com/cifuzz/pkg/parser/libfuzzer/stacktrace"
+)
+
+var logOutput io.ReadWriter
+
+func TestMain(m *testing.M) {
+	logOutput = bytes.NewBuffer([]byte{})
+	log.Output = logOutput
+
+	m.Run()
+}
+
+const reproducerTemplate = `import java.lang.reflect.Method;
+
+public class Crash_%s {
+    static final String base64Bytes = String.join("", "%s", "%s");
+
+    public static void main(String[] args) throws Throwable {
+        %s
+        com.example.FuzzTest.fuzzerTestOneInput(input);
+    }
+}
+`
+
+func writeReproducer(t *testing.T, path, decoding string, input []byte) {
+	name := strings.TrimSuffix(strings.TrimPrefix(filepath.Base(path), "Crash_"), ".java")
+	encoded := base64.StdEncoding.EncodeToString(input)
+	content := fmt.Sprintf(reproducerTemplate, name, encoded[:4], encoded[4:], decoding)
+	err := os.WriteFile(path, []byte(content), 0o644)
+	require.NoError(t, err)
+}
+
+func TestReadCrashingInput(t *testing.T) {
+	dir := t.TempDir()
+
+	// Crash files are read as they are
+	crashFile := filepath.Join(dir, "crash-0123")
+	err := os.WriteFile(crashFile, []byte("crash"), 0o644)
+	require.NoError(t, err)
+	input, err := readCrashingInput(crashFile)
+	require.NoError(t, err)
+	require.Equal(t, "crash", string(input))
+
+	// The input is decoded from reproducers of byte array fuzz tests
+	reproducer := filepath.Join(dir, "Crash_abcd.java")
+	writeReproducer(t, reproducer, jazzerRawInputDecoding, []byte("reproducer input"))
+	input, err = readCrashingInput(reproducer)
+	require.NoError(t, err)
+	require.Equal(t, "reproducer input", string(input))
+
+	// Reproducers of FuzzedDataProvider fuzz tests are not supported...
+	fdpReproducer := filepath.Join(dir, "Crash_0123.java")
+	writeReproducer(t, fdpReproducer,
+		"com.code_intelligence.jazzer.api.CannedFuzzedDataProvider input = "+
+			"new com.code_intelligence.jazzer.api.CannedFuzzedDataProvider(base64Bytes);",
+		[]byte("recorded values"))
+	// ... unless the crash file is next to them
+	input, err = readCrashingInput(fdpReproducer)
+	require.NoError(t, err)
+	require.Equal(t, "crash", string(input))
+	err = os.Remove(crashFile)
+	require.NoError(t, err)
+	_, err = readCrashingInput(fdpReproducer)
+	require.Error(t, err)
+}
+
+func TestImport(t *testing.T) {
+	projectDir := testutil.BootstrapEmptyProject(t, "test-import-")
+	seedCorpusDir := filepath.Join(projectDir, "seed_corpus")
+	filesDir := t.TempDir()
+
+	writeFile := func(name, content string) string {
+		path := filepath.Join(filesDir, name)
+		err := os.MkdirAll(filepath.Dir(path), 0o755)
+		require.NoError(t, err)
+		err = os.WriteFile(path, []byte(content), 0o644)
+		require.NoError(t, err)
+		return path
+	}
+	crashFile := writeFile("crash-0123", "A")
+	duplicateFile := writeFile(filepath.Join("old", "crash-0123"), "A")
+	oomFile := writeFile("oom-4567", "B")
+	noCrashFile := writeFile("crash-89ab", "no crash")
+
+	opts := &options{}
+	c := &importCmd{Command: newWithOptions(opts), opts: opts}
+	// Set the options after creating the command, because adding the
+	// flags resets them to their default values
+	opts.ProjectDir = projectDir
+	opts.FuzzTest = "my_fuzz_test"
+	err := opts.Dedup.Validate()
+	require.NoError(t, err)
+	c.build = func() (string, error) {
+		return seedCorpusDir, nil
+	}
+	c.replay = func(inputFile string) ([]*finding.Finding, error) {
+		input, err := os.ReadFile(inputFile)
+		if err != nil {
+			return nil, err
+		}
+		if string(input) == "no crash" {
+			return nil, nil
+		}
+		return []*finding.Finding{{
+			Type:       finding.ErrorTypeCrash,
+			Details:    "heap-buffer-overflow",
+			StackTrace: []*stacktrace.StackFrame{{Function: "parse_" + string(input), SourceFile: "parser.cpp"}},
+		}}, nil
+	}
+
+	err = c.run([]string{crashFile, duplicateFile, oomFile, noCrashFile})
+	require.NoError(t, err)
+	testutil.CheckOutput(t, logOutput, "Imported 2 findings (1 duplicates, 1 skipped)")
+
+	findings, err := finding.ListFindings(projectDir, nil)
+	require.NoError(t, err)
+	require.Len(t, findings, 2)
+	for _, f := range findings {
+		require.Equal(t, "my_fuzz_test", f.FuzzTest)
+		input, err := f.ReadInput(projectDir)
+		require.NoError(t, err)
+		require.Equal(t, "parse_"+string(input), f.StackTrace[0].Function)
+		// The input was added to the seed corpus
+		seed, err := os.ReadFile(filepath.Join(seedCorpusDir, f.Name))
+		require.NoError(t, err)
+		require.Equal(t, input, seed)
+	}
+
+	// Importing files which don't reproduce a finding fails
+	err = c.run([]string{noCrashFile})
+	require.Error(t, err)
+}
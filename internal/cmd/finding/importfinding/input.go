@@ -0,0 +1,81 @@
+package importfinding
+
+import (
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"code-intelligence.com/cifuzz/util/fileutil"
+)
+
+var (
+	// Jazzer writes a reproducer Crash_<sha1>.java next to each
+	// crash-<sha1> file
+	jazzerReproducerPattern = regexp.MustCompile(`^Crash_([0-9a-f]+)\.java$`)
+	// The crashing input is stored base64 encoded, split into chunks:
+	//     static final String base64Bytes = String.join("", "...", "...");
+	base64BytesPattern   = regexp.MustCompile(`(?s)base64Bytes\s*=\s*String\.join\(\s*""\s*,(.*?)\);`)
+	stringLiteralPattern = regexp.MustCompile(`"([^"]*)"`)
+)
+
+// Reproducers of fuzz tests which take a byte array decode the input
+// like this. For fuzz tests which take a FuzzedDataProvider, the
+// reproducer only contains the values consumed from it, not the input.
+const jazzerRawInputDecoding = "byte[] input = java.util.Base64.getDecoder().decode(base64Bytes);"
+
+// readCrashingInput returns the crashing input stored in the given
+// file, which is either a crash file written by libFuzzer or Jazzer
+// (e.g. crash-<sha1>, oom-<sha1> or timeout-<sha1>) or a Jazzer
+// reproducer (Crash_<sha1>.java).
+func readCrashingInput(path string) ([]byte, error) {
+	match := jazzerReproducerPattern.FindStringSubmatch(filepath.Base(path))
+	if match == nil {
+		input, err := os.ReadFile(path)
+		return input, errors.WithStack(err)
+	}
+
+	// Prefer the crash file which Jazzer wrote next to the reproducer,
+	// because it contains the input as it was passed to the fuzz test
+	crashFile := filepath.Join(filepath.Dir(path), "crash-"+match[1])
+	exists, err := fileutil.Exists(crashFile)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		input, err := os.ReadFile(crashFile)
+		return input, errors.WithStack(err)
+	}
+
+	reproducer, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return parseJazzerReproducer(string(reproducer))
+}
+
+// parseJazzerReproducer extracts the crashing input from the source
+// code of a Jazzer reproducer
+func parseJazzerReproducer(reproducer string) ([]byte, error) {
+	if !strings.Contains(reproducer, jazzerRawInputDecoding) {
+		return nil, errors.New("The reproducer doesn't contain the raw crashing input " +
+			"(only fuzz tests which take a byte array are supported), import the crash-<sha1> file instead")
+	}
+	match := base64BytesPattern.FindStringSubmatch(reproducer)
+	if match == nil {
+		return nil, errors.New("The reproducer doesn't contain the crashing input")
+	}
+
+	var encoded strings.Builder
+	for _, literal := range stringLiteralPattern.FindAllStringSubmatch(match[1], -1) {
+		encoded.WriteString(literal[1])
+	}
+	input, err := base64.StdEncoding.DecodeString(encoded.String())
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to decode the crashing input of the reproducer")
+	}
+	return input, nil
+}
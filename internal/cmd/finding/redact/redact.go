@@ -0,0 +1,167 @@
+package redact
+
+import (
+	"fmt"
+	"text/tabwriter"
+
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+
+	"code-intelligence.com/cifuzz/internal/cmdutils"
+	"code-intelligence.com/cifuzz/internal/completion"
+	"code-intelligence.com/cifuzz/internal/config"
+	"code-intelligence.com/cifuzz/pkg/finding"
+	"code-intelligence.com/cifuzz/pkg/log"
+)
+
+type options struct {
+	ProjectDir string                `mapstructure:"project-dir"`
+	ConfigDir  string                `mapstructure:"config-dir"`
+	Redact     finding.RedactOptions `mapstructure:"redact"`
+}
+
+func (opts *options) validate() error {
+	err := opts.Redact.Validate()
+	if err != nil {
+		log.Error(err)
+		return cmdutils.WrapSilentError(err)
+	}
+	return nil
+}
+
+type redactCmd struct {
+	*cobra.Command
+	opts *options
+}
+
+func New() *cobra.Command {
+	return newWithOptions(&options{})
+}
+
+func newWithOptions(opts *options) *cobra.Command {
+	var bindFlags func()
+
+	cmd := &cobra.Command{
+		Use:   "redact [name]...",
+		Short: "Show which data is redacted from findings before sharing them",
+		Long: `This command is a dry run of the redaction which is applied to the
+logs, details, stack traces and suggested owners of findings before
+they are uploaded by 'cifuzz run' (when logged in to CI Sense) or
+exported via 'cifuzz finding export'. For each finding, it prints the
+matches which would be replaced. Matched tokens are truncated. The
+findings are not modified.
+
+By default, the following built-in redactors are applied:
+
+    tokens      Access tokens (GitHub, GitLab, Slack, AWS, JWT, bearer
+                tokens) and values of variables like API_KEY or PASSWORD
+    emails      Email addresses
+    home-paths  Home directories like /home/<user> or C:\Users\<user>
+    user        The name of the current user
+    hostname    The hostname of the current machine
+
+Built-in redactors can be disabled and additional rules can be added via
+the "redact" setting in cifuzz.yaml:
+
+    redact:
+      disable-builtins: [hostname]
+      rules:
+        - name: internal-hosts
+          regex: '[a-z0-9-]+\.corp\.example\.com'
+          replacement: <internal-host>
+
+If no names are given, all findings are checked.
+`,
+		ValidArgsFunction: completion.ValidFindings,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			// Bind viper keys to flags. We can't do this in the New
+			// function, because that would re-bind viper keys which
+			// were bound to the flags of other commands before.
+			bindFlags()
+			err := config.FindAndParseProjectConfig(opts)
+			if err != nil {
+				log.Errorf(err, "Failed to parse cifuzz.yaml: %v", err.Error())
+				return cmdutils.WrapSilentError(err)
+			}
+			return opts.validate()
+		},
+		RunE: func(c *cobra.Command, args []string) error {
+			cmd := redactCmd{Command: c, opts: opts}
+			return cmd.run(args)
+		},
+	}
+
+	bindFlags = cmdutils.AddFlags(cmd,
+		cmdutils.AddProjectDirFlag,
+	)
+
+	return cmd
+}
+
+func (c *redactCmd) run(names []string) error {
+	var findings []*finding.Finding
+	if len(names) > 0 {
+		for _, name := range names {
+			f, err := finding.LoadFinding(c.opts.ProjectDir, name, nil)
+			if finding.IsNotExistError(err) {
+				log.Errorf(err, "Finding %s does not exist", name)
+				return cmdutils.WrapSilentError(err)
+			}
+			if err != nil {
+				return err
+			}
+			findings = append(findings, f)
+		}
+	} else {
+		var err error
+		findings, err = finding.ListFindings(c.opts.ProjectDir, nil)
+		if err != nil {
+			return err
+		}
+	}
+
+	numRedacted := 0
+	for _, f := range findings {
+		_, redactions := c.opts.Redact.Redact(f)
+		if len(redactions) == 0 {
+			continue
+		}
+		numRedacted++
+		err := c.printRedactions(f, redactions)
+		if err != nil {
+			return err
+		}
+	}
+
+	if numRedacted == 0 {
+		log.Printf("Nothing would be redacted from %d findings", len(findings))
+		return nil
+	}
+	log.Printf("Data would be redacted from %d of %d findings", numRedacted, len(findings))
+	return nil
+}
+
+// printRedactions prints each distinct redaction of the finding once,
+// with the number of times it was applied
+func (c *redactCmd) printRedactions(f *finding.Finding, redactions []*finding.Redaction) error {
+	_, _ = fmt.Fprintln(c.OutOrStdout(), pterm.Style{pterm.Reset, pterm.Bold}.Sprint(f.Name))
+
+	counts := make(map[finding.Redaction]int)
+	var unique []*finding.Redaction
+	for _, r := range redactions {
+		if counts[*r] == 0 {
+			unique = append(unique, r)
+		}
+		counts[*r]++
+	}
+
+	w := tabwriter.NewWriter(c.OutOrStdout(), 0, 0, 2, ' ', 0)
+	for _, r := range unique {
+		line := fmt.Sprintf("  %s\t%s\t%q -> %q", r.Field, r.Redactor, r.DisplayMatch(), r.Replacement)
+		if counts[*r] > 1 {
+			line += fmt.Sprintf(" (%dx)", counts[*r])
+		}
+		_, _ = fmt.Fprintln(w, line)
+	}
+	return w.Flush()
+}
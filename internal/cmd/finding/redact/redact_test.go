@@ -0,0 +1,54 @@
+package redact
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"code-intelligence.com/cifuzz/internal/cmdutils"
+	"code-intelligence.com/cifuzz/internal/testutil"
+	"code-intelligence.com/cifuzz/pkg/finding"
+	"code-intelligence.com/cifuzz/pkg/log"
+)
+
+var logOutput io.ReadWriter
+
+func TestMain(m *testing.M) {
+	logOutput = bytes.NewBuffer([]byte{})
+	log.Output = logOutput
+
+	m.Run()
+}
+
+func TestRedactCmd(t *testing.T) {
+	projectDir := testutil.BootstrapEmptyProject(t, "test-redact-cmd-")
+	logs := []string{"API_TOKEN=s3cr3t", "retrying with API_TOKEN=s3cr3t", "ok"}
+	for _, f := range []*finding.Finding{
+		{Name: "leaky_finding", Logs: logs},
+		{Name: "clean_finding", Logs: []string{"ok"}},
+	} {
+		err := f.Save(projectDir)
+		require.NoError(t, err)
+	}
+
+	opts := &options{ProjectDir: projectDir, ConfigDir: projectDir}
+	output, err := cmdutils.ExecuteCommand(t, newWithOptions(opts), os.Stdin)
+	require.NoError(t, err)
+	require.Contains(t, output, "leaky_finding")
+	// Tokens are not revealed in the output
+	require.Contains(t, output, `logs  tokens  "API_TOKEN=s****" -> "API_TOKEN=<token>" (2x)`)
+	require.NotContains(t, output, "s3cr3t")
+	require.NotContains(t, output, "clean_finding")
+
+	// The findings are not modified
+	f, err := finding.LoadFinding(projectDir, "leaky_finding", nil)
+	require.NoError(t, err)
+	require.Equal(t, logs, f.Logs)
+
+	opts = &options{ProjectDir: projectDir, ConfigDir: projectDir}
+	_, err = cmdutils.ExecuteCommand(t, newWithOptions(opts), os.Stdin, "no_such_finding")
+	require.Error(t, err)
+}
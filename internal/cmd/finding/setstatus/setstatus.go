@@ -0,0 +1,196 @@
+package setstatus
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"code-intelligence.com/cifuzz/internal/cmdutils"
+	"code-intelligence.com/cifuzz/internal/completion"
+	"code-intelligence.com/cifuzz/internal/config"
+	"code-intelligence.com/cifuzz/pkg/finding"
+	"code-intelligence.com/cifuzz/pkg/log"
+)
+
+type options struct {
+	ProjectDir string `mapstructure:"project-dir"`
+	ConfigDir  string `mapstructure:"config-dir"`
+	Note       string
+	Assignee   string
+	Cluster    bool
+	NumFrames  int
+	ShowAll    bool
+}
+
+func (opts *options) validate() error {
+	if opts.NumFrames < 0 {
+		msg := fmt.Sprintf("Invalid value %d for --frames, must not be negative", opts.NumFrames)
+		return cmdutils.WrapIncorrectUsageError(errors.New(msg))
+	}
+	return nil
+}
+
+type setStatusCmd struct {
+	*cobra.Command
+	opts *options
+}
+
+func New() *cobra.Command {
+	return newWithOptions(&options{})
+}
+
+func newWithOptions(opts *options) *cobra.Command {
+	var bindFlags func()
+
+	cmd := &cobra.Command{
+		Use:   "set-status <name> <status>",
+		Short: "Set the triage status of a finding",
+		Long: `This command sets the triage status of a finding. Valid statuses are:
+
+    open       The finding was not triaged yet (default)
+    confirmed  The finding was confirmed to be a bug
+    fixed      The bug was fixed
+    ignored    The finding is not relevant
+    wontfix    The bug will not be fixed
+
+Findings which are fixed, ignored or wontfix are closed and not listed
+by 'cifuzz finding' unless the --all flag is used. If a fixed finding is
+found again, it is reopened.
+
+With --cluster, the status is set for all findings which are in the
+same cluster as the specified finding, i.e. which have the same root
+cause but were possibly found by other fuzz tests (see
+'cifuzz finding clusters'). Like 'cifuzz finding clusters', closed and
+suppressed findings are not included unless the --all flag is used, and
+the number of crashing frames which findings must share can be changed
+via --frames.
+
+Examples:
+
+    cifuzz finding set-status funky_angelfish confirmed --assignee alice
+    cifuzz finding set-status funky_angelfish fixed --note "Fixed in #123"
+    cifuzz finding set-status funky_angelfish fixed --cluster
+`,
+		Args: cobra.ExactArgs(2),
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			if len(args) == 0 {
+				return completion.ValidFindings(cmd, args, toComplete)
+			}
+			if len(args) == 1 {
+				var statuses []string
+				for _, s := range finding.ValidStatuses {
+					statuses = append(statuses, string(s))
+				}
+				return statuses, cobra.ShellCompDirectiveNoFileComp
+			}
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		},
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			// Bind viper keys to flags. We can't do this in the New
+			// function, because that would re-bind viper keys which
+			// were bound to the flags of other commands before.
+			bindFlags()
+			err := config.FindAndParseProjectConfig(opts)
+			if err != nil {
+				log.Errorf(err, "Failed to parse cifuzz.yaml: %v", err.Error())
+				return cmdutils.WrapSilentError(err)
+			}
+			return opts.validate()
+		},
+		RunE: func(c *cobra.Command, args []string) error {
+			cmd := setStatusCmd{Command: c, opts: opts}
+			return cmd.run(args)
+		},
+	}
+
+	bindFlags = cmdutils.AddFlags(cmd,
+		cmdutils.AddProjectDirFlag,
+	)
+	cmd.Flags().StringVar(&opts.Note, "note", "", "Add a note to the finding.")
+	cmd.Flags().StringVar(&opts.Assignee, "assignee", "", "Assign the finding to the specified person or team.")
+	cmd.Flags().BoolVar(&opts.Cluster, "cluster", false, "Set the status of all findings in the same cluster as the finding.")
+	cmd.Flags().IntVar(&opts.NumFrames, "frames", finding.DefaultClusterFrames,
+		"Number of top crashing frames which findings must share to be in the same cluster (with --cluster).")
+	cmd.Flags().BoolVarP(&opts.ShowAll, "all", "a", false, "Include closed and suppressed findings in the cluster (with --cluster).")
+
+	return cmd
+}
+
+func (c *setStatusCmd) run(args []string) error {
+	findingName := args[0]
+
+	status, err := finding.ParseStatus(args[1])
+	if err != nil {
+		return cmdutils.WrapIncorrectUsageError(err)
+	}
+
+	// Don't enhance the finding with error details, because it's
+	// saved again
+	f, err := finding.LoadFinding(c.opts.ProjectDir, findingName, nil)
+	if finding.IsNotExistError(err) {
+		log.Errorf(err, "Finding %s does not exist", findingName)
+		return cmdutils.WrapSilentError(err)
+	}
+	if err != nil {
+		return err
+	}
+
+	findings := []*finding.Finding{f}
+	if c.opts.Cluster {
+		findings, err = c.clusterFindings(f)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, f := range findings {
+		f.SetStatus(status)
+		if c.Flags().Changed("assignee") {
+			f.Assignee = c.opts.Assignee
+		}
+		if c.opts.Note != "" {
+			f.AddNote(c.opts.Note)
+		}
+
+		err = f.Save(c.opts.ProjectDir)
+		if err != nil {
+			return err
+		}
+	}
+
+	if c.opts.Cluster {
+		log.Successf("Set status of %d findings in the cluster of finding %s to %s", len(findings), findingName, status)
+		return nil
+	}
+	log.Successf("Set status of finding %s to %s", f.Name, status)
+	return nil
+}
+
+// clusterFindings returns the findings which are in the same cluster as
+// the finding f, including f itself, in the same way as
+// `cifuzz finding clusters`
+func (c *setStatusCmd) clusterFindings(f *finding.Finding) ([]*finding.Finding, error) {
+	findings, err := finding.ListClusteredFindings(c.opts.ProjectDir, c.opts.ShowAll)
+	if err != nil {
+		return nil, err
+	}
+	// The specified finding is always included, even if it's closed
+	// or suppressed
+	included := false
+	for _, other := range findings {
+		if other.Name == f.Name {
+			included = true
+			break
+		}
+	}
+	if !included {
+		findings = append(findings, f)
+	}
+	clusters := finding.ClusterFindings(findings, c.opts.NumFrames)
+	cluster := finding.FindCluster(clusters, f.Name)
+	if cluster == nil {
+		return []*finding.Finding{f}, nil
+	}
+	return cluster.Findings, nil
+}
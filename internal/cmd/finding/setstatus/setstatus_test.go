@@ -0,0 +1,139 @@
+package setstatus
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"code-intelligence.com/cifuzz/internal/cmdutils"
+	"code-intelligence.com/cifuzz/internal/testutil"
+	"code-intelligence.com/cifuzz/pkg/finding"
+	"code-intelligence.com/cifuzz/pkg/log"
+	"code-intelligence.com/cifuzz/pkg/parser/libfuzzer/stacktrace"
+)
+
+var logOutput io.ReadWriter
+
+func TestMain(m *testing.M) {
+	logOutput = bytes.NewBuffer([]byte{})
+	log.Output = logOutput
+
+	m.Run()
+}
+
+func TestSetStatusCmd(t *testing.T) {
+	projectDir := testutil.BootstrapEmptyProject(t, "test-set-status-cmd-")
+	f := &finding.Finding{Name: "test_finding"}
+	err := f.Save(projectDir)
+	require.NoError(t, err)
+
+	opts := &options{ProjectDir: projectDir, ConfigDir: projectDir}
+	_, err = cmdutils.ExecuteCommand(t, newWithOptions(opts), os.Stdin,
+		"test_finding", "confirmed", "--assignee", "@team-x", "--note", "Reproduced locally")
+	require.NoError(t, err)
+
+	f, err = finding.LoadFinding(projectDir, "test_finding", nil)
+	require.NoError(t, err)
+	require.Equal(t, finding.StatusConfirmed, f.GetStatus())
+	require.Equal(t, "@team-x", f.Assignee)
+	require.Len(t, f.Notes, 1)
+	require.Equal(t, "Reproduced locally", f.Notes[0].Text)
+	require.NotNil(t, f.StatusUpdatedAt)
+
+	opts = &options{ProjectDir: projectDir, ConfigDir: projectDir}
+	_, err = cmdutils.ExecuteCommand(t, newWithOptions(opts), os.Stdin, "test_finding", "done")
+	require.Error(t, err)
+
+	opts = &options{ProjectDir: projectDir, ConfigDir: projectDir}
+	_, err = cmdutils.ExecuteCommand(t, newWithOptions(opts), os.Stdin, "no_such_finding", "fixed")
+	require.Error(t, err)
+}
+
+func TestSetStatusCmd_Cluster(t *testing.T) {
+	projectDir := testutil.BootstrapEmptyProject(t, "test-set-status-cmd-")
+	for _, f := range []*finding.Finding{
+		{
+			Name:        "first_finding",
+			FuzzTest:    "decode_fuzzer",
+			MoreDetails: &finding.ErrorDetails{ID: "heap_buffer_overflow"},
+			StackTrace: []*stacktrace.StackFrame{
+				{Function: "parse", SourceFile: "src/parser.cpp", Line: 10},
+				{Function: "LLVMFuzzerTestOneInputNoReturn", SourceFile: "fuzz/decode_fuzzer.cpp", Line: 5},
+			},
+		},
+		{
+			Name:        "second_finding",
+			FuzzTest:    "stream_fuzzer",
+			MoreDetails: &finding.ErrorDetails{ID: "heap_buffer_overflow"},
+			StackTrace: []*stacktrace.StackFrame{
+				{Function: "parse", SourceFile: "src/parser.cpp", Line: 10},
+				{Function: "LLVMFuzzerTestOneInputNoReturn", SourceFile: "fuzz/stream_fuzzer.cpp", Line: 8},
+			},
+		},
+		{
+			// Only shares the top frame with the other findings
+			Name:        "caller_finding",
+			FuzzTest:    "stream_fuzzer",
+			MoreDetails: &finding.ErrorDetails{ID: "heap_buffer_overflow"},
+			StackTrace: []*stacktrace.StackFrame{
+				{Function: "parse", SourceFile: "src/parser.cpp", Line: 10},
+				{Function: "decode", SourceFile: "src/decoder.cpp", Line: 20},
+				{Function: "LLVMFuzzerTestOneInputNoReturn", SourceFile: "fuzz/stream_fuzzer.cpp", Line: 8},
+			},
+			Status: finding.StatusOpen,
+		},
+		{
+			Name:        "ignored_finding",
+			FuzzTest:    "decode_fuzzer",
+			MoreDetails: &finding.ErrorDetails{ID: "heap_buffer_overflow"},
+			StackTrace: []*stacktrace.StackFrame{
+				{Function: "parse", SourceFile: "src/parser.cpp", Line: 10},
+				{Function: "LLVMFuzzerTestOneInputNoReturn", SourceFile: "fuzz/decode_fuzzer.cpp", Line: 5},
+			},
+			Status: finding.StatusIgnored,
+		},
+		{Name: "unrelated_finding", MoreDetails: &finding.ErrorDetails{ID: "memory_leak"}},
+	} {
+		err := f.Save(projectDir)
+		require.NoError(t, err)
+	}
+
+	opts := &options{ProjectDir: projectDir, ConfigDir: projectDir}
+	_, err := cmdutils.ExecuteCommand(t, newWithOptions(opts), os.Stdin,
+		"first_finding", "confirmed", "--cluster")
+	require.NoError(t, err)
+
+	// Closed findings and findings which don't share all top frames
+	// are not included
+	for name, status := range map[string]finding.Status{
+		"first_finding":     finding.StatusConfirmed,
+		"second_finding":    finding.StatusConfirmed,
+		"caller_finding":    finding.StatusOpen,
+		"ignored_finding":   finding.StatusIgnored,
+		"unrelated_finding": finding.StatusOpen,
+	} {
+		f, err := finding.LoadFinding(projectDir, name, nil)
+		require.NoError(t, err)
+		require.Equal(t, status, f.GetStatus(), name)
+	}
+
+	opts = &options{ProjectDir: projectDir, ConfigDir: projectDir}
+	_, err = cmdutils.ExecuteCommand(t, newWithOptions(opts), os.Stdin,
+		"first_finding", "fixed", "--cluster", "--frames", "1", "--note", "Fixed in #42")
+	require.NoError(t, err)
+
+	for name, status := range map[string]finding.Status{
+		"first_finding":     finding.StatusFixed,
+		"second_finding":    finding.StatusFixed,
+		"caller_finding":    finding.StatusFixed,
+		"ignored_finding":   finding.StatusIgnored,
+		"unrelated_finding": finding.StatusOpen,
+	} {
+		f, err := finding.LoadFinding(projectDir, name, nil)
+		require.NoError(t, err)
+		require.Equal(t, status, f.GetStatus(), name)
+	}
+}
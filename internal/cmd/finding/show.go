@@ -0,0 +1,83 @@
+package finding
+
+import (
+	"github.com/spf13/cobra"
+
+	"code-intelligence.com/cifuzz/internal/cmdutils"
+	"code-intelligence.com/cifuzz/internal/completion"
+	"code-intelligence.com/cifuzz/internal/config"
+	"code-intelligence.com/cifuzz/pkg/log"
+)
+
+func newShowCommand() *cobra.Command {
+	return newShowWithOptions(&options{})
+}
+
+func newShowWithOptions(opts *options) *cobra.Command {
+	var bindFlags func()
+
+	cmd := &cobra.Command{
+		Use:   "show <name>",
+		Short: "Show a finding",
+		Long: `This command prints the information available for the specified
+finding, like 'cifuzz finding <name>'.
+
+With --decode, the fuzz test of the finding is built and executed with
+the crashing input, to determine the values which the fuzz test consumes
+via the FuzzedDataProvider, e.g.
+
+    ConsumeIntegral<int>() = 42
+    ConsumeRandomLengthString() = "ab"
+
+The values are stored in the finding. C/C++ fuzz tests must use the
+FuzzedDataProvider shipped with cifuzz
+(include/fuzzer/FuzzedDataProvider.h). For Java fuzz tests, the calls
+of Jazzer's FuzzedDataProvider are logged via a Jazzer hook, e.g.
+
+    consumeInt(0, 10) = 7
+    consumeString(20) = "ab"
+`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completion.ValidFindings,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			// Bind viper keys to flags. We can't do this in the New
+			// function, because that would re-bind viper keys which
+			// were bound to the flags of other commands before.
+			bindFlags()
+			err := config.FindAndParseProjectConfig(opts)
+			if err != nil {
+				log.Errorf(err, "Failed to parse cifuzz.yaml: %v", err.Error())
+				return cmdutils.WrapSilentError(err)
+			}
+			return opts.validateShowOptions()
+		},
+		RunE: func(c *cobra.Command, args []string) error {
+			cmd, err := newFindingCmd(c, opts)
+			if err != nil {
+				return err
+			}
+			_, errorDetails, err := cmd.authenticate()
+			if err != nil {
+				return err
+			}
+			return cmd.show(args[0], errorDetails)
+		},
+	}
+
+	// Note: If a flag should be configurable via viper as well (i.e.
+	//       via cifuzz.yaml and CIFUZZ_* environment variables), bind
+	//       it to viper in the PreRun function.
+	bindFlags = cmdutils.AddFlags(cmd,
+		cmdutils.AddPrintJSONFlag,
+		cmdutils.AddProjectDirFlag,
+		cmdutils.AddInteractiveFlag,
+		cmdutils.AddServerFlag,
+	)
+
+	addNumFramesFlag(cmd, opts)
+	cmd.Flags().BoolVar(&opts.Decode, "decode", false,
+		"Build the fuzz test and execute it with the crashing input to determine the values\n"+
+			"which it consumes via the FuzzedDataProvider. The values are stored in the finding.")
+
+	return cmd
+}
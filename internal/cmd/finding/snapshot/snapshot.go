@@ -0,0 +1,102 @@
+package snapshot
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"code-intelligence.com/cifuzz/internal/cmdutils"
+	"code-intelligence.com/cifuzz/internal/config"
+	"code-intelligence.com/cifuzz/pkg/finding"
+	"code-intelligence.com/cifuzz/pkg/log"
+	"code-intelligence.com/cifuzz/util/stringutil"
+)
+
+type options struct {
+	ProjectDir string               `mapstructure:"project-dir"`
+	ConfigDir  string               `mapstructure:"config-dir"`
+	Dedup      finding.DedupOptions `mapstructure:"dedup"`
+	OutputPath string
+}
+
+type snapshotCmd struct {
+	*cobra.Command
+	opts *options
+}
+
+func New() *cobra.Command {
+	return newWithOptions(&options{})
+}
+
+func newWithOptions(opts *options) *cobra.Command {
+	var bindFlags func()
+
+	cmd := &cobra.Command{
+		Use:   "snapshot",
+		Short: "Export a snapshot of the current findings",
+		Long: `This command prints a snapshot of the current findings in JSON format.
+The snapshot can be passed to 'cifuzz finding diff --base' later to
+determine which findings are new, for example in a pull request check:
+
+    cifuzz finding snapshot > base.json
+
+Closed findings and findings which are suppressed are not included in
+the snapshot.
+`,
+		Args: cobra.NoArgs,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			// Bind viper keys to flags. We can't do this in the New
+			// function, because that would re-bind viper keys which
+			// were bound to the flags of other commands before.
+			bindFlags()
+			err := config.FindAndParseProjectConfig(opts)
+			if err != nil {
+				log.Errorf(err, "Failed to parse cifuzz.yaml: %v", err.Error())
+				return cmdutils.WrapSilentError(err)
+			}
+			err = opts.Dedup.Validate()
+			if err != nil {
+				log.Error(err)
+				return cmdutils.WrapSilentError(err)
+			}
+			return nil
+		},
+		RunE: func(c *cobra.Command, args []string) error {
+			cmd := snapshotCmd{Command: c, opts: opts}
+			return cmd.run()
+		},
+	}
+
+	bindFlags = cmdutils.AddFlags(cmd,
+		cmdutils.AddProjectDirFlag,
+	)
+	cmd.Flags().StringVarP(&opts.OutputPath, "output", "o", "", "Write the snapshot to the specified file instead of stdout.")
+
+	return cmd
+}
+
+func (c *snapshotCmd) run() error {
+	snapshot, err := finding.CreateSnapshot(c.opts.ProjectDir, &c.opts.Dedup)
+	if err != nil {
+		return err
+	}
+
+	s, err := stringutil.ToJSONString(snapshot)
+	if err != nil {
+		return err
+	}
+
+	if c.opts.OutputPath == "" {
+		_, _ = fmt.Fprintln(c.OutOrStdout(), s)
+		return nil
+	}
+
+	err = os.WriteFile(c.opts.OutputPath, []byte(s+"\n"), 0o644)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	log.Successf("Wrote snapshot of %d findings to %s", len(snapshot.Findings), c.opts.OutputPath)
+	return nil
+}
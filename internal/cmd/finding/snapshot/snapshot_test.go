@@ -0,0 +1,57 @@
+package snapshot
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"code-intelligence.com/cifuzz/internal/cmdutils"
+	"code-intelligence.com/cifuzz/internal/testutil"
+	"code-intelligence.com/cifuzz/pkg/finding"
+	"code-intelligence.com/cifuzz/pkg/log"
+)
+
+var logOutput io.ReadWriter
+
+func TestMain(m *testing.M) {
+	logOutput = bytes.NewBuffer([]byte{})
+	log.Output = logOutput
+
+	m.Run()
+}
+
+func TestSnapshotCmd(t *testing.T) {
+	projectDir := testutil.BootstrapEmptyProject(t, "test-snapshot-cmd-")
+
+	f := &finding.Finding{
+		Name:        "my_finding",
+		MoreDetails: &finding.ErrorDetails{ID: "heap_buffer_overflow"},
+	}
+	err := f.Save(projectDir)
+	require.NoError(t, err)
+
+	opts := &options{ProjectDir: projectDir, ConfigDir: projectDir}
+	output, err := cmdutils.ExecuteCommand(t, newWithOptions(opts), os.Stdin)
+	require.NoError(t, err)
+	var snapshot finding.Snapshot
+	err = json.Unmarshal([]byte(output), &snapshot)
+	require.NoError(t, err)
+	require.Len(t, snapshot.Findings, 1)
+	require.Equal(t, "my_finding", snapshot.Findings[0].Name)
+	// The severity is taken from the error details shipped with cifuzz
+	require.NotNil(t, snapshot.Findings[0].Severity)
+
+	// The snapshot can be written to a file which can be loaded again
+	outputPath := filepath.Join(projectDir, "base.json")
+	opts = &options{ProjectDir: projectDir, ConfigDir: projectDir}
+	_, err = cmdutils.ExecuteCommand(t, newWithOptions(opts), os.Stdin, "-o", outputPath)
+	require.NoError(t, err)
+	loaded, err := finding.LoadSnapshot(outputPath)
+	require.NoError(t, err)
+	require.Equal(t, snapshot.Findings, loaded.Findings)
+}
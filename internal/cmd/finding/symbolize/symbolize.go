@@ -0,0 +1,131 @@
+package symbolize
+
+import (
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"code-intelligence.com/cifuzz/internal/cmdutils"
+	"code-intelligence.com/cifuzz/internal/completion"
+	"code-intelligence.com/cifuzz/internal/config"
+	"code-intelligence.com/cifuzz/pkg/finding"
+	"code-intelligence.com/cifuzz/pkg/log"
+	"code-intelligence.com/cifuzz/util/fileutil"
+)
+
+type options struct {
+	ProjectDir string `mapstructure:"project-dir"`
+	ConfigDir  string `mapstructure:"config-dir"`
+
+	Binary string
+}
+
+type symbolizeCmd struct {
+	*cobra.Command
+	opts *options
+}
+
+func New() *cobra.Command {
+	return newWithOptions(&options{})
+}
+
+func newWithOptions(opts *options) *cobra.Command {
+	var bindFlags func()
+
+	cmd := &cobra.Command{
+		Use:   "symbolize <name>",
+		Short: "Symbolize the stack trace of a finding",
+		Long: `This command symbolizes the stack frames of a finding which were not
+symbolized when the finding was reported, for example because the fuzz
+test was executed without a working llvm-symbolizer. The source files,
+lines and functions of the frames are determined with llvm-symbolizer
+from the binary of the fuzz test and stored in the finding.
+
+By default, the binaries which are referenced in the stack frames are
+used. If the fuzz test binary was moved or built on another machine,
+specify its path via --binary.
+
+Findings without any symbolized stack frames are symbolized
+automatically when they are loaded, if the binary still exists, but
+the result is only stored by this command.
+`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completion.ValidFindings,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			// Bind viper keys to flags. We can't do this in the New
+			// function, because that would re-bind viper keys which
+			// were bound to the flags of other commands before.
+			bindFlags()
+			err := config.FindAndParseProjectConfig(opts)
+			if err != nil {
+				log.Errorf(err, "Failed to parse cifuzz.yaml: %v", err.Error())
+				return cmdutils.WrapSilentError(err)
+			}
+			if opts.Binary != "" {
+				exists, err := fileutil.Exists(opts.Binary)
+				if err != nil {
+					return err
+				}
+				if !exists {
+					return cmdutils.WrapIncorrectUsageError(errors.Errorf("Binary %s does not exist", opts.Binary))
+				}
+			}
+			return nil
+		},
+		RunE: func(c *cobra.Command, args []string) error {
+			cmd := symbolizeCmd{Command: c, opts: opts}
+			return cmd.run(args[0])
+		},
+	}
+
+	bindFlags = cmdutils.AddFlags(cmd,
+		cmdutils.AddProjectDirFlag,
+	)
+	cmd.Flags().StringVar(&opts.Binary, "binary", "", "Path to the fuzz test binary to use for symbolization.")
+
+	return cmd
+}
+
+func (c *symbolizeCmd) run(findingName string) error {
+	// LoadFinding would already symbolize the finding in memory, we
+	// want to symbolize the stored finding and save the result
+	f, err := finding.LoadStoredFinding(c.opts.ProjectDir, findingName)
+	if finding.IsNotExistError(err) {
+		log.Errorf(err, "Finding %s does not exist", findingName)
+		return cmdutils.WrapSilentError(err)
+	}
+	if err != nil {
+		return err
+	}
+
+	if !f.NeedsSymbolization() {
+		log.Successf("The stack trace of finding %s is already symbolized", f.Name)
+		return nil
+	}
+
+	numSymbolized, err := f.Symbolize(&finding.SymbolizeOptions{
+		ProjectDir: c.opts.ProjectDir,
+		Binary:     c.opts.Binary,
+	})
+	if err != nil {
+		log.Errorf(err, "Failed to symbolize finding %s: %v", f.Name, err.Error())
+		return cmdutils.WrapSilentError(err)
+	}
+	if numSymbolized == 0 {
+		err = errors.Errorf("None of the stack frames of finding %s could be symbolized", f.Name)
+		if c.opts.Binary == "" {
+			log.Errorf(err, "%s. Please specify the fuzz test binary via --binary.", err.Error())
+		} else {
+			log.Error(err)
+		}
+		return cmdutils.WrapSilentError(err)
+	}
+
+	err = f.Save(c.opts.ProjectDir)
+	if err != nil {
+		return err
+	}
+
+	log.Successf("Symbolized %d stack frames of finding %s", numSymbolized, f.Name)
+	log.Print(f.ShortDescriptionWithName())
+	return nil
+}
@@ -0,0 +1,58 @@
+package symbolize
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"code-intelligence.com/cifuzz/internal/cmdutils"
+	"code-intelligence.com/cifuzz/internal/testutil"
+	"code-intelligence.com/cifuzz/pkg/finding"
+	"code-intelligence.com/cifuzz/pkg/log"
+)
+
+var logOutput io.ReadWriter
+
+func TestMain(m *testing.M) {
+	logOutput = bytes.NewBuffer([]byte{})
+	log.Output = logOutput
+
+	m.Run()
+}
+
+func TestSymbolizeCmd(t *testing.T) {
+	projectDir := testutil.BootstrapEmptyProject(t, "test-symbolize-cmd-")
+
+	symbolized := &finding.Finding{
+		Name: "symbolized_finding",
+		Logs: []string{"    #0 0x55d4f1a2b3c4 in parse /src/parser.cpp:12:3"},
+	}
+	err := symbolized.Save(projectDir)
+	require.NoError(t, err)
+
+	opts := &options{ProjectDir: projectDir, ConfigDir: projectDir}
+	_, err = cmdutils.ExecuteCommand(t, newWithOptions(opts), os.Stdin, "symbolized_finding")
+	require.NoError(t, err)
+	testutil.CheckOutput(t, logOutput, "already symbolized")
+
+	unsymbolized := &finding.Finding{
+		Name: "unsymbolized_finding",
+		Logs: []string{"    #0 0x55d4f1a2b3c4  (" + filepath.Join(projectDir, "no-such-binary") + "+0x1234)"},
+	}
+	err = unsymbolized.Save(projectDir)
+	require.NoError(t, err)
+
+	// The binary in the stack trace doesn't exist
+	opts = &options{ProjectDir: projectDir, ConfigDir: projectDir}
+	_, err = cmdutils.ExecuteCommand(t, newWithOptions(opts), os.Stdin, "unsymbolized_finding")
+	require.Error(t, err)
+	testutil.CheckOutput(t, logOutput, "Please specify the fuzz test binary via --binary")
+
+	opts = &options{ProjectDir: projectDir, ConfigDir: projectDir}
+	_, err = cmdutils.ExecuteCommand(t, newWithOptions(opts), os.Stdin, "unsymbolized_finding", "--binary", "no-such-binary")
+	require.Error(t, err)
+}
@@ -0,0 +1,334 @@
+package report
+
+import (
+	_ "embed"
+	"encoding/hex"
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	texttemplate "text/template"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"code-intelligence.com/cifuzz/internal/api"
+	"code-intelligence.com/cifuzz/internal/cmdutils"
+	"code-intelligence.com/cifuzz/internal/cmdutils/auth"
+	"code-intelligence.com/cifuzz/internal/config"
+	"code-intelligence.com/cifuzz/pkg/finding"
+	"code-intelligence.com/cifuzz/pkg/log"
+	"code-intelligence.com/cifuzz/pkg/report"
+	"code-intelligence.com/cifuzz/util/fileutil"
+	"code-intelligence.com/cifuzz/util/stringutil"
+)
+
+const (
+	FormatHTML     = "html"
+	FormatMarkdown = "markdown"
+)
+
+var validFormats = []string{FormatHTML, FormatMarkdown}
+
+// The maximum number of bytes of the crashing input which are included
+// in the hex dump, to keep the report readable for large inputs.
+const maxHexDumpBytes = 1024
+
+//go:embed report.html.tmpl
+var htmlTemplate string
+
+//go:embed report.md.tmpl
+var markdownTemplate string
+
+type options struct {
+	OutputFormat string `mapstructure:"format"`
+	OutputPath   string `mapstructure:"output"`
+	ProjectDir   string `mapstructure:"project-dir"`
+	ConfigDir    string `mapstructure:"config-dir"`
+	Server       string `mapstructure:"server"`
+}
+
+func (opts *options) validate() error {
+	if !stringutil.Contains(validFormats, opts.OutputFormat) {
+		msg := fmt.Sprintf("Flag \"format\" must be %s", strings.Join(validFormats, " or "))
+		return cmdutils.WrapIncorrectUsageError(errors.New(msg))
+	}
+	return nil
+}
+
+type reportCmd struct {
+	*cobra.Command
+	opts *options
+}
+
+type reportData struct {
+	Project     string
+	GeneratedAt time.Time
+	Version     string
+	Findings    []*findingData
+	Runs        []*runData
+}
+
+type findingData struct {
+	*finding.Finding
+	Severity      string
+	SeverityLevel string
+	Description   string
+	Location      string
+	HexDump       string
+	InputSize     int
+	Truncated     bool
+}
+
+type runData struct {
+	*report.RunSummary
+	Duration       string
+	AverageExecs   uint64
+	NumFindings    int
+	FinishedAtTime string
+}
+
+func New() *cobra.Command {
+	return newWithOptions(&options{})
+}
+
+func newWithOptions(opts *options) *cobra.Command {
+	var bindFlags func()
+
+	cmd := &cobra.Command{
+		Use:   "report [flags]",
+		Short: "Create a findings report",
+		Long: `This command creates a self-contained report of all findings of the
+project, which can be shared with others, for example for security
+reviews.
+
+For each finding, the report includes the severity, description,
+mitigation and links (if available), the stack trace and a hex dump of
+the crashing input. The report also includes the metrics of the last
+run of each fuzz test.
+
+The report is printed to stdout unless an output path is specified:
+
+    cifuzz report --format=html --output findings.html
+    cifuzz report --format=markdown > findings.md
+`,
+		Args: cobra.NoArgs,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			// Bind viper keys to flags. We can't do this in the New
+			// function, because that would re-bind viper keys which
+			// were bound to the flags of other commands before.
+			bindFlags()
+			cmdutils.ViperMustBindPFlag("format", cmd.Flags().Lookup("format"))
+			cmdutils.ViperMustBindPFlag("output", cmd.Flags().Lookup("output"))
+
+			err := config.FindAndParseProjectConfig(opts)
+			if err != nil {
+				log.Errorf(err, "Failed to parse cifuzz.yaml: %v", err.Error())
+				return cmdutils.WrapSilentError(err)
+			}
+			return opts.validate()
+		},
+		RunE: func(c *cobra.Command, args []string) error {
+			var err error
+			opts.Server, err = api.ValidateAndNormalizeServerURL(viper.GetString("server"))
+			if err != nil {
+				return err
+			}
+			cmd := reportCmd{Command: c, opts: opts}
+			return cmd.run()
+		},
+	}
+
+	// Note: If a flag should be configurable via viper as well (i.e.
+	//       via cifuzz.yaml and CIFUZZ_* environment variables), bind
+	//       it to viper in the PreRun function.
+	bindFlags = cmdutils.AddFlags(cmd,
+		cmdutils.AddProjectDirFlag,
+		cmdutils.AddServerFlag,
+	)
+	cmd.Flags().StringP("format", "f", FormatHTML, "Output format of the report (html/markdown).")
+	cmd.Flags().StringP("output", "o", "", "Output path of the report. By default, the report is printed to stdout.")
+
+	return cmd
+}
+
+func (c *reportCmd) run() error {
+	errorDetails, err := auth.GetErrorDetails(c.opts.Server, c.Root().Version)
+	if err != nil {
+		return err
+	}
+
+	findings, err := finding.ListFindings(c.opts.ProjectDir, errorDetails)
+	if err != nil {
+		return err
+	}
+
+	// Suppressed findings are not included in the report
+	suppressions, err := finding.LoadSuppressions(c.opts.ProjectDir)
+	if err != nil {
+		return err
+	}
+	var unsuppressed []*finding.Finding
+	for _, f := range findings {
+		if suppressions.Match(f) == nil {
+			unsuppressed = append(unsuppressed, f)
+		}
+	}
+	findings = unsuppressed
+
+	runs, err := report.LoadRunSummaries(c.opts.ProjectDir)
+	if err != nil {
+		return err
+	}
+
+	data := &reportData{
+		Project:     filepath.Base(c.opts.ProjectDir),
+		GeneratedAt: time.Now(),
+		Version:     c.Root().Version,
+	}
+	for _, f := range findings {
+		fd, err := c.newFindingData(f)
+		if err != nil {
+			return err
+		}
+		data.Findings = append(data.Findings, fd)
+	}
+	for _, r := range runs {
+		data.Runs = append(data.Runs, newRunData(r))
+	}
+
+	// Show the most severe findings first. ListFindings already sorted
+	// the findings by date, which we keep for findings of the same
+	// severity.
+	sort.SliceStable(data.Findings, func(i, j int) bool {
+		return severityScore(data.Findings[i].Finding) > severityScore(data.Findings[j].Finding)
+	})
+
+	var out io.Writer = c.OutOrStdout()
+	if c.opts.OutputPath != "" {
+		file, err := os.Create(c.opts.OutputPath)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		defer file.Close()
+		out = file
+	}
+
+	err = render(out, c.opts.OutputFormat, data)
+	if err != nil {
+		return err
+	}
+
+	if c.opts.OutputPath != "" {
+		log.Successf("Created findings report: %s", fileutil.PrettifyPath(c.opts.OutputPath))
+	}
+	return nil
+}
+
+func render(out io.Writer, format string, data *reportData) error {
+	var err error
+	switch format {
+	case FormatHTML:
+		var t *htmltemplate.Template
+		t, err = htmltemplate.New("report").Funcs(htmltemplate.FuncMap(templateFuncs)).Parse(htmlTemplate)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		err = t.Execute(out, data)
+	case FormatMarkdown:
+		var t *texttemplate.Template
+		t, err = texttemplate.New("report").Funcs(templateFuncs).Parse(markdownTemplate)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		err = t.Execute(out, data)
+	default:
+		return errors.Errorf("Unsupported output format %q", format)
+	}
+	return errors.WithStack(err)
+}
+
+var templateFuncs = texttemplate.FuncMap{
+	"join": strings.Join,
+	"date": func(t time.Time) string {
+		return t.Format("2006-01-02 15:04:05 MST")
+	},
+	// mdEscape escapes characters which would break markdown tables
+	"mdEscape": func(s string) string {
+		return strings.NewReplacer("|", `\|`, "\n", " ").Replace(s)
+	},
+}
+
+func (c *reportCmd) newFindingData(f *finding.Finding) (*findingData, error) {
+	fd := &findingData{
+		Finding:       f,
+		Severity:      "n/a",
+		SeverityLevel: "unknown",
+	}
+
+	columns := f.ShortDescriptionColumns()
+	fd.Description = columns[0]
+	if len(f.StackTrace) > 0 {
+		fd.Location = fmt.Sprintf("%s (%s)", f.StackTrace[0].Function, f.StackTrace[0].Location())
+	}
+
+	if f.MoreDetails != nil && f.MoreDetails.Severity != nil {
+		fd.Severity = fmt.Sprintf("%.1f", f.MoreDetails.Severity.Score)
+		if f.MoreDetails.Severity.Level != "" {
+			fd.SeverityLevel = strings.ToLower(string(f.MoreDetails.Severity.Level))
+		}
+	}
+
+	input, err := c.crashingInput(f)
+	if err != nil {
+		return nil, err
+	}
+	fd.InputSize = len(input)
+	if len(input) > maxHexDumpBytes {
+		input = input[:maxHexDumpBytes]
+		fd.Truncated = true
+	}
+	fd.HexDump = hex.Dump(input)
+
+	return fd, nil
+}
+
+// crashingInput returns the input which triggered the finding, either
+// from the finding itself or from the crashing input file stored in
+// the finding directory.
+func (c *reportCmd) crashingInput(f *finding.Finding) ([]byte, error) {
+	if len(f.InputData) > 0 || f.InputFile == "" {
+		return f.InputData, nil
+	}
+	input, err := os.ReadFile(filepath.Join(c.opts.ProjectDir, f.InputFile))
+	if os.IsNotExist(err) {
+		log.Debugf("Crashing input of finding %s not found: %v", f.Name, err)
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return input, nil
+}
+
+func newRunData(r *report.RunSummary) *runData {
+	return &runData{
+		RunSummary:     r,
+		Duration:       r.Duration.Round(time.Second).String(),
+		AverageExecs:   r.AverageExecutionsPerSecond(),
+		NumFindings:    len(r.Findings),
+		FinishedAtTime: r.StartedAt.Add(r.Duration).Format("2006-01-02 15:04:05 MST"),
+	}
+}
+
+func severityScore(f *finding.Finding) float32 {
+	if f.MoreDetails == nil || f.MoreDetails.Severity == nil {
+		return -1
+	}
+	return f.MoreDetails.Severity.Score
+}
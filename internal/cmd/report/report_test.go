@@ -0,0 +1,104 @@
+package report
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"code-intelligence.com/cifuzz/internal/cmdutils"
+	"code-intelligence.com/cifuzz/internal/testutil"
+	"code-intelligence.com/cifuzz/pkg/finding"
+	"code-intelligence.com/cifuzz/pkg/log"
+	"code-intelligence.com/cifuzz/pkg/parser/libfuzzer/stacktrace"
+	"code-intelligence.com/cifuzz/pkg/report"
+)
+
+var logOutput io.ReadWriter
+
+func TestMain(m *testing.M) {
+	logOutput = bytes.NewBuffer([]byte{})
+	log.Output = logOutput
+
+	m.Run()
+}
+
+func TestReportCmd_InvalidFormat(t *testing.T) {
+	projectDir := testutil.BootstrapEmptyProject(t, "test-report-cmd-")
+	opts := &options{
+		ProjectDir: projectDir,
+		ConfigDir:  projectDir,
+	}
+
+	_, err := cmdutils.ExecuteCommand(t, newWithOptions(opts), os.Stdin, "--format", "pdf")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "Flag \"format\" must be html or markdown")
+}
+
+func TestReportCmd(t *testing.T) {
+	projectDir := testutil.BootstrapEmptyProject(t, "test-report-cmd-")
+	opts := &options{
+		ProjectDir: projectDir,
+		ConfigDir:  projectDir,
+	}
+
+	f := &finding.Finding{
+		Name:      "funky_fox",
+		Type:      finding.ErrorTypeCrash,
+		Details:   "heap-buffer-overflow on address 0x1234",
+		InputData: []byte("FUZZ<script>"),
+		Logs:      []string{"==1==ERROR: AddressSanitizer: heap-buffer-overflow"},
+		StackTrace: []*stacktrace.StackFrame{{
+			SourceFile: "src/explore_me.cpp",
+			Line:       18,
+			Column:     11,
+			Function:   "exploreMe",
+		}},
+		MoreDetails: &finding.ErrorDetails{
+			ID:         "heap_buffer_overflow",
+			Mitigation: "Check the bounds of the buffer",
+			Severity: &finding.Severity{
+				Level: finding.SeverityLevelCritical,
+				Score: 9,
+			},
+		},
+		FuzzTest: "my_fuzz_test",
+	}
+	err := f.Save(projectDir)
+	require.NoError(t, err)
+
+	run := &report.RunSummary{
+		FuzzTest:  "my_fuzz_test",
+		StartedAt: time.Now(),
+		Duration:  time.Minute,
+		LastMetrics: &report.FuzzingMetric{
+			TotalExecutions: 123456,
+		},
+		Findings: []string{f.Name},
+	}
+	err = run.Save(projectDir)
+	require.NoError(t, err)
+
+	output, err := cmdutils.ExecuteCommand(t, newWithOptions(opts), os.Stdin, "--format", "markdown")
+	require.NoError(t, err)
+	require.Contains(t, output, "## funky_fox")
+	require.Contains(t, output, "| 9.0 | [funky_fox](#funky_fox) | heap buffer overflow | my_fuzz_test | exploreMe (src/explore_me.cpp:18:11) |")
+	require.Contains(t, output, "Check the bounds of the buffer")
+	require.Contains(t, output, "#0 exploreMe src/explore_me.cpp:18:11")
+	require.Contains(t, output, "46 55 5a 5a")
+	require.Contains(t, output, "123456")
+
+	outputPath := filepath.Join(projectDir, "report.html")
+	_, err = cmdutils.ExecuteCommand(t, newWithOptions(opts), os.Stdin, "--format", "html", "--output", outputPath)
+	require.NoError(t, err)
+	html, err := os.ReadFile(outputPath)
+	require.NoError(t, err)
+	require.Contains(t, string(html), `<h2 id="funky_fox">funky_fox</h2>`)
+	require.Contains(t, string(html), "severity-critical")
+	// The crashing input must be escaped in the HTML report
+	require.Contains(t, string(html), "FUZZ&lt;script&gt;")
+}
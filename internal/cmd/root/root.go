@@ -16,12 +16,14 @@ import (
 	bundleCmd "code-intelligence.com/cifuzz/internal/cmd/bundle"
 	coverageCmd "code-intelligence.com/cifuzz/internal/cmd/coverage"
 	createCmd "code-intelligence.com/cifuzz/internal/cmd/create"
+	debugCmd "code-intelligence.com/cifuzz/internal/cmd/debug"
 	findingCmd "code-intelligence.com/cifuzz/internal/cmd/finding"
 	initCmd "code-intelligence.com/cifuzz/internal/cmd/init"
 	integrateCmd "code-intelligence.com/cifuzz/internal/cmd/integrate"
 	loginCmd "code-intelligence.com/cifuzz/internal/cmd/login"
 	reloadCmd "code-intelligence.com/cifuzz/internal/cmd/reload"
 	remoteRunCmd "code-intelligence.com/cifuzz/internal/cmd/remoterun"
+	reportCmd "code-intelligence.com/cifuzz/internal/cmd/report"
 	runCmd "code-intelligence.com/cifuzz/internal/cmd/run"
 	"code-intelligence.com/cifuzz/internal/cmdutils"
 	"code-intelligence.com/cifuzz/internal/config"
@@ -96,7 +98,9 @@ func New() (*cobra.Command, error) {
 	rootCmd.AddCommand(reloadCmd.New())
 	rootCmd.AddCommand(bundleCmd.New())
 	rootCmd.AddCommand(coverageCmd.New())
+	rootCmd.AddCommand(debugCmd.New())
 	rootCmd.AddCommand(findingCmd.New())
+	rootCmd.AddCommand(reportCmd.New())
 	rootCmd.AddCommand(integrateCmd.New())
 
 	return rootCmd, nil
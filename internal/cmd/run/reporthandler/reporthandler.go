@@ -21,7 +21,9 @@ import (
 	"code-intelligence.com/cifuzz/pkg/desktop"
 	"code-intelligence.com/cifuzz/pkg/finding"
 	"code-intelligence.com/cifuzz/pkg/log"
+	"code-intelligence.com/cifuzz/pkg/parser/errorid"
 	"code-intelligence.com/cifuzz/pkg/report"
+	"code-intelligence.com/cifuzz/pkg/vcs"
 	"code-intelligence.com/cifuzz/util/fileutil"
 	"code-intelligence.com/cifuzz/util/stringutil"
 )
@@ -30,6 +32,15 @@ type ReportHandlerOptions struct {
 	ProjectDir    string
 	SeedCorpusDir string
 	PrintJSON     bool
+	// Dedup configures how findings are named and thereby
+	// deduplicated. If nil, the stack-trace strategy is used.
+	Dedup *finding.DedupOptions
+	// Findings which match one of the suppression rules are saved,
+	// but not reported
+	Suppressions *finding.Suppressions
+	// User-defined error ID matchers, which take precedence over the
+	// error ID determined by the output parser
+	ErrorIDMatchers errorid.UserMatchers
 }
 
 type ReportHandler struct {
@@ -49,8 +60,11 @@ type ReportHandler struct {
 
 	jsonOutput io.Writer
 
-	FuzzTest string
-	Findings []*finding.Finding
+	FuzzTest           string
+	Findings           []*finding.Finding
+	SuppressedFindings []*finding.Finding
+	// The findings of Findings which were not found before
+	NewFindings []*finding.Finding
 }
 
 func NewReportHandler(fuzzTest string, options *ReportHandlerOptions) (*ReportHandler, error) {
@@ -113,17 +127,17 @@ func (h *ReportHandler) Handle(r *report.Report) error {
 	}
 
 	if r.Finding != nil {
-		// save finding
-		h.Findings = append(h.Findings, r.Finding)
-
-		if len(h.Findings) == 1 {
-			h.PrintFindingInstruction()
-		}
-
 		err := h.handleFinding(r.Finding, !h.PrintJSON)
 		if err != nil {
 			return err
 		}
+
+		// Suppressed findings are not reported. Reports which contain
+		// a finding don't contain anything else, so we can skip the
+		// whole report.
+		if r.Finding.Suppression != nil {
+			return nil
+		}
 	}
 
 	// Print report as JSON if the --json flag was specified
@@ -158,36 +172,22 @@ func (h *ReportHandler) handleFinding(f *finding.Finding, print bool) error {
 
 	f.CreatedAt = time.Now()
 
-	// Generate a name for the finding. The name is chosen deterministically,
-	// based on:
-	// * Parts of the stack trace: The function name, source file name,
-	//   line and column of those stack frames which are located in user
-	//   or library code, i.e. everything above the call to
-	//   LLVMFuzzerTestOneInputNoReturn or LLVMFuzzerTestOneInput.
-	// * The crashing input.
-	//
-	// This automatically provides some very basic deduplication:
-	// Crashes which were triggered by the same line in the user code
-	// and with the same crashing input result in the same name, which
-	// means that a previous finding of the same name gets overwritten.
-	// So when executing the same fuzz test twice, we don't have
-	// duplicate findings, because the same crashing input is used from
-	// the seed corpus (unless the user deliberately removed it), which
-	// results in the same crash and a finding of the same name.
-	//
-	// By including the crashing input, we also generate a new finding
-	// in the scenario that, after a crash was found, the code was fixed
-	// and therefore the old crashing input does not trigger the crash
-	// anymore, but in a subsequent run the fuzzer finds a different
-	// crashing input which causes the crash again. We do want to
-	// produce a distinct new finding in that case.
-	var b bytes.Buffer
-	err = gob.NewEncoder(&b).Encode(f.StackTrace)
+	// This must be done before generating the name, which can depend
+	// on the error ID
+	if d := h.ErrorIDMatchers.ErrorDetails(f); d != nil {
+		f.MoreDetails = d
+	}
+	if f.ErrorID() == "" {
+		log.Warnf("unable to find matching error id for given finding: %s", f.Details)
+	}
+
+	// The dedup key used by the stack-hash strategy includes the fuzz
+	// test
+	f.FuzzTest = h.FuzzTest
+	f.Name, err = h.generateName(f)
 	if err != nil {
-		return errors.WithStack(err)
+		return err
 	}
-	nameSeed := append(b.Bytes(), f.InputData...)
-	f.Name = names.GetDeterministicName(nameSeed)
 
 	if f.InputFile != "" {
 		err = f.CopyInputFileAndUpdateFinding(h.ProjectDir, h.SeedCorpusDir)
@@ -196,7 +196,20 @@ func (h *ReportHandler) handleFinding(f *finding.Finding, print bool) error {
 		}
 	}
 
-	f.FuzzTest = h.FuzzTest
+	f.GitRevision = gitRevision(h.ProjectDir)
+	f.Owners = h.suggestOwners(f)
+
+	// If the finding was found before, keep the information which was
+	// added to it since then, like its triage status
+	existing, err := finding.LoadFinding(h.ProjectDir, f.Name, nil)
+	if err != nil && !finding.IsNotExistError(err) {
+		return err
+	}
+	if existing != nil {
+		f.CopyExistingInfo(existing)
+	}
+
+	f.Suppression = h.Suppressions.Match(f)
 
 	// Do not mutate f after this call.
 	err = f.Save(h.ProjectDir)
@@ -204,6 +217,23 @@ func (h *ReportHandler) handleFinding(f *finding.Finding, print bool) error {
 		return err
 	}
 
+	if f.Suppression != nil {
+		h.SuppressedFindings = append(h.SuppressedFindings, f)
+		if print {
+			log.Infof("Suppressed finding %s: %s", f.Name, f.Suppression.Reason)
+		}
+		return nil
+	}
+
+	h.Findings = append(h.Findings, f)
+	if existing == nil {
+		h.NewFindings = append(h.NewFindings, f)
+	}
+
+	if len(h.Findings) == 1 {
+		h.PrintFindingInstruction()
+	}
+
 	if !print {
 		return nil
 	}
@@ -215,6 +245,100 @@ func (h *ReportHandler) handleFinding(f *finding.Finding, print bool) error {
 	return nil
 }
 
+// generateName generates a name for the finding. By default, the name
+// is chosen deterministically, based on:
+//   - Parts of the stack trace: The function name, source file name,
+//     line and column of those stack frames which are located in user
+//     or library code, i.e. everything above the call to
+//     LLVMFuzzerTestOneInputNoReturn or LLVMFuzzerTestOneInput.
+//   - The crashing input.
+//
+// This automatically provides some very basic deduplication:
+// Crashes which were triggered by the same line in the user code
+// and with the same crashing input result in the same name, which
+// means that a previous finding of the same name gets overwritten.
+// So when executing the same fuzz test twice, we don't have
+// duplicate findings, because the same crashing input is used from
+// the seed corpus (unless the user deliberately removed it), which
+// results in the same crash and a finding of the same name.
+//
+// By including the crashing input, we also generate a new finding
+// in the scenario that, after a crash was found, the code was fixed
+// and therefore the old crashing input does not trigger the crash
+// anymore, but in a subsequent run the fuzzer finds a different
+// crashing input which causes the crash again. We do want to
+// produce a distinct new finding in that case.
+//
+// With the stack-hash dedup strategy, the name is instead based on the
+// fuzz test, the error ID and the normalized top in-project stack
+// frames (see finding.DedupKey), so that crashes with the same root
+// cause result in a single finding, even if they were triggered by
+// different inputs or reached via different call paths. If the dedup root-cause option is
+// set, the root cause of Java exceptions is used instead (see
+// finding.RootCauseDedupKey).
+func (h *ReportHandler) generateName(f *finding.Finding) (string, error) {
+	if h.Dedup != nil && h.Dedup.Strategy == finding.DedupStrategyStackHash {
+		return names.GetDeterministicName([]byte(h.Dedup.Key(f))), nil
+	}
+
+	var b bytes.Buffer
+	err := gob.NewEncoder(&b).Encode(f.StackTrace)
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+	nameSeed := append(b.Bytes(), f.InputData...)
+	return names.GetDeterministicName(nameSeed), nil
+}
+
+// suggestOwners returns the suggested owners of the finding, based on
+// the CODEOWNERS file of the project or git blame
+func (h *ReportHandler) suggestOwners(f *finding.Finding) []string {
+	codeOwners, err := finding.LoadCodeOwners(h.ProjectDir)
+	if err != nil {
+		log.Warnf("Failed to parse CODEOWNERS file: %v", err)
+	}
+	return f.SuggestOwners(h.ProjectDir, codeOwners)
+}
+
+// gitRevision returns the current Git revision of the project or nil if
+// the project is not a Git repository
+func gitRevision(projectDir string) *finding.GitRevision {
+	commit, err := vcs.GitCommit(projectDir)
+	if err != nil {
+		log.Debugf("Not recording Git revision of finding: %v", err)
+		return nil
+	}
+	branch, err := vcs.GitBranch(projectDir)
+	if err != nil {
+		log.Debugf("Failed to get Git branch: %v", err)
+	}
+	return &finding.GitRevision{Commit: commit, Branch: branch}
+}
+
+// Duration returns the time that passed since the report handler was
+// created, which is right before the fuzz test is started.
+func (h *ReportHandler) Duration() time.Duration {
+	return time.Since(h.startedAt)
+}
+
+// RunSummary returns a summary of the metrics and findings of the
+// fuzzing run handled by this report handler.
+func (h *ReportHandler) RunSummary(numCorpusEntries uint) *report.RunSummary {
+	var findingNames []string
+	for _, f := range h.Findings {
+		findingNames = append(findingNames, f.Name)
+	}
+	return &report.RunSummary{
+		FuzzTest:      h.FuzzTest,
+		StartedAt:     h.startedAt,
+		Duration:      h.Duration(),
+		FirstMetrics:  h.FirstMetrics,
+		LastMetrics:   h.LastMetrics,
+		CorpusEntries: numCorpusEntries,
+		Findings:      findingNames,
+	}
+}
+
 func (h *ReportHandler) PrintFindingInstruction() {
 	log.Note(`
 Use 'cifuzz finding <finding name>' for details on a finding.
@@ -248,6 +372,13 @@ regression tests. For more information on regression tests, see:
 `, strings.Join(crashingInputs, "\n    "))
 }
 
+func (h *ReportHandler) suppressedFindingsString() string {
+	if len(h.SuppressedFindings) == 0 {
+		return ""
+	}
+	return metrics.DescString(" (%s suppressed)", metrics.NumberString("%d", len(h.SuppressedFindings)))
+}
+
 func (h *ReportHandler) PrintFinalMetrics(numCorpusEntries uint) error {
 	// We don't want to print colors to stderr unless it's a TTY
 	if !term.IsTerminal(int(os.Stderr.Fd())) {
@@ -269,7 +400,7 @@ func (h *ReportHandler) PrintFinalMetrics(numCorpusEntries uint) error {
 		log.Print("\n")
 	}
 
-	duration := time.Since(h.startedAt)
+	duration := h.Duration()
 	totalCorpusEntries := numCorpusEntries
 	newCorpusEntries := totalCorpusEntries - h.numSeedsAtInit
 
@@ -278,19 +409,7 @@ func (h *ReportHandler) PrintFinalMetrics(numCorpusEntries uint) error {
 	if h.FirstMetrics == nil {
 		averageExecsStr = metrics.NumberString("n/a")
 	} else {
-		var averageExecs uint64
-		metricsDuration := h.LastMetrics.Timestamp.Sub(h.FirstMetrics.Timestamp)
-		if metricsDuration.Milliseconds() == 0 {
-			// The first and last metrics are either the same or were
-			// printed too fast one after the other to calculate a
-			// meaningful average, so we just use the exec/s from the
-			// current metrics as the average.
-			averageExecs = uint64(h.LastMetrics.ExecutionsPerSecond)
-		} else {
-			// We use milliseconds here to calculate a more accurate average
-			execs := h.LastMetrics.TotalExecutions - h.FirstMetrics.TotalExecutions
-			averageExecs = uint64(float64(execs) / (float64(metricsDuration.Milliseconds()) / 1000))
-		}
+		averageExecs := report.AverageExecutionsPerSecond(h.FirstMetrics, h.LastMetrics)
 		averageExecsStr = metrics.NumberString("%d", averageExecs)
 	}
 
@@ -301,7 +420,8 @@ func (h *ReportHandler) PrintFinalMetrics(numCorpusEntries uint) error {
 	lines := []string{
 		metrics.DescString("Execution time:\t") + metrics.NumberString(durationStr),
 		metrics.DescString("Average exec/s:\t") + averageExecsStr,
-		metrics.DescString("Findings:\t") + metrics.NumberString("%d", len(h.Findings)),
+		metrics.DescString("Findings:\t") + metrics.NumberString("%d", len(h.Findings)) +
+			h.suppressedFindingsString(),
 		metrics.DescString("Corpus entries:\t") + metrics.NumberString("%d", totalCorpusEntries) +
 			metrics.DescString(" (+%s)", metrics.NumberString("%d", newCorpusEntries)),
 	}
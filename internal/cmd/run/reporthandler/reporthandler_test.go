@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"io"
 	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -15,6 +16,8 @@ import (
 	"code-intelligence.com/cifuzz/internal/testutil"
 	"code-intelligence.com/cifuzz/pkg/finding"
 	"code-intelligence.com/cifuzz/pkg/log"
+	"code-intelligence.com/cifuzz/pkg/parser/errorid"
+	"code-intelligence.com/cifuzz/pkg/parser/libfuzzer/stacktrace"
 	"code-intelligence.com/cifuzz/pkg/report"
 )
 
@@ -148,6 +151,177 @@ func TestReportHandler_GenerateName(t *testing.T) {
 	assert.Equal(t, "adoring_orangutan", findingReport.Finding.Name)
 }
 
+func TestReportHandler_GenerateName_StackHash(t *testing.T) {
+	h, err := NewReportHandler("", &ReportHandlerOptions{
+		ProjectDir: testDir,
+		PrintJSON:  true,
+		Dedup:      &finding.DedupOptions{Strategy: finding.DedupStrategyStackHash, Frames: 1},
+	})
+	require.NoError(t, err)
+
+	// Findings with the same error ID and top frame get the same name,
+	// even if the inputs and the rest of the stack traces differ
+	var names []string
+	for i, caller := range []string{"foo", "bar"} {
+		f := &finding.Finding{
+			InputData:   []byte{byte(i)},
+			MoreDetails: &finding.ErrorDetails{ID: "heap_buffer_overflow"},
+			StackTrace: []*stacktrace.StackFrame{
+				{Function: "parse", SourceFile: "src/parser.cpp", Line: 10 + uint32(i)},
+				{Function: caller, SourceFile: "src/main.cpp", Line: 20},
+			},
+		}
+		err = h.Handle(&report.Report{Status: report.RunStatusRunning, Finding: f})
+		require.NoError(t, err)
+		names = append(names, f.Name)
+	}
+	assert.Equal(t, names[0], names[1])
+}
+
+func TestReportHandler_ErrorIDMatchers(t *testing.T) {
+	matchers := errorid.UserMatchers{{ID: "assertion_failure", Substrings: []string{"MY_ASSERT failed"}, Severity: 5.0}}
+	require.NoError(t, matchers.Validate())
+	h, err := NewReportHandler("", &ReportHandlerOptions{ProjectDir: testDir, PrintJSON: true, ErrorIDMatchers: matchers})
+	require.NoError(t, err)
+	h.jsonOutput = io.Discard
+
+	f := &finding.Finding{
+		Details:     "deadly signal",
+		Logs:        []string{"MY_ASSERT failed: size > 0"},
+		InputData:   []byte("assertion"),
+		MoreDetails: &finding.ErrorDetails{ID: "deadly_signal"},
+	}
+	err = h.Handle(&report.Report{Status: report.RunStatusRunning, Finding: f})
+	require.NoError(t, err)
+	assert.Equal(t, "assertion_failure", f.MoreDetails.ID)
+	assert.Equal(t, float32(5.0), f.MoreDetails.Severity.Score)
+
+	// Only findings which none of the built-in and user matchers
+	// match result in a warning
+	f = &finding.Finding{
+		Details:     "custom crash handler",
+		Logs:        []string{"MY_ASSERT failed: size > 1"},
+		InputData:   []byte("assertion 2"),
+		MoreDetails: &finding.ErrorDetails{},
+	}
+	_, _ = io.ReadAll(logOutput)
+	err = h.Handle(&report.Report{Status: report.RunStatusRunning, Finding: f})
+	require.NoError(t, err)
+	output, err := io.ReadAll(logOutput)
+	require.NoError(t, err)
+	assert.NotContains(t, string(output), "unable to find matching error id")
+
+	f = &finding.Finding{
+		Details:     "custom crash handler",
+		InputData:   []byte("unknown"),
+		MoreDetails: &finding.ErrorDetails{},
+	}
+	err = h.Handle(&report.Report{Status: report.RunStatusRunning, Finding: f})
+	require.NoError(t, err)
+	checkOutput(t, logOutput, "unable to find matching error id for given finding: custom crash handler")
+}
+
+func TestReportHandler_Owners(t *testing.T) {
+	projectDir := t.TempDir()
+	err := os.MkdirAll(filepath.Join(projectDir, "src"), 0o755)
+	require.NoError(t, err)
+	err = os.WriteFile(filepath.Join(projectDir, "src", "parser.cpp"), []byte("void parse() {}\n"), 0o644)
+	require.NoError(t, err)
+	err = os.WriteFile(filepath.Join(projectDir, "CODEOWNERS"), []byte("/src/ @org/parser-team\n"), 0o644)
+	require.NoError(t, err)
+
+	h, err := NewReportHandler("", &ReportHandlerOptions{ProjectDir: projectDir, PrintJSON: true})
+	require.NoError(t, err)
+	h.jsonOutput = io.Discard
+
+	f := &finding.Finding{
+		InputData:  []byte("owned"),
+		StackTrace: []*stacktrace.StackFrame{{Function: "parse", SourceFile: "src/parser.cpp", Line: 1}},
+	}
+	err = h.Handle(&report.Report{Status: report.RunStatusRunning, Finding: f})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"@org/parser-team"}, f.Owners)
+
+	// The owners are stored in the finding
+	saved, err := finding.LoadFinding(projectDir, f.Name, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"@org/parser-team"}, saved.Owners)
+}
+
+func TestReportHandler_FindingFoundAgain(t *testing.T) {
+	projectDir := t.TempDir()
+	h, err := NewReportHandler("", &ReportHandlerOptions{ProjectDir: projectDir, PrintJSON: true})
+	require.NoError(t, err)
+	h.jsonOutput = io.Discard
+
+	newFinding := func() *finding.Finding {
+		return &finding.Finding{
+			InputData:  []byte("again"),
+			StackTrace: []*stacktrace.StackFrame{{Function: "parse", SourceFile: "src/parser.cpp", Line: 1}},
+		}
+	}
+	f := newFinding()
+	err = h.Handle(&report.Report{Status: report.RunStatusRunning, Finding: f})
+	require.NoError(t, err)
+
+	// Add the information which is added to a finding after it was
+	// found, e.g. by `cifuzz finding dedupe`, `bisect` and `show --decode`
+	saved, err := finding.LoadFinding(projectDir, f.Name, nil)
+	require.NoError(t, err)
+	saved.SetStatus(finding.StatusConfirmed)
+	saved.Duplicates = []string{"other_finding"}
+	saved.GitRevision = &finding.GitRevision{Commit: "abc", FirstBadCommit: "def"}
+	saved.SetDataProviderCalls([]string{"ConsumeBool() = true"})
+	saved.Reproducibility = finding.NewReproducibility([]*finding.ReproAttempt{{Reproduced: true}})
+	err = saved.Save(projectDir)
+	require.NoError(t, err)
+
+	f = newFinding()
+	err = h.Handle(&report.Report{Status: report.RunStatusRunning, Finding: f})
+	require.NoError(t, err)
+	require.Equal(t, saved.Name, f.Name)
+	// Only the first report of the finding is a new finding
+	assert.Len(t, h.Findings, 2)
+	assert.Equal(t, []*finding.Finding{h.Findings[0]}, h.NewFindings)
+
+	found, err := finding.LoadFinding(projectDir, f.Name, nil)
+	require.NoError(t, err)
+	assert.Equal(t, finding.StatusConfirmed, found.GetStatus())
+	assert.Equal(t, []string{"other_finding"}, found.Duplicates)
+	require.NotNil(t, found.GitRevision)
+	assert.Equal(t, "def", found.GitRevision.FirstBadCommit)
+	assert.Equal(t, []string{"ConsumeBool() = true"}, found.DataProviderCalls)
+	assert.Equal(t, "ConsumeBool() = true", found.HumanReadableInput)
+	require.NotNil(t, found.Reproducibility)
+	assert.Equal(t, 1.0, found.Reproducibility.Rate)
+}
+
+func TestReportHandler_SuppressedFinding(t *testing.T) {
+	suppressions := &finding.Suppressions{Rules: []*finding.SuppressionRule{{
+		ErrorID: "heap_buffer_overflow",
+		Reason:  "Known bug",
+	}}}
+	h, err := NewReportHandler("", &ReportHandlerOptions{ProjectDir: testDir, PrintJSON: true, Suppressions: suppressions})
+	require.NoError(t, err)
+	jsonOutput := bytes.NewBuffer([]byte{})
+	h.jsonOutput = jsonOutput
+
+	f := &finding.Finding{
+		InputData:   []byte("suppressed"),
+		MoreDetails: &finding.ErrorDetails{ID: "heap_buffer_overflow"},
+	}
+	err = h.Handle(&report.Report{Status: report.RunStatusRunning, Finding: f})
+	require.NoError(t, err)
+
+	// The finding is saved, but not reported
+	exists, err := f.Exists(testDir)
+	require.NoError(t, err)
+	require.True(t, exists)
+	require.Empty(t, h.Findings)
+	require.Equal(t, []*finding.Finding{f}, h.SuppressedFindings)
+	require.Empty(t, jsonOutput.String())
+}
+
 func checkOutput(t *testing.T, r io.Reader, s ...string) {
 	output, err := io.ReadAll(r)
 	require.NoError(t, err)
@@ -21,11 +21,7 @@ import (
 
 	"code-intelligence.com/cifuzz/internal/api"
 	"code-intelligence.com/cifuzz/internal/build"
-	"code-intelligence.com/cifuzz/internal/build/bazel"
-	"code-intelligence.com/cifuzz/internal/build/cmake"
-	"code-intelligence.com/cifuzz/internal/build/gradle"
-	"code-intelligence.com/cifuzz/internal/build/maven"
-	"code-intelligence.com/cifuzz/internal/build/other"
+	"code-intelligence.com/cifuzz/internal/build/fuzztest"
 	"code-intelligence.com/cifuzz/internal/cmd/run/reporthandler"
 	"code-intelligence.com/cifuzz/internal/cmdutils"
 	"code-intelligence.com/cifuzz/internal/cmdutils/auth"
@@ -34,7 +30,9 @@ import (
 	"code-intelligence.com/cifuzz/internal/cmdutils/resolve"
 	"code-intelligence.com/cifuzz/internal/completion"
 	"code-intelligence.com/cifuzz/internal/config"
+	"code-intelligence.com/cifuzz/internal/junit"
 	"code-intelligence.com/cifuzz/internal/ldd"
+	"code-intelligence.com/cifuzz/internal/replayer"
 	"code-intelligence.com/cifuzz/internal/tokenstorage"
 	"code-intelligence.com/cifuzz/pkg/cicheck"
 	"code-intelligence.com/cifuzz/pkg/dependencies"
@@ -42,6 +40,7 @@ import (
 	"code-intelligence.com/cifuzz/pkg/finding"
 	"code-intelligence.com/cifuzz/pkg/log"
 	"code-intelligence.com/cifuzz/pkg/messaging"
+	"code-intelligence.com/cifuzz/pkg/parser/errorid"
 	"code-intelligence.com/cifuzz/pkg/report"
 	"code-intelligence.com/cifuzz/pkg/runner/jazzer"
 	"code-intelligence.com/cifuzz/pkg/runner/libfuzzer"
@@ -50,20 +49,25 @@ import (
 )
 
 type runOptions struct {
-	BuildSystem           string        `mapstructure:"build-system"`
-	BuildCommand          string        `mapstructure:"build-command"`
-	CleanCommand          string        `mapstructure:"clean-command"`
-	NumBuildJobs          uint          `mapstructure:"build-jobs"`
-	Dictionary            string        `mapstructure:"dict"`
-	EngineArgs            []string      `mapstructure:"engine-args"`
-	SeedCorpusDirs        []string      `mapstructure:"seed-corpus-dirs"`
-	Timeout               time.Duration `mapstructure:"timeout"`
-	Interactive           bool          `mapstructure:"interactive"`
-	Server                string        `mapstructure:"server"`
-	Project               string        `mapstructure:"project"`
-	UseSandbox            bool          `mapstructure:"use-sandbox"`
-	PrintJSON             bool          `mapstructure:"print-json"`
-	BuildOnly             bool          `mapstructure:"build-only"`
+	BuildSystem           string                `mapstructure:"build-system"`
+	BuildCommand          string                `mapstructure:"build-command"`
+	CleanCommand          string                `mapstructure:"clean-command"`
+	NumBuildJobs          uint                  `mapstructure:"build-jobs"`
+	Dictionary            string                `mapstructure:"dict"`
+	EngineArgs            []string              `mapstructure:"engine-args"`
+	SeedCorpusDirs        []string              `mapstructure:"seed-corpus-dirs"`
+	Timeout               time.Duration         `mapstructure:"timeout"`
+	Interactive           bool                  `mapstructure:"interactive"`
+	Server                string                `mapstructure:"server"`
+	Project               string                `mapstructure:"project"`
+	UseSandbox            bool                  `mapstructure:"use-sandbox"`
+	PrintJSON             bool                  `mapstructure:"print-json"`
+	BuildOnly             bool                  `mapstructure:"build-only"`
+	JUnitReport           string                `mapstructure:"junit-report"`
+	Dedup                 finding.DedupOptions  `mapstructure:"dedup"`
+	ErrorIDMatchers       errorid.UserMatchers  `mapstructure:"error-ids"`
+	Redact                finding.RedactOptions `mapstructure:"redact"`
+	ReproAttempts         uint                  `mapstructure:"repro-attempts"`
 	ResolveSourceFilePath bool
 
 	ProjectDir   string
@@ -119,6 +123,24 @@ func (opts *runOptions) validate() error {
 		return cmdutils.WrapIncorrectUsageError(errors.New(msg))
 	}
 
+	err = opts.Dedup.Validate()
+	if err != nil {
+		log.Error(err)
+		return cmdutils.WrapSilentError(err)
+	}
+
+	err = opts.ErrorIDMatchers.Validate()
+	if err != nil {
+		log.Error(err)
+		return cmdutils.WrapSilentError(err)
+	}
+
+	err = opts.Redact.Validate()
+	if err != nil {
+		log.Error(err)
+		return cmdutils.WrapSilentError(err)
+	}
+
 	if opts.Timeout != 0 && opts.Timeout < time.Second {
 		msg := fmt.Sprintf("invalid argument %q for \"--timeout\" flag: timeout can't be less than a second", opts.Timeout)
 		return cmdutils.WrapIncorrectUsageError(errors.New(msg))
@@ -309,9 +331,11 @@ depends on the build system configured for the project.
 		cmdutils.AddDictFlag,
 		cmdutils.AddEngineArgFlag,
 		cmdutils.AddInteractiveFlag,
+		cmdutils.AddJUnitReportFlag,
 		cmdutils.AddPrintJSONFlag,
 		cmdutils.AddProjectFlag,
 		cmdutils.AddProjectDirFlag,
+		cmdutils.AddReproAttemptsFlag,
 		cmdutils.AddSeedCorpusFlag,
 		cmdutils.AddServerFlag,
 		cmdutils.AddTimeoutFlag,
@@ -369,15 +393,24 @@ func (c *runCmd) run() error {
 		return nil
 	}
 
+	suppressions, err := finding.LoadSuppressions(c.opts.ProjectDir)
+	if err != nil {
+		log.Error(err)
+		return cmdutils.WrapSilentError(err)
+	}
+
 	// Initialize the report handler. Only do this right before we start
 	// the fuzz test, because this is storing a timestamp which is used
 	// to figure out how long the fuzzing run is running.
 	c.reportHandler, err = reporthandler.NewReportHandler(
 		c.opts.fuzzTest,
 		&reporthandler.ReportHandlerOptions{
-			ProjectDir:    c.opts.ProjectDir,
-			SeedCorpusDir: buildResult.SeedCorpus,
-			PrintJSON:     c.opts.PrintJSON,
+			ProjectDir:      c.opts.ProjectDir,
+			SeedCorpusDir:   buildResult.SeedCorpus,
+			PrintJSON:       c.opts.PrintJSON,
+			Dedup:           &c.opts.Dedup,
+			Suppressions:    suppressions,
+			ErrorIDMatchers: c.opts.ErrorIDMatchers,
 		})
 	if err != nil {
 		return err
@@ -393,6 +426,13 @@ func (c *runCmd) run() error {
 		return err
 	}
 
+	if c.opts.ReproAttempts > 0 {
+		err = c.checkReproducibility(buildResult)
+		if err != nil {
+			return err
+		}
+	}
+
 	c.reportHandler.PrintCrashingInputNote()
 
 	err = c.printFinalMetrics(buildResult.GeneratedCorpus, buildResult.SeedCorpus)
@@ -400,6 +440,13 @@ func (c *runCmd) run() error {
 		return err
 	}
 
+	if c.opts.JUnitReport != "" {
+		err = c.writeJUnitReport()
+		if err != nil {
+			return err
+		}
+	}
+
 	// We need this check, otherwise we might hang forever in CI
 	if c.opts.Project == "" && !c.opts.Interactive {
 		log.Info("Skipping upload of findings because no project was specified and running in non-interactive mode.")
@@ -436,163 +483,25 @@ func (c *runCmd) buildFuzzTest() (*build.Result, error) {
 		}(&err)
 	}
 
-	// TODO: Do not hardcode these values.
-	sanitizers := []string{"address", "undefined"}
-
-	switch c.opts.BuildSystem {
-	case config.BuildSystemBazel:
-		// The cc_fuzz_test rule defines multiple bazel targets: If the
-		// name is "foo", it defines the targets "foo", "foo_bin", and
-		// others. We need to run the "foo_bin" target but want to
-		// allow users to specify either "foo" or "foo_bin", so we check
-		// if the fuzz test name appended with "_bin" is a valid target
-		// and use that in that case
-		cmd := exec.Command("bazel", "query", c.opts.fuzzTest+"_bin")
-		err = cmd.Run()
-		if err == nil {
-			c.opts.fuzzTest += "_bin"
-		}
-
-		var builder *bazel.Builder
-		builder, err = bazel.NewBuilder(&bazel.BuilderOptions{
-			ProjectDir: c.opts.ProjectDir,
-			Args:       c.opts.argsToPass,
-			NumJobs:    c.opts.NumBuildJobs,
-			Stdout:     c.opts.buildStdout,
-			Stderr:     c.opts.buildStderr,
-			TempDir:    c.tempDir,
-			Verbose:    viper.GetBool("verbose"),
-		})
-		if err != nil {
-			return nil, err
-		}
-
-		var buildResults []*build.Result
-		buildResults, err = builder.BuildForRun([]string{c.opts.fuzzTest})
-		if err != nil {
-			return nil, err
-		}
-		return buildResults[0], nil
-
-	case config.BuildSystemCMake:
-		var builder *cmake.Builder
-		builder, err = cmake.NewBuilder(&cmake.BuilderOptions{
-			ProjectDir: c.opts.ProjectDir,
-			Args:       c.opts.argsToPass,
-			Sanitizers: sanitizers,
-			Parallel: cmake.ParallelOptions{
-				Enabled: viper.IsSet("build-jobs"),
-				NumJobs: c.opts.NumBuildJobs,
-			},
-			Stdout:    c.opts.buildStdout,
-			Stderr:    c.opts.buildStderr,
-			BuildOnly: c.opts.BuildOnly,
-		})
-		if err != nil {
-			return nil, err
-		}
-		err = builder.Configure()
-		if err != nil {
-			return nil, err
-		}
-
-		var buildResults []*build.Result
-		buildResults, err = builder.Build([]string{c.opts.fuzzTest})
-		if err != nil {
-			return nil, err
-		}
-
-		if c.opts.BuildOnly {
-			return nil, nil
-		}
-		return buildResults[0], nil
-
-	case config.BuildSystemMaven:
-		if len(c.opts.argsToPass) > 0 {
-			log.Warnf("Passing additional arguments is not supported for Maven.\n"+
-				"These arguments are ignored: %s", strings.Join(c.opts.argsToPass, " "))
-		}
-
-		var builder *maven.Builder
-		builder, err = maven.NewBuilder(&maven.BuilderOptions{
-			ProjectDir: c.opts.ProjectDir,
-			Parallel: maven.ParallelOptions{
-				Enabled: viper.IsSet("build-jobs"),
-				NumJobs: c.opts.NumBuildJobs,
-			},
-			Stdout: c.opts.buildStdout,
-			Stderr: c.opts.buildStderr,
-		})
-		if err != nil {
-			return nil, err
-		}
-
-		var buildResult *build.Result
-		buildResult, err = builder.Build(c.opts.fuzzTest)
-		if err != nil {
-			return nil, err
-		}
-		return buildResult, err
-
-	case config.BuildSystemGradle:
-		if len(c.opts.argsToPass) > 0 {
-			log.Warnf("Passing additional arguments is not supported for Gradle.\n"+
-				"These arguments are ignored: %s", strings.Join(c.opts.argsToPass, " "))
-		}
-
-		var builder *gradle.Builder
-		builder, err = gradle.NewBuilder(&gradle.BuilderOptions{
-			ProjectDir: c.opts.ProjectDir,
-			Parallel: gradle.ParallelOptions{
-				Enabled: viper.IsSet("build-jobs"),
-				NumJobs: c.opts.NumBuildJobs,
-			},
-			Stdout: c.opts.buildStdout,
-			Stderr: c.opts.buildStderr,
-		})
-		if err != nil {
-			return nil, err
-		}
-
-		var buildResult *build.Result
-		buildResult, err = builder.Build(c.opts.fuzzTest)
-		if err != nil {
-			return nil, err
-		}
-		return buildResult, err
-	case config.BuildSystemOther:
-		if len(c.opts.argsToPass) > 0 {
-			log.Warnf("Passing additional arguments is not supported for build system type \"other\".\n"+
-				"These arguments are ignored: %s", strings.Join(c.opts.argsToPass, " "))
-		}
-
-		var builder *other.Builder
-		builder, err = other.NewBuilder(&other.BuilderOptions{
-			ProjectDir:   c.opts.ProjectDir,
-			BuildCommand: c.opts.BuildCommand,
-			CleanCommand: c.opts.CleanCommand,
-			Sanitizers:   sanitizers,
-			Stdout:       c.opts.buildStdout,
-			Stderr:       c.opts.buildStderr,
-		})
-		if err != nil {
-			return nil, err
-		}
-
-		err := builder.Clean()
-		if err != nil {
-			return nil, err
-		}
-
-		var buildResult *build.Result
-		buildResult, err = builder.Build(c.opts.fuzzTest)
-		if err != nil {
-			return nil, err
-		}
-		return buildResult, nil
-	}
-
-	return nil, errors.Errorf("Unsupported build system \"%s\"", c.opts.BuildSystem)
+	buildOpts := &fuzztest.BuildOptions{
+		BuildSystem:  c.opts.BuildSystem,
+		BuildCommand: c.opts.BuildCommand,
+		CleanCommand: c.opts.CleanCommand,
+		NumBuildJobs: c.opts.NumBuildJobs,
+		ProjectDir:   c.opts.ProjectDir,
+		FuzzTest:     c.opts.fuzzTest,
+		Args:         c.opts.argsToPass,
+		TempDir:      c.tempDir,
+		BuildOnly:    c.opts.BuildOnly,
+		Stdout:       c.opts.buildStdout,
+		Stderr:       c.opts.buildStderr,
+	}
+	var buildResult *build.Result
+	buildResult, err = fuzztest.Build(buildOpts)
+	// The builder might have changed the name of the fuzz test (for
+	// Bazel)
+	c.opts.fuzzTest = buildOpts.FuzzTest
+	return buildResult, err
 }
 
 func (c *runCmd) runFuzzTest(buildResult *build.Result) error {
@@ -683,13 +592,100 @@ func (c *runCmd) runFuzzTest(buildResult *build.Result) error {
 	return executeRunner(runner)
 }
 
+// checkReproducibility replays each new finding multiple times and
+// stores the reproduction rate in the finding, so that flaky findings
+// can be recognized. Findings which were found before keep the
+// reproducibility determined back then.
+func (c *runCmd) checkReproducibility(buildResult *build.Result) error {
+	for _, f := range c.reportHandler.NewFindings {
+		inputPath, err := f.InputPath(c.opts.ProjectDir)
+		if err != nil {
+			return err
+		}
+
+		log.Infof("Replaying finding %s %d times to check if it's reproducible", f.Name, c.opts.ReproAttempts)
+		var attempts []*finding.ReproAttempt
+		for i := uint(0); i < c.opts.ReproAttempts; i++ {
+			start := time.Now()
+			findings, err := replayer.Replay(context.Background(), &replayer.Options{
+				BuildSystem:  c.opts.BuildSystem,
+				BuildResult:  buildResult,
+				FuzzTest:     c.opts.fuzzTest,
+				TargetMethod: c.opts.targetMethod,
+				ProjectDir:   c.opts.ProjectDir,
+				InputFiles:   []string{inputPath},
+				EngineArgs:   c.opts.EngineArgs,
+				UseSandbox:   c.opts.UseSandbox,
+			})
+			duration := time.Since(start)
+			if err != nil {
+				log.Debugf("Replay error: %+v", err)
+				attempts = append(attempts, &finding.ReproAttempt{Duration: duration, Error: err.Error()})
+				continue
+			}
+			// Classify the findings like the findings of the run
+			for _, other := range findings {
+				if d := c.opts.ErrorIDMatchers.ErrorDetails(other); d != nil {
+					other.MoreDetails = d
+				}
+			}
+			attempts = append(attempts, f.NewReproAttempt(findings, duration))
+		}
+
+		// Attempts which failed to run the fuzz test, e.g. because of
+		// a sandbox error, don't tell whether the finding reproduces
+		r := finding.NewReproducibility(attempts)
+		if r.NumCompleted() == 0 {
+			log.Warnf("Failed to replay finding %s, its reproducibility is unknown: %s", f.Name, attempts[0].Error)
+			continue
+		}
+
+		// The fuzzer has stopped, so the finding can be modified again
+		f.Reproducibility = r
+		err = f.Save(c.opts.ProjectDir)
+		if err != nil {
+			return err
+		}
+
+		if f.IsUnreliable() {
+			log.Warnf("Finding %s is flaky, it reproduced in %s", f.Name, f.Reproducibility)
+		} else {
+			log.Infof("Finding %s reproduced in %s", f.Name, f.Reproducibility)
+		}
+	}
+	return nil
+}
+
 func (c *runCmd) printFinalMetrics(generatedCorpus, seedCorpus string) error {
 	numCorpusEntries, err := countCorpusEntries(append(c.opts.SeedCorpusDirs, generatedCorpus, seedCorpus))
 	if err != nil {
 		return err
 	}
 
-	return c.reportHandler.PrintFinalMetrics(numCorpusEntries)
+	err = c.reportHandler.PrintFinalMetrics(numCorpusEntries)
+	if err != nil {
+		return err
+	}
+
+	// Store the metrics of this run, so that they can be included in
+	// reports created via 'cifuzz report'
+	return c.reportHandler.RunSummary(numCorpusEntries).Save(c.opts.ProjectDir)
+}
+
+func (c *runCmd) writeJUnitReport() error {
+	results := []*junit.FuzzTestResult{{
+		FuzzTest:     c.opts.fuzzTest,
+		Duration:     c.reportHandler.Duration(),
+		FirstMetrics: c.reportHandler.FirstMetrics,
+		LastMetrics:  c.reportHandler.LastMetrics,
+		Findings:     c.reportHandler.Findings,
+	}}
+	err := junit.WriteReport(c.opts.JUnitReport, results)
+	if err != nil {
+		return err
+	}
+	log.Infof("Created JUnit report: %s", fileutil.PrettifyPath(c.opts.JUnitReport))
+	return nil
 }
 
 func (c *runCmd) checkDependencies() error {
@@ -878,9 +874,11 @@ Findings have *not* been uploaded. Please check the 'project' entry in your cifu
 		return err
 	}
 
-	// upload findings
+	// upload findings, with sensitive data like tokens and home
+	// directories redacted
 	for _, finding := range c.reportHandler.Findings {
-		err = c.apiClient.UploadFinding(project, fuzzTarget, campaignRunName, fuzzingRunName, finding, token)
+		redacted, _ := c.opts.Redact.Redact(finding)
+		err = c.apiClient.UploadFinding(project, fuzzTarget, campaignRunName, fuzzingRunName, redacted, token)
 		if err != nil {
 			return err
 		}
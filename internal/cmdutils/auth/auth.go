@@ -1,10 +1,13 @@
 package auth
 
 import (
+	"github.com/pkg/errors"
+
 	"code-intelligence.com/cifuzz/internal/api"
 	"code-intelligence.com/cifuzz/internal/cmdutils/login"
 	"code-intelligence.com/cifuzz/internal/tokenstorage"
 	"code-intelligence.com/cifuzz/pkg/dialog"
+	"code-intelligence.com/cifuzz/pkg/finding"
 	"code-intelligence.com/cifuzz/pkg/log"
 	"code-intelligence.com/cifuzz/pkg/messaging"
 )
@@ -53,3 +56,26 @@ func ShowServerConnectionDialog(server string, context messaging.MessagingContex
 
 	return wishToAuthenticate, nil
 }
+
+// GetErrorDetails tries to get error details from the API of the given
+// server. If the API is available and the user is logged in, it returns
+// the error details. If the API is not available or the user is not
+// logged in, it returns an empty list, so that the error details shipped
+// with cifuzz are used.
+func GetErrorDetails(server string, version string) (*[]finding.ErrorDetails, error) {
+	token := login.GetToken(server)
+	log.Debugf("Checking for error details on server %s", server)
+
+	apiClient := api.NewClient(server, version)
+	errorDetails, err := apiClient.GetErrorDetails(token)
+	if err != nil {
+		var connErr *api.ConnectionError
+		if !errors.As(err, &connErr) {
+			return nil, err
+		}
+		log.Warn("Using offline error details.")
+		log.Debugf("Connection error: %v (continuing gracefully)", connErr)
+		return &[]finding.ErrorDetails{}, nil
+	}
+	return &errorDetails, nil
+}
@@ -164,6 +164,15 @@ func AddInteractiveFlag(cmd *cobra.Command) func() {
 	}
 }
 
+func AddJUnitReportFlag(cmd *cobra.Command) func() {
+	cmd.Flags().String("junit-report", "",
+		"Write a JUnit XML report of the fuzzing results to the specified `path`.\n"+
+			"Each fuzz test is reported as a test case and each finding as a failure.")
+	return func() {
+		ViperMustBindPFlag("junit-report", cmd.Flags().Lookup("junit-report"))
+	}
+}
+
 func AddPresetFlag(cmd *cobra.Command) func() {
 	cmd.Flags().String("preset", "", "Preset for a given environment to execute coverage with necessary flags.\n"+
 		"We recommend not using this flag with '--format' or '--output' because the preset will set these accordingly.\n"+
@@ -209,6 +218,15 @@ e.g. "my-project-c170bc17".`)
 	}
 }
 
+func AddReproAttemptsFlag(cmd *cobra.Command) func() {
+	cmd.Flags().Uint("repro-attempts", 0,
+		"Number of times each new finding is replayed to determine its reproduction rate.\n"+
+			"By default, findings are not replayed.")
+	return func() {
+		ViperMustBindPFlag("repro-attempts", cmd.Flags().Lookup("repro-attempts"))
+	}
+}
+
 func AddSeedCorpusFlag(cmd *cobra.Command) func() {
 	// TODO(afl): Also link to https://aflplus.plus/docs/fuzzing_in_depth/#a-collecting-inputs
 	cmd.Flags().StringArrayP("seed-corpus", "s", nil,
@@ -162,3 +162,54 @@ func FuzzTestArgument(resolveSourceFile bool, args []string, buildSystem, projec
 
 	return args, nil
 }
+
+// SourceFile determines the source file of the given fuzz test, which
+// is returned relative to the project directory. For build systems for
+// which that's not supported, an empty string is returned.
+func SourceFile(fuzzTest, buildSystem, projectDir string) (string, error) {
+	switch buildSystem {
+	case config.BuildSystemCMake:
+		cmakeLists, err := findAllCMakeLists(projectDir)
+		if err != nil {
+			return "", err
+		}
+
+		for _, list := range cmakeLists {
+			bs, err := os.ReadFile(filepath.Join(projectDir, list))
+			if err != nil {
+				return "", errors.WithStack(err)
+			}
+
+			matches, _ := regexutil.FindAllNamedGroupsMatches(cmakeFuzzTestFileNamePattern, string(bs))
+			for _, match := range matches {
+				if match["fuzzTest"] == fuzzTest {
+					return filepath.Join(filepath.Dir(list), match["file"]), nil
+				}
+			}
+		}
+		return "", nil
+
+	case config.BuildSystemMaven, config.BuildSystemGradle:
+		// Named fuzz tests are methods of the fuzz test class
+		class := strings.Split(fuzzTest, "::")[0]
+		testDir := filepath.Join(projectDir, "src", "test")
+		matches, err := zglob.Glob(filepath.Join(testDir, "**", "*.{java,kt}"))
+		if err != nil {
+			return "", errors.WithStack(err)
+		}
+		for _, match := range matches {
+			identifier, err := cmdutils.ConstructJVMFuzzTestIdentifier(match, testDir)
+			if err != nil {
+				return "", err
+			}
+			if identifier == class {
+				path, err := filepath.Rel(projectDir, match)
+				return path, errors.WithStack(err)
+			}
+		}
+		return "", nil
+
+	default:
+		return "", nil
+	}
+}
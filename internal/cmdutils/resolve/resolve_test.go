@@ -83,6 +83,14 @@ func testResolveCMake(t *testing.T, pwd string) {
 	resolved, err = resolve(srcFile, config.BuildSystemCMake, pwd)
 	require.NoError(t, err)
 	require.Equal(t, fuzzTestName, resolved)
+
+	sourceFile, err := SourceFile(fuzzTestName, config.BuildSystemCMake, pwd)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join("src", "fuzz_test_1", "fuzz_test.cpp"), sourceFile)
+
+	sourceFile, err = SourceFile("no_such_fuzz_test", config.BuildSystemCMake, pwd)
+	require.NoError(t, err)
+	assert.Empty(t, sourceFile)
 }
 
 func testResolveMavenGradle(t *testing.T, pwd string) {
@@ -125,6 +133,14 @@ func testResolveMavenGradle(t *testing.T, pwd string) {
 	resolved, err = resolve(srcFile, config.BuildSystemMaven, pwd)
 	assert.NoError(t, err)
 	assert.Equal(t, fuzzTestName, resolved)
+
+	// The fuzz test class is defined in both a Java and a Kotlin file
+	sourceFile, err := SourceFile(fuzzTestName+"::myFuzzTest", config.BuildSystemMaven, pwd)
+	require.NoError(t, err)
+	assert.Contains(t, []string{
+		filepath.Join("src", "test", "java", "com", "example", "fuzz_test_1", "FuzzTestCase.java"),
+		filepath.Join("src", "test", "kotlin", "com", "example", "fuzz_test_1", "FuzzTestCase.kt"),
+	}, sourceFile)
 }
 
 func testResolveMavenGradleWindowsPaths(t *testing.T, pwd string) {
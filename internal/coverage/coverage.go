@@ -5,11 +5,17 @@ import "code-intelligence.com/cifuzz/internal/config"
 const FormatHTML = "html"
 const FormatLCOV = "lcov"
 const FormatJacocoXML = "jacocoxml"
+const FormatCobertura = "cobertura"
+const FormatSonarQube = "sonarqube"
+
+// ConvertedFormats are the output formats which cifuzz converts the
+// lcov or JaCoCo XML reports of the build system into
+var ConvertedFormats = []string{FormatCobertura, FormatSonarQube}
 
 var ValidOutputFormats = map[string][]string{
-	config.BuildSystemCMake:  {FormatHTML, FormatLCOV},
-	config.BuildSystemBazel:  {FormatHTML, FormatLCOV},
-	config.BuildSystemOther:  {FormatHTML, FormatLCOV},
-	config.BuildSystemMaven:  {FormatHTML, FormatJacocoXML},
-	config.BuildSystemGradle: {FormatHTML, FormatJacocoXML},
+	config.BuildSystemCMake:  {FormatHTML, FormatLCOV, FormatCobertura, FormatSonarQube},
+	config.BuildSystemBazel:  {FormatHTML, FormatLCOV, FormatCobertura, FormatSonarQube},
+	config.BuildSystemOther:  {FormatHTML, FormatLCOV, FormatCobertura, FormatSonarQube},
+	config.BuildSystemMaven:  {FormatHTML, FormatJacocoXML, FormatCobertura, FormatSonarQube},
+	config.BuildSystemGradle: {FormatHTML, FormatJacocoXML, FormatCobertura, FormatSonarQube},
 }
@@ -0,0 +1,182 @@
+package junit
+
+import (
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"code-intelligence.com/cifuzz/pkg/finding"
+	"code-intelligence.com/cifuzz/pkg/report"
+)
+
+// FuzzTestResult contains the results of running a single fuzz test
+// which are included in the JUnit report.
+type FuzzTestResult struct {
+	FuzzTest     string
+	Duration     time.Duration
+	FirstMetrics *report.FuzzingMetric
+	LastMetrics  *report.FuzzingMetric
+	Findings     []*finding.Finding
+}
+
+type testSuites struct {
+	XMLName    xml.Name     `xml:"testsuites"`
+	Name       string       `xml:"name,attr"`
+	Tests      int          `xml:"tests,attr"`
+	Failures   int          `xml:"failures,attr"`
+	Time       string       `xml:"time,attr"`
+	TestSuites []*testSuite `xml:"testsuite"`
+}
+
+type testSuite struct {
+	Name      string      `xml:"name,attr"`
+	Tests     int         `xml:"tests,attr"`
+	Failures  int         `xml:"failures,attr"`
+	Errors    int         `xml:"errors,attr"`
+	Skipped   int         `xml:"skipped,attr"`
+	Time      string      `xml:"time,attr"`
+	Timestamp string      `xml:"timestamp,attr,omitempty"`
+	TestCases []*testCase `xml:"testcase"`
+}
+
+type testCase struct {
+	Name       string      `xml:"name,attr"`
+	ClassName  string      `xml:"classname,attr"`
+	Time       string      `xml:"time,attr"`
+	Properties *properties `xml:"properties,omitempty"`
+	Failures   []*failure  `xml:"failure"`
+}
+
+type properties struct {
+	Properties []*property `xml:"property"`
+}
+
+type property struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value,attr"`
+}
+
+type failure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// WriteReport writes a JUnit XML report containing one test case per
+// fuzz test and one failure per finding to the specified path.
+func WriteReport(path string, results []*FuzzTestResult) error {
+	bytes, err := Marshal(results)
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(filepath.Dir(path), 0o755)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	err = os.WriteFile(path, bytes, 0o644)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
+
+// Marshal returns the JUnit XML representation of the results.
+func Marshal(results []*FuzzTestResult) ([]byte, error) {
+	suite := &testSuite{Name: "cifuzz"}
+	var totalDuration time.Duration
+	for _, r := range results {
+		suite.TestCases = append(suite.TestCases, newTestCase(r))
+		suite.Tests++
+		suite.Failures += len(r.Findings)
+		totalDuration += r.Duration
+		if r.FirstMetrics != nil && suite.Timestamp == "" {
+			suite.Timestamp = r.FirstMetrics.Timestamp.Format(time.RFC3339)
+		}
+	}
+	suite.Time = seconds(totalDuration)
+
+	suites := &testSuites{
+		Name:       "cifuzz",
+		Tests:      suite.Tests,
+		Failures:   suite.Failures,
+		Time:       suite.Time,
+		TestSuites: []*testSuite{suite},
+	}
+
+	bytes, err := xml.MarshalIndent(suites, "", "  ")
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return append([]byte(xml.Header), append(bytes, '\n')...), nil
+}
+
+func newTestCase(r *FuzzTestResult) *testCase {
+	tc := &testCase{
+		Name:      r.FuzzTest,
+		ClassName: r.FuzzTest,
+		Time:      seconds(r.Duration),
+	}
+
+	props := []*property{
+		{Name: "duration", Value: r.Duration.Round(time.Second).String()},
+		{Name: "findings", Value: fmt.Sprint(len(r.Findings))},
+	}
+	if r.LastMetrics != nil {
+		props = append(props,
+			&property{Name: "total_executions", Value: fmt.Sprint(r.LastMetrics.TotalExecutions)},
+			&property{Name: "executions_per_second", Value: fmt.Sprint(report.AverageExecutionsPerSecond(r.FirstMetrics, r.LastMetrics))},
+			&property{Name: "features", Value: fmt.Sprint(r.LastMetrics.Features)},
+			&property{Name: "edges", Value: fmt.Sprint(r.LastMetrics.Edges)},
+			&property{Name: "corpus_size", Value: fmt.Sprint(r.LastMetrics.CorpusSize)},
+		)
+	}
+	tc.Properties = &properties{Properties: props}
+
+	for _, f := range r.Findings {
+		tc.Failures = append(tc.Failures, newFailure(f))
+	}
+	return tc
+}
+
+func newFailure(f *finding.Finding) *failure {
+	errorType := string(f.Type)
+	if f.MoreDetails != nil && f.MoreDetails.ID != "" {
+		errorType = f.MoreDetails.ID
+	}
+
+	var text strings.Builder
+	text.WriteString(fmt.Sprintf("Finding: %s\n", f.Name))
+	if f.Details != "" {
+		text.WriteString(fmt.Sprintf("Details: %s\n", f.Details))
+	}
+	if f.InputFile != "" {
+		text.WriteString(fmt.Sprintf("Crashing input: %s\n", f.InputFile))
+	}
+	if len(f.StackTrace) > 0 {
+		text.WriteString("\nStack trace:\n")
+		for _, frame := range f.StackTrace {
+			text.WriteString(fmt.Sprintf("    #%d %s %s\n", frame.FrameNumber, frame.Function, frame.Location()))
+		}
+	}
+	if len(f.Logs) > 0 {
+		text.WriteString("\nLogs:\n")
+		text.WriteString(strings.Join(f.Logs, "\n"))
+		text.WriteString("\n")
+	}
+
+	return &failure{
+		Message: f.ShortDescriptionWithName(),
+		Type:    errorType,
+		Text:    text.String(),
+	}
+}
+
+func seconds(d time.Duration) string {
+	return fmt.Sprintf("%.3f", d.Seconds())
+}
@@ -0,0 +1,91 @@
+package junit
+
+import (
+	"encoding/xml"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"code-intelligence.com/cifuzz/pkg/finding"
+	"code-intelligence.com/cifuzz/pkg/parser/libfuzzer/stacktrace"
+	"code-intelligence.com/cifuzz/pkg/report"
+)
+
+func TestWriteReport(t *testing.T) {
+	start := time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC)
+	results := []*FuzzTestResult{
+		{
+			FuzzTest: "my_fuzz_test",
+			Duration: 90 * time.Second,
+			FirstMetrics: &report.FuzzingMetric{
+				Timestamp:       start,
+				TotalExecutions: 0,
+			},
+			LastMetrics: &report.FuzzingMetric{
+				Timestamp:       start.Add(10 * time.Second),
+				TotalExecutions: 1000,
+				Features:        42,
+				Edges:           21,
+				CorpusSize:      7,
+			},
+			Findings: []*finding.Finding{{
+				Name:    "funky_fox",
+				Type:    finding.ErrorTypeCrash,
+				Details: "heap-buffer-overflow on address 0x1234",
+				Logs:    []string{"==1==ERROR: AddressSanitizer: heap-buffer-overflow"},
+				StackTrace: []*stacktrace.StackFrame{{
+					SourceFile: "src/explore_me.cpp",
+					Line:       18,
+					Column:     11,
+					Function:   "exploreMe",
+				}},
+				MoreDetails: &finding.ErrorDetails{ID: "heap_buffer_overflow"},
+			}},
+		},
+		{
+			FuzzTest: "another_fuzz_test",
+			Duration: 30 * time.Second,
+		},
+	}
+
+	path := filepath.Join(t.TempDir(), "reports", "junit.xml")
+	err := WriteReport(path, results)
+	require.NoError(t, err)
+
+	bytes, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	suites := &testSuites{}
+	err = xml.Unmarshal(bytes, suites)
+	require.NoError(t, err)
+
+	require.Equal(t, 2, suites.Tests)
+	require.Equal(t, 1, suites.Failures)
+	require.Equal(t, "120.000", suites.Time)
+	require.Len(t, suites.TestSuites, 1)
+
+	testCases := suites.TestSuites[0].TestCases
+	require.Len(t, testCases, 2)
+	require.Equal(t, "my_fuzz_test", testCases[0].Name)
+	require.Equal(t, "90.000", testCases[0].Time)
+	require.Len(t, testCases[0].Failures, 1)
+	require.Equal(t, "heap_buffer_overflow", testCases[0].Failures[0].Type)
+	require.Contains(t, testCases[0].Failures[0].Message, "funky_fox")
+	require.Contains(t, testCases[0].Failures[0].Text, "exploreMe src/explore_me.cpp:18:11")
+	require.Contains(t, testCases[0].Failures[0].Text, "AddressSanitizer: heap-buffer-overflow")
+
+	props := map[string]string{}
+	for _, p := range testCases[0].Properties.Properties {
+		props[p.Name] = p.Value
+	}
+	require.Equal(t, "1000", props["total_executions"])
+	require.Equal(t, "100", props["executions_per_second"])
+	require.Equal(t, "42", props["features"])
+	require.Equal(t, "1m30s", props["duration"])
+
+	require.Equal(t, "another_fuzz_test", testCases[1].Name)
+	require.Empty(t, testCases[1].Failures)
+}
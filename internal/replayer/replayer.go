@@ -0,0 +1,100 @@
+package replayer
+
+import (
+	"context"
+	"runtime"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/viper"
+
+	"code-intelligence.com/cifuzz/internal/build"
+	"code-intelligence.com/cifuzz/internal/config"
+	"code-intelligence.com/cifuzz/internal/ldd"
+	"code-intelligence.com/cifuzz/pkg/finding"
+	"code-intelligence.com/cifuzz/pkg/report"
+	"code-intelligence.com/cifuzz/pkg/runner/jazzer"
+	"code-intelligence.com/cifuzz/pkg/runner/libfuzzer"
+)
+
+// Options configures how inputs are replayed
+type Options struct {
+	BuildSystem string
+	BuildResult *build.Result
+	// The name of the fuzz test. For Maven and Gradle, this is the
+	// class containing the fuzz test.
+	FuzzTest     string
+	TargetMethod string
+	ProjectDir   string
+	// The inputs with which the fuzz test is executed
+	InputFiles []string
+	// Additional arguments passed to libFuzzer or Jazzer
+	EngineArgs []string
+	EnvVars    []string
+	Timeout    time.Duration
+	UseSandbox bool
+}
+
+type findingsCollector struct {
+	findings []*finding.Finding
+}
+
+func (c *findingsCollector) Handle(r *report.Report) error {
+	if r.Finding != nil {
+		c.findings = append(c.findings, r.Finding)
+	}
+	return nil
+}
+
+// Replay executes the fuzz test once with each of the inputs and
+// returns the findings which were reported. Note that libFuzzer stops
+// after the first input which triggers a finding.
+func Replay(ctx context.Context, opts *Options) ([]*finding.Finding, error) {
+	var err error
+
+	if len(opts.InputFiles) == 0 {
+		return nil, errors.New("No inputs to replay")
+	}
+
+	var libraryPaths []string
+	if runtime.GOOS != "windows" && opts.BuildResult.Executable != "" {
+		libraryPaths, err = ldd.LibraryPaths(opts.BuildResult.Executable)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+	}
+
+	collector := &findingsCollector{}
+	runnerOpts := &libfuzzer.RunnerOptions{
+		EngineArgs:       opts.EngineArgs,
+		EnvVars:          append([]string{"NO_CIFUZZ=1"}, opts.EnvVars...),
+		FuzzTarget:       opts.BuildResult.Executable,
+		InputFiles:       opts.InputFiles,
+		LibraryDirs:      libraryPaths,
+		ProjectDir:       opts.ProjectDir,
+		ReadOnlyBindings: []string{opts.BuildResult.BuildDir},
+		ReportHandler:    collector,
+		Timeout:          opts.Timeout,
+		UseMinijail:      opts.UseSandbox,
+		Verbose:          viper.GetBool("verbose"),
+	}
+
+	switch opts.BuildSystem {
+	case config.BuildSystemCMake, config.BuildSystemBazel, config.BuildSystemOther:
+		err = libfuzzer.NewRunner(runnerOpts).Run(ctx)
+	case config.BuildSystemMaven, config.BuildSystemGradle:
+		err = jazzer.NewRunner(&jazzer.RunnerOptions{
+			TargetClass:      opts.FuzzTest,
+			TargetMethod:     opts.TargetMethod,
+			ClassPaths:       opts.BuildResult.RuntimeDeps,
+			LibfuzzerOptions: runnerOpts,
+		}).Run(ctx)
+	default:
+		return nil, errors.Errorf("Replaying inputs is not supported for build system %q", opts.BuildSystem)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return collector.findings, nil
+}
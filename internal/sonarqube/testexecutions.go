@@ -0,0 +1,141 @@
+package sonarqube
+
+import (
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"code-intelligence.com/cifuzz/pkg/finding"
+)
+
+// FuzzTestResult contains the fuzz test and findings which are included
+// in the test execution report.
+type FuzzTestResult struct {
+	FuzzTest string
+	// The source file of the fuzz test, relative to the project
+	// directory
+	SourceFile string
+	Duration   time.Duration
+	Findings   []*finding.Finding
+	// Suppressed findings are reported as skipped, like closed ones
+	Suppressions *finding.Suppressions
+}
+
+type testExecutions struct {
+	XMLName xml.Name    `xml:"testExecutions"`
+	Version int         `xml:"version,attr"`
+	Files   []*testFile `xml:"file"`
+}
+
+type testFile struct {
+	Path      string      `xml:"path,attr"`
+	TestCases []*testCase `xml:"testCase"`
+}
+
+type testCase struct {
+	Name     string   `xml:"name,attr"`
+	Duration int64    `xml:"duration,attr"`
+	Failure  *message `xml:"failure,omitempty"`
+	Skipped  *message `xml:"skipped,omitempty"`
+}
+
+type message struct {
+	Message string `xml:"message,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// WriteTestExecutionReport writes a report in the SonarQube generic test
+// execution format to the specified path, see
+// https://docs.sonarsource.com/sonarqube/latest/analyzing-source-code/test-coverage/generic-test-data/.
+// Each fuzz test is reported as a passed test case and each finding as
+// a failed one, unless the finding is closed or suppressed.
+func WriteTestExecutionReport(path string, results []*FuzzTestResult) error {
+	bytes, err := Marshal(results)
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(filepath.Dir(path), 0o755)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	err = os.WriteFile(path, bytes, 0o644)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
+
+// Marshal returns the SonarQube generic test execution representation
+// of the results. Results of fuzz tests with the same source file are
+// reported in the same file element.
+func Marshal(results []*FuzzTestResult) ([]byte, error) {
+	report := &testExecutions{Version: 1}
+	files := make(map[string]*testFile)
+	for _, r := range results {
+		path := filepath.ToSlash(r.SourceFile)
+		file, ok := files[path]
+		if !ok {
+			file = &testFile{Path: path}
+			files[path] = file
+			report.Files = append(report.Files, file)
+		}
+
+		file.TestCases = append(file.TestCases, &testCase{
+			Name:     r.FuzzTest,
+			Duration: r.Duration.Milliseconds(),
+		})
+		for _, f := range r.Findings {
+			file.TestCases = append(file.TestCases, newTestCase(r, f))
+		}
+	}
+
+	bytes, err := xml.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return append([]byte(xml.Header), append(bytes, '\n')...), nil
+}
+
+func newTestCase(r *FuzzTestResult, f *finding.Finding) *testCase {
+	tc := &testCase{Name: fmt.Sprintf("%s: %s", r.FuzzTest, f.Name)}
+	// Use the average duration of the reproduction attempts, if the
+	// finding was replayed
+	if f.Reproducibility != nil && len(f.Reproducibility.Attempts) > 0 {
+		var total time.Duration
+		for _, attempt := range f.Reproducibility.Attempts {
+			total += attempt.Duration
+		}
+		tc.Duration = (total / time.Duration(len(f.Reproducibility.Attempts))).Milliseconds()
+	}
+
+	if status := f.GetStatus(); status.IsClosed() {
+		tc.Skipped = &message{Message: fmt.Sprintf("Finding is %s", status)}
+		return tc
+	}
+	if rule := r.Suppressions.Match(f); rule != nil {
+		tc.Skipped = &message{Message: fmt.Sprintf("Finding is suppressed: %s", rule.Reason)}
+		return tc
+	}
+
+	var text strings.Builder
+	if f.Details != "" {
+		text.WriteString(fmt.Sprintf("Details: %s\n", f.Details))
+	}
+	if len(f.StackTrace) > 0 {
+		text.WriteString("\nStack trace:\n")
+		for _, frame := range f.StackTrace {
+			text.WriteString(fmt.Sprintf("    #%d %s %s\n", frame.FrameNumber, frame.Function, frame.Location()))
+		}
+	}
+	tc.Failure = &message{
+		Message: f.ShortDescriptionWithName(),
+		Text:    text.String(),
+	}
+	return tc
+}
@@ -0,0 +1,71 @@
+package sonarqube
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"code-intelligence.com/cifuzz/pkg/finding"
+	"code-intelligence.com/cifuzz/pkg/parser/libfuzzer/stacktrace"
+)
+
+func TestWriteTestExecutionReport(t *testing.T) {
+	results := []*FuzzTestResult{
+		{
+			FuzzTest:   "my_fuzz_test",
+			SourceFile: filepath.Join("src", "my_fuzz_test.cpp"),
+			Duration:   1500 * time.Millisecond,
+			Findings: []*finding.Finding{
+				{
+					Name:    "funky_fox",
+					Type:    finding.ErrorTypeCrash,
+					Details: "heap-buffer-overflow on address 0x1234",
+					StackTrace: []*stacktrace.StackFrame{{
+						SourceFile: "src/explore_me.cpp",
+						Line:       18,
+						Column:     11,
+						Function:   "exploreMe",
+					}},
+					Reproducibility: finding.NewReproducibility([]*finding.ReproAttempt{
+						{Reproduced: true, Duration: 100 * time.Millisecond},
+						{Reproduced: true, Duration: 300 * time.Millisecond},
+					}),
+				},
+				{Name: "fixed_finding", Status: finding.StatusFixed},
+				{Name: "suppressed_finding"},
+			},
+			Suppressions: &finding.Suppressions{Rules: []*finding.SuppressionRule{
+				{Finding: "suppressed_finding", Reason: "false positive"},
+			}},
+		},
+	}
+
+	path := filepath.Join(t.TempDir(), "reports", "test-executions.xml")
+	err := WriteTestExecutionReport(path, results)
+	require.NoError(t, err)
+
+	bytes, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	expected := `<?xml version="1.0" encoding="UTF-8"?>
+<testExecutions version="1">
+  <file path="src/my_fuzz_test.cpp">
+    <testCase name="my_fuzz_test" duration="1500"></testCase>
+    <testCase name="my_fuzz_test: funky_fox" duration="200">
+      <failure message="[funky_fox] heap buffer overflow in exploreMe (src/explore_me.cpp:18:11)">Details: heap-buffer-overflow on address 0x1234&#xA;&#xA;Stack trace:&#xA;    #0 exploreMe src/explore_me.cpp:18:11&#xA;</failure>
+    </testCase>
+    <testCase name="my_fuzz_test: fixed_finding" duration="0">
+      <skipped message="Finding is fixed"></skipped>
+    </testCase>
+    <testCase name="my_fuzz_test: suppressed_finding" duration="0">
+      <skipped message="Finding is suppressed: false positive"></skipped>
+    </testCase>
+  </file>
+</testExecutions>
+`
+	assert.Equal(t, expected, string(bytes))
+}
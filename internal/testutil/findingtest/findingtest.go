@@ -0,0 +1,39 @@
+// Package findingtest provides fixtures for tests of commands which
+// operate on stored findings. It is separate from the testutil package
+// because the tests of the finding package use testutil themselves.
+package findingtest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"code-intelligence.com/cifuzz/pkg/finding"
+	"code-intelligence.com/cifuzz/pkg/parser/libfuzzer/stacktrace"
+)
+
+// SaveFinding stores a finding with the given error ID in projectDir
+// which crashed in function. If fuzzTest is not empty, the stack trace
+// also contains a frame of its fuzz test function.
+func SaveFinding(t *testing.T, projectDir, name, fuzzTest, errorID, function string) *finding.Finding {
+	t.Helper()
+
+	f := &finding.Finding{
+		Name:        name,
+		FuzzTest:    fuzzTest,
+		MoreDetails: &finding.ErrorDetails{ID: errorID},
+		StackTrace: []*stacktrace.StackFrame{
+			{Function: function, SourceFile: "src/parser.cpp", Line: 10},
+		},
+	}
+	if fuzzTest != "" {
+		f.StackTrace = append(f.StackTrace, &stacktrace.StackFrame{
+			Function:   "LLVMFuzzerTestOneInputNoReturn",
+			SourceFile: "fuzz/" + fuzzTest + ".cpp",
+			Line:       5,
+		})
+	}
+	err := f.Save(projectDir)
+	require.NoError(t, err)
+	return f
+}
@@ -0,0 +1,28 @@
+package testutil
+
+import (
+	"os/exec"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// RunGit runs git with the given arguments in dir and fails the test
+// if the command fails.
+func RunGit(t *testing.T, dir string, args ...string) {
+	t.Helper()
+
+	cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+	out, err := cmd.CombinedOutput()
+	require.NoError(t, err, string(out))
+}
+
+// InitGitRepo initializes a git repository in dir with a user
+// configured, so that commits can be created in it.
+func InitGitRepo(t *testing.T, dir string) {
+	t.Helper()
+
+	RunGit(t, dir, "init")
+	RunGit(t, dir, "config", "user.email", "you@example.com")
+	RunGit(t, dir, "config", "user.name", "Your Name")
+}
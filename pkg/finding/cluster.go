@@ -0,0 +1,213 @@
+package finding
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+
+	"code-intelligence.com/cifuzz/pkg/parser/libfuzzer/stacktrace"
+)
+
+// DefaultClusterFrames is the default number of crashing frames which
+// findings must share to be in the same cluster
+const DefaultClusterFrames = 3
+
+// The functions which call the code under test from a C/C++ or Java
+// fuzz test
+var harnessFunctions = []string{
+	"LLVMFuzzerTestOneInput",
+	"LLVMFuzzerTestOneInputNoReturn",
+}
+
+const javaHarnessMethod = ".fuzzerTestOneInput"
+
+// Cluster is a group of findings which have the same root cause but
+// were possibly found by different fuzz tests. Findings are in the same
+// cluster if they have the same error ID and share the top crashing
+// frames below the fuzz test harness.
+type Cluster struct {
+	// A short hash of the error ID and the shared frames which is
+	// stable across runs
+	ID      string `json:"id"`
+	ErrorID string `json:"error_id"`
+	// The shared crashing frames in the format "<function> (<source
+	// file>)", with normalized function names and the top frame first
+	Frames   []string   `json:"frames"`
+	Findings []*Finding `json:"findings"`
+}
+
+// ClusterFindings groups the findings by their root cause. The
+// clusters are sorted by the number of findings, starting with the
+// largest one.
+//
+// Findings without crashing frames below the harness, like most
+// timeouts and leaks, can't be told apart by their error ID alone, so
+// they are only grouped with findings of the same fuzz test which have
+// the same dedup key (see DedupKey). Otherwise, changing the status of
+// the cluster of one timeout would change the status of all timeouts in
+// the project.
+func ClusterFindings(findings []*Finding, numFrames int) []*Cluster {
+	var clusters []*Cluster
+	clustersByID := make(map[string]*Cluster)
+	for _, f := range findings {
+		errorID, frames := f.clusterKey(numFrames)
+		id := clusterID(errorID, frames)
+		if len(frames) == 0 {
+			id = clusterID(f.DedupKey(numFrames), nil)
+		}
+		c, ok := clustersByID[id]
+		if !ok {
+			c = &Cluster{ID: id, ErrorID: errorID, Frames: frames}
+			clustersByID[id] = c
+			clusters = append(clusters, c)
+		}
+		c.Findings = append(c.Findings, f)
+	}
+
+	sort.SliceStable(clusters, func(i, j int) bool {
+		return len(clusters[i].Findings) > len(clusters[j].Findings)
+	})
+	return clusters
+}
+
+// ListClusteredFindings returns the findings of the project which are
+// clustered by default. Closed and suppressed findings are only
+// included if all is true.
+func ListClusteredFindings(projectDir string, all bool) ([]*Finding, error) {
+	// Don't enhance the findings with error details, because they might
+	// be saved again
+	findings, err := ListFindings(projectDir, nil)
+	if err != nil {
+		return nil, err
+	}
+	if all {
+		return findings, nil
+	}
+
+	suppressions, err := LoadSuppressions(projectDir)
+	if err != nil {
+		return nil, err
+	}
+	var result []*Finding
+	for _, f := range findings {
+		if f.GetStatus().IsClosed() || suppressions.Match(f) != nil {
+			continue
+		}
+		result = append(result, f)
+	}
+	return result, nil
+}
+
+// FindCluster returns the cluster which contains the finding with the
+// given name, or nil if no cluster contains it
+func FindCluster(clusters []*Cluster, name string) *Cluster {
+	for _, c := range clusters {
+		for _, f := range c.Findings {
+			if f.Name == name {
+				return c
+			}
+		}
+	}
+	return nil
+}
+
+// FuzzTests returns the sorted names of the fuzz tests which found the
+// findings of the cluster
+func (c *Cluster) FuzzTests() []string {
+	seen := make(map[string]bool)
+	var fuzzTests []string
+	for _, f := range c.Findings {
+		if f.FuzzTest == "" || seen[f.FuzzTest] {
+			continue
+		}
+		seen[f.FuzzTest] = true
+		fuzzTests = append(fuzzTests, f.FuzzTest)
+	}
+	sort.Strings(fuzzTests)
+	return fuzzTests
+}
+
+// Location returns the top shared crashing frame of the cluster, or an
+// empty string if the findings don't have crashing frames
+func (c *Cluster) Location() string {
+	if len(c.Frames) == 0 {
+		return ""
+	}
+	return c.Frames[0]
+}
+
+func clusterID(errorID string, frames []string) string {
+	hash := sha256.Sum256([]byte(strings.Join(append([]string{errorID}, frames...), "\n")))
+	return hex.EncodeToString(hash[:])[:8]
+}
+
+// clusterKey returns the error ID and the top numFrames crashing frames
+// of the finding
+func (f *Finding) clusterKey(numFrames int) (string, []string) {
+	// Like for the dedup key, findings for which no error ID could be
+	// determined are identified by their normalized details instead
+	errorID := f.errorIDOrNormalizedDetails()
+
+	var frames []string
+	for _, frame := range f.CrashingFrames() {
+		if len(frames) == numFrames {
+			break
+		}
+		s := NormalizeFunctionName(frame.Function)
+		if frame.SourceFile != "" {
+			s += fmt.Sprintf(" (%s)", frame.SourceFile)
+		}
+		frames = append(frames, s)
+	}
+	return errorID, frames
+}
+
+// CrashingFrames returns the in-project stack frames of the finding
+// which belong to the code under test, i.e. without the frames of the
+// libFuzzer, sanitizer and Jazzer runtimes and without the frames of
+// the fuzz test harness. Helper functions which are defined in the same
+// source file as the harness function are considered part of the
+// harness.
+func (f *Finding) CrashingFrames() []*stacktrace.StackFrame {
+	var frames []*stacktrace.StackFrame
+	harnessFile := ""
+	for _, frame := range f.StackTrace {
+		if f.isHarnessFrame(frame) {
+			harnessFile = frame.SourceFile
+			break
+		}
+		if !IsProjectFrame(frame) {
+			continue
+		}
+		frames = append(frames, frame)
+	}
+
+	if harnessFile != "" {
+		for len(frames) > 0 && frames[len(frames)-1].SourceFile == harnessFile {
+			frames = frames[:len(frames)-1]
+		}
+	}
+	return frames
+}
+
+func (f *Finding) isHarnessFrame(frame *stacktrace.StackFrame) bool {
+	for _, function := range harnessFunctions {
+		if frame.Function == function {
+			return true
+		}
+	}
+	if strings.HasSuffix(frame.Function, javaHarnessMethod) {
+		return true
+	}
+	// The fuzz test of Java findings is the class which contains the
+	// fuzz test method
+	if f.FuzzTest != "" {
+		class := strings.Split(f.FuzzTest, "::")[0]
+		if strings.HasPrefix(frame.Function, class+".") {
+			return true
+		}
+	}
+	return false
+}
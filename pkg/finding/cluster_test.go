@@ -0,0 +1,113 @@
+package finding
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"code-intelligence.com/cifuzz/pkg/parser/libfuzzer/stacktrace"
+)
+
+func TestClusterFindings(t *testing.T) {
+	parseFrames := []*stacktrace.StackFrame{
+		{Function: "__asan_memcpy"},
+		{Function: "parse", SourceFile: "src/parser.cpp", Line: 10},
+		{Function: "decode", SourceFile: "src/decoder.cpp", Line: 20},
+	}
+	withHarness := func(frames []*stacktrace.StackFrame, harness ...*stacktrace.StackFrame) []*stacktrace.StackFrame {
+		return append(append([]*stacktrace.StackFrame{}, frames...), harness...)
+	}
+	findings := []*Finding{
+		{
+			Name:        "via_decode_fuzzer",
+			FuzzTest:    "decode_fuzzer",
+			MoreDetails: &ErrorDetails{ID: "heap_buffer_overflow"},
+			StackTrace: withHarness(parseFrames,
+				&stacktrace.StackFrame{Function: "LLVMFuzzerTestOneInputNoReturn", SourceFile: "fuzz/decode_fuzzer.cpp", Line: 5}),
+		},
+		{
+			// Found by another fuzz test, which calls the code under
+			// test via a helper function of the fuzz test
+			Name:        "via_stream_fuzzer",
+			FuzzTest:    "stream_fuzzer",
+			MoreDetails: &ErrorDetails{ID: "heap_buffer_overflow"},
+			StackTrace: withHarness(parseFrames,
+				&stacktrace.StackFrame{Function: "feed", SourceFile: "fuzz/stream_fuzzer.cpp", Line: 12},
+				&stacktrace.StackFrame{Function: "LLVMFuzzerTestOneInputNoReturn", SourceFile: "fuzz/stream_fuzzer.cpp", Line: 20}),
+		},
+		{
+			// Same location, but a different error
+			Name:        "use_after_free",
+			FuzzTest:    "decode_fuzzer",
+			MoreDetails: &ErrorDetails{ID: "heap_use_after_free"},
+			StackTrace: withHarness(parseFrames,
+				&stacktrace.StackFrame{Function: "LLVMFuzzerTestOneInputNoReturn", SourceFile: "fuzz/decode_fuzzer.cpp", Line: 5}),
+		},
+		{
+			Name:        "java_finding",
+			FuzzTest:    "com.example.ParserFuzzTest::fuzz",
+			MoreDetails: &ErrorDetails{ID: "java_exception"},
+			StackTrace: []*stacktrace.StackFrame{
+				{Function: "com.example.Parser.parse", SourceFile: "com.example.Parser", Line: 3},
+				{Function: "com.example.ParserFuzzTest.fuzz", SourceFile: "com.example.ParserFuzzTest", Line: 8},
+				{Function: "com.code_intelligence.jazzer.driver.FuzzTargetRunner.runOne"},
+			},
+		},
+	}
+
+	clusters := ClusterFindings(findings, DefaultClusterFrames)
+	require.Len(t, clusters, 3)
+
+	c := clusters[0]
+	assert.Equal(t, "heap_buffer_overflow", c.ErrorID)
+	assert.Equal(t, []string{"parse (src/parser.cpp)", "decode (src/decoder.cpp)"}, c.Frames)
+	assert.Equal(t, "parse (src/parser.cpp)", c.Location())
+	assert.Equal(t, []string{"decode_fuzzer", "stream_fuzzer"}, c.FuzzTests())
+	require.Len(t, c.Findings, 2)
+	assert.Regexp(t, `^[0-9a-f]{8}$`, c.ID)
+
+	assert.Equal(t, "heap_use_after_free", clusters[1].ErrorID)
+	assert.Equal(t, []string{"com.example.Parser.parse (com.example.Parser)"}, clusters[2].Frames)
+
+	assert.Equal(t, c, FindCluster(clusters, "via_stream_fuzzer"))
+	assert.Nil(t, FindCluster(clusters, "no_such_finding"))
+
+	// The cluster IDs are stable
+	assert.Equal(t, c.ID, ClusterFindings(findings[:2], DefaultClusterFrames)[0].ID)
+
+	// With a single frame, only the top crashing frame must match
+	clusters = ClusterFindings([]*Finding{
+		findings[0],
+		{
+			Name:        "other_caller",
+			MoreDetails: &ErrorDetails{ID: "heap_buffer_overflow"},
+			StackTrace:  []*stacktrace.StackFrame{{Function: "parse", SourceFile: "src/parser.cpp"}, {Function: "lex", SourceFile: "src/lexer.cpp"}},
+		},
+	}, 1)
+	require.Len(t, clusters, 1)
+}
+
+func TestClusterFindings_WithoutCrashingFrames(t *testing.T) {
+	timeout := func(name, fuzzTest, input string) *Finding {
+		return &Finding{
+			Name:        name,
+			FuzzTest:    fuzzTest,
+			MoreDetails: &ErrorDetails{ID: "timeout"},
+			InputData:   []byte(input),
+			StackTrace: []*stacktrace.StackFrame{
+				{Function: "fuzzer::Fuzzer::AlarmCallback", SourceFile: "FuzzerLoop.cpp", Line: 301},
+			},
+		}
+	}
+	clusters := ClusterFindings([]*Finding{
+		timeout("decode_timeout", "decode_fuzzer", "A"),
+		timeout("stream_timeout", "stream_fuzzer", "A"),
+		timeout("other_decode_timeout", "decode_fuzzer", "B"),
+		timeout("duplicate_decode_timeout", "decode_fuzzer", "A"),
+	}, DefaultClusterFrames)
+	require.Len(t, clusters, 3)
+	assert.Len(t, clusters[0].Findings, 2)
+	assert.Equal(t, "decode_timeout", clusters[0].Findings[0].Name)
+	assert.Equal(t, "duplicate_decode_timeout", clusters[0].Findings[1].Name)
+}
@@ -0,0 +1,43 @@
+package finding
+
+import (
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// DataProviderLogEnvVar is the environment variable which tells the
+// FuzzedDataProvider shipped with cifuzz (include/fuzzer), or the Jazzer
+// hooks for Jazzer's FuzzedDataProvider (tools/jazzer-hooks), to log each
+// call of its public methods and the returned value to the specified
+// file, one call per line, e.g.
+//
+//	ConsumeIntegral<int>() = 42
+//	ConsumeRandomLengthString() = "ab"
+const DataProviderLogEnvVar = "CIFUZZ_DATA_PROVIDER_LOG"
+
+// ReadDataProviderLog returns the calls logged by the FuzzedDataProvider
+// to the file specified via DataProviderLogEnvVar
+func ReadDataProviderLog(path string) ([]string, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	var calls []string
+	for _, line := range strings.Split(string(content), "\n") {
+		line = strings.TrimRight(line, "\r")
+		if line != "" {
+			calls = append(calls, line)
+		}
+	}
+	return calls, nil
+}
+
+// SetDataProviderCalls stores the calls of the FuzzedDataProvider with
+// which the fuzz test consumed the crashing input. They are also used
+// as the human-readable representation of the input.
+func (f *Finding) SetDataProviderCalls(calls []string) {
+	f.DataProviderCalls = calls
+	f.HumanReadableInput = strings.Join(calls, "; ")
+}
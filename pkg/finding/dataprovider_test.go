@@ -0,0 +1,84 @@
+package finding
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadDataProviderLog(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "calls")
+	err := os.WriteFile(path, []byte("ConsumeIntegral<int>() = 42\r\n\nConsumeRandomLengthString() = \"ab\"\n"), 0o644)
+	require.NoError(t, err)
+
+	calls, err := ReadDataProviderLog(path)
+	require.NoError(t, err)
+	require.Equal(t, []string{`ConsumeIntegral<int>() = 42`, `ConsumeRandomLengthString() = "ab"`}, calls)
+
+	f := &Finding{}
+	f.SetDataProviderCalls(calls)
+	require.Equal(t, calls, f.DataProviderCalls)
+	require.Equal(t, `ConsumeIntegral<int>() = 42; ConsumeRandomLengthString() = "ab"`, f.HumanReadableInput)
+}
+
+func TestDataProviderLog(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("The data provider log is tested on Unix only")
+	}
+	compiler, err := exec.LookPath("c++")
+	if err != nil {
+		t.Skip("C++ compiler not found")
+	}
+
+	dir := t.TempDir()
+	source := filepath.Join(dir, "main.cpp")
+	err = os.WriteFile(source, []byte(`#include <fuzzer/FuzzedDataProvider.h>
+
+int main() {
+  const uint8_t data[] = {'a', 'b', '\\', 'x', 1, 2, 3, 4, 5, 6, 42, 0, 0, 0};
+  FuzzedDataProvider fdp(data, sizeof(data));
+  fdp.ConsumeRandomLengthString();
+  fdp.ConsumeIntegral<int>();
+  fdp.ConsumeBool();
+  fdp.ConsumeIntegralInRange<int>(-5, 5);
+  fdp.PickValueInArray({1, 2, 3});
+  fdp.ConsumeRemainingBytes<uint8_t>();
+  return 0;
+}
+`), 0o644)
+	require.NoError(t, err)
+	binary := filepath.Join(dir, "main")
+	// The header is shipped in the include directory of the repository.
+	// The tests of this package don't run in the package directory, so
+	// we determine its path via the path of this file.
+	_, thisFile, _, ok := runtime.Caller(0)
+	require.True(t, ok)
+	includeDir := filepath.Join(filepath.Dir(thisFile), "..", "..", "include")
+	out, err := exec.Command(compiler, "-std=c++11", "-I", includeDir, "-o", binary, source).CombinedOutput()
+	require.NoError(t, err, string(out))
+
+	// Nothing is logged if the environment variable is not set
+	logFile := filepath.Join(dir, "calls")
+	err = exec.Command(binary).Run()
+	require.NoError(t, err)
+	require.NoFileExists(t, logFile)
+
+	cmd := exec.Command(binary)
+	cmd.Env = append(os.Environ(), DataProviderLogEnvVar+"="+logFile)
+	err = cmd.Run()
+	require.NoError(t, err)
+	calls, err := ReadDataProviderLog(logFile)
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		`ConsumeRandomLengthString() = "ab"`,
+		`ConsumeIntegral<int>() = -2147483606`,
+		`ConsumeBool() = false`,
+		`ConsumeIntegralInRange<int>(-5, 5) = 0`,
+		`PickValueInArray(3 values) = index 1`,
+		`ConsumeRemainingBytes<unsigned char>() = "\x01\x02\x03"`,
+	}, calls)
+}
@@ -0,0 +1,322 @@
+package finding
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"code-intelligence.com/cifuzz/pkg/parser/libfuzzer/stacktrace"
+)
+
+const (
+	// DedupStrategyStackTrace names findings based on the complete
+	// stack trace and the crashing input.
+	DedupStrategyStackTrace = "stack-trace"
+	// DedupStrategyStackHash names findings based on the error ID and
+	// the normalized function names of the top in-project stack frames.
+	DedupStrategyStackHash = "stack-hash"
+
+	DefaultDedupFrames = 3
+)
+
+var validDedupStrategies = []string{DedupStrategyStackTrace, DedupStrategyStackHash}
+
+// DedupOptions configures how findings are deduplicated. It can be set
+// via the "dedup" setting in cifuzz.yaml.
+type DedupOptions struct {
+	Strategy string `mapstructure:"strategy"`
+	Frames   int    `mapstructure:"frames"`
+	// If set, Java findings are identified by the root cause of the
+	// exception instead of the exception which wraps it
+	RootCause bool `mapstructure:"root-cause"`
+}
+
+// Validate checks the options and sets the default values for options
+// which were not set.
+func (o *DedupOptions) Validate() error {
+	if o.Strategy == "" {
+		o.Strategy = DedupStrategyStackTrace
+	}
+	valid := false
+	for _, s := range validDedupStrategies {
+		if o.Strategy == s {
+			valid = true
+		}
+	}
+	if !valid {
+		return errors.Errorf("Invalid dedup strategy %q, valid strategies are: %s",
+			o.Strategy, strings.Join(validDedupStrategies, ", "))
+	}
+
+	if o.Frames < 0 {
+		return errors.Errorf("Invalid number of dedup frames %d, must not be negative", o.Frames)
+	}
+	if o.Frames == 0 {
+		o.Frames = DefaultDedupFrames
+	}
+	return nil
+}
+
+// Prefixes of functions which belong to the runtimes of libFuzzer, the
+// sanitizers or Jazzer. These are ignored when deduplicating findings
+// because they depend on how the error was detected, not on where it
+// happened.
+var runtimeFunctionPrefixes = []string{
+	"__asan",
+	"__hwasan",
+	"__interceptor_",
+	"__lsan",
+	"__msan",
+	"__sanitizer",
+	"__tsan",
+	"__ubsan",
+	"___interceptor_",
+	"fuzzer::",
+	"com.code_intelligence.jazzer.",
+}
+
+var (
+	// Suffixes added by compilers to functions which were cloned during
+	// optimization, e.g. "foo.cold" or "foo.isra.0"
+	cloneSuffixPattern = regexp.MustCompile(`\.(cold|isra|constprop|part|lto_priv|llvm)(\.\d+)*$`)
+	// Java lambdas get a generated name which is not stable across
+	// compilations, e.g. "lambda$fuzzerTestOneInput$0" or
+	// "MyClass$$Lambda$14/0x0000000800c02a00"
+	javaLambdaPattern       = regexp.MustCompile(`lambda\$(\w+)\$\d+`)
+	javaLambdaClassPattern  = regexp.MustCompile(`\$\$Lambda\$\d+(/0x[0-9a-fA-F]+)?`)
+	anonymousNamespaceRegex = regexp.MustCompile(`\(anonymous namespace\)::`)
+	// Addresses and other numbers in the details of a finding, like
+	// PIDs, thread IDs or sizes, which differ between findings with the
+	// same root cause
+	hexAddressPattern = regexp.MustCompile(`\b0x[0-9a-fA-F]+\b`)
+	numberPattern     = regexp.MustCompile(`\b\d+\b`)
+
+	windowsAbsPathPattern = regexp.MustCompile(`^[A-Za-z]:/`)
+)
+
+// DedupKey returns a key which identifies the root cause of the
+// finding. Findings with the same key are considered duplicates. The
+// key consists of the fuzz test, the error ID and the normalized
+// function names and source files of the top numFrames in-project stack
+// frames, ignoring frames from the libFuzzer, sanitizer and Jazzer
+// runtimes.
+//
+// Findings without in-project frames, like most timeouts, OOMs and
+// leaks, can't be told apart by their error ID alone, so their key
+// consists of the complete normalized stack trace and a hash of the
+// crashing input instead.
+func (f *Finding) DedupKey(numFrames int) string {
+	parts := []string{f.FuzzTest, f.errorIDOrNormalizedDetails()}
+	var frames []string
+	for _, frame := range f.StackTrace {
+		if len(frames) >= numFrames {
+			break
+		}
+		if !IsProjectFrame(frame) {
+			continue
+		}
+		frames = append(frames, normalizedFrame(frame))
+	}
+	if !f.hasProjectFrame() {
+		for _, frame := range f.StackTrace {
+			frames = append(frames, normalizedFrame(frame))
+		}
+		hash := sha256.Sum256(f.InputData)
+		frames = append(frames, hex.EncodeToString(hash[:]))
+	}
+	return strings.Join(append(parts, frames...), "\n")
+}
+
+func (f *Finding) hasProjectFrame() bool {
+	for _, frame := range f.StackTrace {
+		if IsProjectFrame(frame) {
+			return true
+		}
+	}
+	return false
+}
+
+func normalizedFrame(frame *stacktrace.StackFrame) string {
+	return fmt.Sprintf("%s@%s", NormalizeFunctionName(frame.Function), frame.SourceFile)
+}
+
+// errorIDOrNormalizedDetails returns the error ID of the finding. For
+// findings for which no error ID could be determined, it returns the
+// details instead, without the addresses and numbers they contain.
+func (f *Finding) errorIDOrNormalizedDetails() string {
+	if errorID := f.ErrorID(); errorID != "" {
+		return errorID
+	}
+	details := hexAddressPattern.ReplaceAllString(f.Details, "0x")
+	return numberPattern.ReplaceAllString(details, "N")
+}
+
+// RootCauseDedupKey is like DedupKey, but uses the exception type and
+// the stack trace of the root cause of a Java exception, so that
+// findings which are caused by the same exception are considered
+// duplicates, even if the exception was wrapped differently. For
+// findings without causes, it's the same as DedupKey.
+func (f *Finding) RootCauseDedupKey(numFrames int) string {
+	rootCause := f.RootCause()
+	if rootCause == nil {
+		return f.DedupKey(numFrames)
+	}
+	rootCauseFinding := &Finding{
+		Details:    rootCause.Exception,
+		StackTrace: rootCause.StackTrace,
+		InputData:  f.InputData,
+		FuzzTest:   f.FuzzTest,
+	}
+	return rootCauseFinding.DedupKey(numFrames)
+}
+
+// RootCause returns the last cause of a Java exception or nil if the
+// finding doesn't have any causes
+func (f *Finding) RootCause() *stacktrace.ExceptionCause {
+	if len(f.Causes) == 0 {
+		return nil
+	}
+	return f.Causes[len(f.Causes)-1]
+}
+
+// Key returns the dedup key of the finding as configured by the options
+func (o *DedupOptions) Key(f *Finding) string {
+	if o.RootCause {
+		return f.RootCauseDedupKey(o.Frames)
+	}
+	return f.DedupKey(o.Frames)
+}
+
+// IsProjectFrame returns true if the stack frame belongs to a source
+// file in the project directory and not to the libFuzzer, sanitizer or
+// Jazzer runtime. The output parsers only store frames of source files
+// in the project directory, with paths relative to it, but frames of
+// findings which were created in other ways can contain absolute paths
+// or no source file.
+func IsProjectFrame(frame *stacktrace.StackFrame) bool {
+	if frame.SourceFile == "" || IsRuntimeFrame(frame) {
+		return false
+	}
+	path := filepath.ToSlash(frame.SourceFile)
+	return !strings.HasPrefix(path, "/") && !windowsAbsPathPattern.MatchString(path) &&
+		path != ".." && !strings.HasPrefix(path, "../")
+}
+
+// IsRuntimeFrame returns true if the stack frame belongs to the
+// libFuzzer, sanitizer or Jazzer runtime
+func IsRuntimeFrame(frame *stacktrace.StackFrame) bool {
+	for _, prefix := range runtimeFunctionPrefixes {
+		if strings.HasPrefix(frame.Function, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// NormalizeFunctionName removes the parts of a function name which can
+// differ between builds of the same code, like template arguments,
+// suffixes of cloned functions and generated names of Java lambdas.
+func NormalizeFunctionName(function string) string {
+	function = anonymousNamespaceRegex.ReplaceAllString(function, "")
+	function = stripTemplateArgs(function)
+	for cloneSuffixPattern.MatchString(function) {
+		function = cloneSuffixPattern.ReplaceAllString(function, "")
+	}
+	function = javaLambdaPattern.ReplaceAllString(function, "lambda$$$1")
+	function = javaLambdaClassPattern.ReplaceAllString(function, "$$$$Lambda")
+	return function
+}
+
+// stripTemplateArgs removes (possibly nested) template arguments from a
+// C++ function name, e.g. "Foo<int>::bar<std::vector<char>>" becomes
+// "Foo::bar".
+func stripTemplateArgs(function string) string {
+	// Don't treat the "<" and ">" of operator names as template brackets
+	if strings.Contains(function, "operator") {
+		return function
+	}
+
+	var b strings.Builder
+	depth := 0
+	for _, c := range function {
+		switch {
+		case c == '<':
+			depth++
+		case c == '>' && depth > 0:
+			depth--
+		case depth == 0:
+			b.WriteRune(c)
+		}
+	}
+	return b.String()
+}
+
+// DuplicateGroup is a group of findings which have the same dedup key.
+type DuplicateGroup struct {
+	// The finding which is kept, this is the oldest finding of the group
+	Finding *Finding
+	// The findings which are merged into Finding
+	Duplicates []*Finding
+}
+
+// FindDuplicates groups the findings by their dedup key and returns
+// all groups which contain more than one finding.
+func FindDuplicates(findings []*Finding, opts *DedupOptions) []*DuplicateGroup {
+	// Sort the findings by date, starting with the oldest, so that
+	// the first finding of each group is the one that is kept
+	sorted := make([]*Finding, len(findings))
+	copy(sorted, findings)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].CreatedAt.Before(sorted[j].CreatedAt)
+	})
+
+	var groups []*DuplicateGroup
+	groupsByKey := make(map[string]*DuplicateGroup)
+	for _, f := range sorted {
+		key := opts.Key(f)
+		group, ok := groupsByKey[key]
+		if !ok {
+			group = &DuplicateGroup{Finding: f}
+			groupsByKey[key] = group
+			groups = append(groups, group)
+			continue
+		}
+		group.Duplicates = append(group.Duplicates, f)
+	}
+
+	var res []*DuplicateGroup
+	for _, group := range groups {
+		if len(group.Duplicates) > 0 {
+			res = append(res, group)
+		}
+	}
+	return res
+}
+
+// Merge records the names of the duplicates in the kept finding and
+// deletes the duplicates.
+func (g *DuplicateGroup) Merge(projectDir string) error {
+	for _, d := range g.Duplicates {
+		g.Finding.Duplicates = append(g.Finding.Duplicates, d.Name)
+		g.Finding.Duplicates = append(g.Finding.Duplicates, d.Duplicates...)
+	}
+
+	err := g.Finding.Save(projectDir)
+	if err != nil {
+		return err
+	}
+
+	for _, d := range g.Duplicates {
+		err = d.Delete(projectDir)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
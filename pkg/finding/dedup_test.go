@@ -0,0 +1,220 @@
+package finding
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"code-intelligence.com/cifuzz/pkg/parser/libfuzzer/stacktrace"
+)
+
+func TestNormalizeFunctionName(t *testing.T) {
+	tests := map[string]string{
+		"exploreMe": "exploreMe",
+		"Foo<int>::bar<std::vector<char, std::allocator<char>>>": "Foo::bar",
+		"(anonymous namespace)::parse":                           "parse",
+		"parse.cold":                                             "parse",
+		"parse.isra.0":                                           "parse",
+		"parse.constprop.1.isra.0":                               "parse",
+		"operator<<":                                             "operator<<",
+		"com.example.Foo.lambda$fuzzerTestOneInput$3":            "com.example.Foo.lambda$fuzzerTestOneInput",
+		"com.example.Foo$$Lambda$14/0x0000000800c02a00.accept":   "com.example.Foo$$Lambda.accept",
+	}
+	for in, expected := range tests {
+		require.Equal(t, expected, NormalizeFunctionName(in), in)
+	}
+}
+
+func TestDedupKey(t *testing.T) {
+	f1 := &Finding{
+		MoreDetails: &ErrorDetails{ID: "heap_buffer_overflow"},
+		StackTrace: []*stacktrace.StackFrame{
+			{Function: "__asan_memcpy", SourceFile: "asan_interceptors.cpp", Line: 22},
+			{Function: "parse<int>", SourceFile: "src/parser.cpp", Line: 10},
+			{Function: "handle", SourceFile: "src/handler.cpp", Line: 20},
+			{Function: "LLVMFuzzerTestOneInput", SourceFile: "fuzz_test.cpp", Line: 30},
+		},
+	}
+	// Same root cause, but different lines, template arguments and
+	// call path below the top frames
+	f2 := &Finding{
+		MoreDetails: &ErrorDetails{ID: "heap_buffer_overflow"},
+		StackTrace: []*stacktrace.StackFrame{
+			{Function: "parse<char>", SourceFile: "src/parser.cpp", Line: 12},
+			{Function: "handle", SourceFile: "src/handler.cpp", Line: 25},
+			{Function: "other", SourceFile: "src/other.cpp", Line: 5},
+		},
+	}
+	require.Equal(t, f1.DedupKey(2), f2.DedupKey(2))
+	require.NotEqual(t, f1.DedupKey(3), f2.DedupKey(3))
+
+	// Different error ID
+	f3 := &Finding{
+		MoreDetails: &ErrorDetails{ID: "use_after_free"},
+		StackTrace:  f2.StackTrace,
+	}
+	require.NotEqual(t, f2.DedupKey(2), f3.DedupKey(2))
+
+	// Frames which are not part of the project are skipped
+	f4 := &Finding{
+		MoreDetails: &ErrorDetails{ID: "heap_buffer_overflow"},
+		StackTrace: []*stacktrace.StackFrame{
+			{Function: "memcpy", SourceFile: "/usr/include/string.h", Line: 5},
+			{Function: "inflate", SourceFile: "", Line: 0},
+			{Function: "parse<int>", SourceFile: "src/parser.cpp", Line: 10},
+			{Function: "vendored", SourceFile: "../third_party/lib.c", Line: 3},
+			{Function: "handle", SourceFile: "src/handler.cpp", Line: 20},
+		},
+	}
+	require.Equal(t, f1.DedupKey(2), f4.DedupKey(2))
+}
+
+func TestDedupKey_WithoutErrorID(t *testing.T) {
+	// Findings without error ID are identified by their details, which
+	// can contain addresses and PIDs
+	f1 := &Finding{
+		Details:    "deadly signal in thread 12 (pid 4711) at 0x7ffd4a2b3c10",
+		StackTrace: []*stacktrace.StackFrame{{Function: "parse", SourceFile: "src/parser.cpp", Line: 10}},
+	}
+	f2 := &Finding{
+		Details:    "deadly signal in thread 3 (pid 815) at 0x7ffc00112233",
+		StackTrace: f1.StackTrace,
+	}
+	require.Equal(t, f1.DedupKey(1), f2.DedupKey(1))
+	require.Len(t, ClusterFindings([]*Finding{f1, f2}, 1), 1)
+
+	f3 := &Finding{
+		Details:    "timeout after 25 seconds",
+		StackTrace: f1.StackTrace,
+	}
+	require.NotEqual(t, f1.DedupKey(1), f3.DedupKey(1))
+}
+
+func TestDedupKey_WithoutProjectFrames(t *testing.T) {
+	timeoutStackTrace := []*stacktrace.StackFrame{
+		{Function: "__sanitizer_print_stack_trace", SourceFile: "sanitizer_stacktrace.cpp", Line: 87},
+		{Function: "fuzzer::Fuzzer::AlarmCallback", SourceFile: "FuzzerLoop.cpp", Line: 301},
+	}
+	f1 := &Finding{
+		FuzzTest:    "decode_fuzzer",
+		MoreDetails: &ErrorDetails{ID: "timeout"},
+		StackTrace:  timeoutStackTrace,
+		InputData:   []byte("A"),
+	}
+	// Timeouts of different fuzz tests are not duplicates
+	f2 := &Finding{
+		FuzzTest:    "stream_fuzzer",
+		MoreDetails: &ErrorDetails{ID: "timeout"},
+		StackTrace:  timeoutStackTrace,
+		InputData:   []byte("A"),
+	}
+	require.NotEqual(t, f1.DedupKey(DefaultDedupFrames), f2.DedupKey(DefaultDedupFrames))
+
+	// Without in-project frames, the crashing input is compared
+	f3 := &Finding{
+		FuzzTest:    "decode_fuzzer",
+		MoreDetails: &ErrorDetails{ID: "timeout"},
+		StackTrace:  timeoutStackTrace,
+		InputData:   []byte("B"),
+	}
+	require.NotEqual(t, f1.DedupKey(DefaultDedupFrames), f3.DedupKey(DefaultDedupFrames))
+
+	f4 := &Finding{
+		FuzzTest:    "decode_fuzzer",
+		MoreDetails: &ErrorDetails{ID: "timeout"},
+		StackTrace:  timeoutStackTrace,
+		InputData:   []byte("A"),
+	}
+	require.Equal(t, f1.DedupKey(DefaultDedupFrames), f4.DedupKey(DefaultDedupFrames))
+
+	opts := &DedupOptions{Frames: DefaultDedupFrames}
+	require.Empty(t, FindDuplicates([]*Finding{f1, f2, f3}, opts))
+}
+
+func TestFindDuplicates_Merge(t *testing.T) {
+	testDir, err := os.MkdirTemp(testBaseDir, "dedup-test-")
+	require.NoError(t, err)
+
+	stackTrace := []*stacktrace.StackFrame{{Function: "parse", SourceFile: "src/parser.cpp", Line: 10}}
+	oldest := &Finding{
+		Name:        "oldest_finding",
+		CreatedAt:   time.Now().Add(-time.Hour),
+		MoreDetails: &ErrorDetails{ID: "heap_buffer_overflow"},
+		StackTrace:  stackTrace,
+	}
+	duplicate := &Finding{
+		Name:        "duplicate_finding",
+		CreatedAt:   time.Now(),
+		MoreDetails: &ErrorDetails{ID: "heap_buffer_overflow"},
+		StackTrace:  stackTrace,
+	}
+	other := &Finding{
+		Name:        "other_finding",
+		CreatedAt:   time.Now(),
+		MoreDetails: &ErrorDetails{ID: "use_after_free"},
+		StackTrace:  stackTrace,
+	}
+	for _, f := range []*Finding{oldest, duplicate, other} {
+		require.NoError(t, f.Save(testDir))
+	}
+
+	groups := FindDuplicates([]*Finding{duplicate, other, oldest}, &DedupOptions{Frames: DefaultDedupFrames})
+	require.Len(t, groups, 1)
+	require.Equal(t, oldest, groups[0].Finding)
+	require.Equal(t, []*Finding{duplicate}, groups[0].Duplicates)
+
+	err = groups[0].Merge(testDir)
+	require.NoError(t, err)
+
+	findings, err := ListFindings(testDir, nil)
+	require.NoError(t, err)
+	require.Len(t, findings, 2)
+
+	merged, err := LoadFinding(testDir, oldest.Name, nil)
+	require.NoError(t, err)
+	require.Equal(t, []string{duplicate.Name}, merged.Duplicates)
+}
+
+func TestRootCauseDedupKey(t *testing.T) {
+	rootCause := &stacktrace.ExceptionCause{
+		Exception:  "java.lang.IllegalStateException",
+		Message:    "Unexpected token",
+		StackTrace: []*stacktrace.StackFrame{{Function: "next", SourceFile: "com.example.Lexer", Line: 5}},
+	}
+	f1 := &Finding{
+		Details:    "java.lang.RuntimeException: Failed to parse",
+		StackTrace: []*stacktrace.StackFrame{{Function: "parse", SourceFile: "com.example.Parser", Line: 20}},
+		Causes:     []*stacktrace.ExceptionCause{rootCause},
+	}
+	f2 := &Finding{
+		Details:    "java.io.UncheckedIOException: Failed to read",
+		StackTrace: []*stacktrace.StackFrame{{Function: "read", SourceFile: "com.example.Reader", Line: 7}},
+		Causes: []*stacktrace.ExceptionCause{
+			{Exception: "java.lang.RuntimeException", Message: "Failed to parse"},
+			rootCause,
+		},
+	}
+
+	opts := &DedupOptions{Frames: DefaultDedupFrames}
+	require.NotEqual(t, opts.Key(f1), opts.Key(f2))
+	opts.RootCause = true
+	require.Equal(t, opts.Key(f1), opts.Key(f2))
+	require.Equal(t, rootCause, f2.RootCause())
+
+	// Findings without causes use the normal dedup key
+	f3 := &Finding{Details: "java.lang.NullPointerException"}
+	require.Nil(t, f3.RootCause())
+	require.Equal(t, f3.DedupKey(DefaultDedupFrames), opts.Key(f3))
+}
+
+func TestDedupOptions_Validate(t *testing.T) {
+	opts := &DedupOptions{}
+	require.NoError(t, opts.Validate())
+	require.Equal(t, DedupStrategyStackTrace, opts.Strategy)
+	require.Equal(t, DefaultDedupFrames, opts.Frames)
+
+	opts = &DedupOptions{Strategy: "foo"}
+	require.Error(t, opts.Validate())
+}
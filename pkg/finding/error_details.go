@@ -0,0 +1,79 @@
+package finding
+
+import (
+	_ "embed"
+	"encoding/json"
+	"sync"
+)
+
+// The error details which are shipped with cifuzz. They are used when
+// the error details can't be fetched from the server, for example
+// because the user is not logged in, and for error IDs which are not
+// known to the server.
+//
+//go:embed error_details.json
+var localErrorDetailsJSON []byte
+
+var (
+	localErrorDetails     []ErrorDetails
+	localErrorDetailsOnce sync.Once
+)
+
+// LocalErrorDetails returns the error details which are shipped with
+// cifuzz.
+func LocalErrorDetails() []ErrorDetails {
+	localErrorDetailsOnce.Do(func() {
+		var catalog struct {
+			ErrorDetails []ErrorDetails `json:"error_details"`
+		}
+		err := json.Unmarshal(localErrorDetailsJSON, &catalog)
+		if err != nil {
+			// The file is embedded, so this can only happen if it's
+			// invalid, which is checked by the tests
+			panic(err)
+		}
+		localErrorDetails = catalog.ErrorDetails
+	})
+	return localErrorDetails
+}
+
+// LocalErrorDetailsByID returns the error details shipped with cifuzz
+// for the given error ID, or nil if there are none.
+func LocalErrorDetailsByID(id string) *ErrorDetails {
+	if id == "" {
+		return nil
+	}
+	for _, d := range LocalErrorDetails() {
+		if d.ID == id {
+			d := d
+			return &d
+		}
+	}
+	return nil
+}
+
+// fillFrom sets all fields which are not set yet to the values of the
+// other error details
+func (d *ErrorDetails) fillFrom(other *ErrorDetails) {
+	if d.Name == "" {
+		d.Name = other.Name
+	}
+	if d.Description == "" {
+		d.Description = other.Description
+	}
+	if d.Severity == nil {
+		d.Severity = other.Severity
+	}
+	if d.Mitigation == "" {
+		d.Mitigation = other.Mitigation
+	}
+	if d.Links == nil {
+		d.Links = other.Links
+	}
+	if d.OwaspDetails == nil {
+		d.OwaspDetails = other.OwaspDetails
+	}
+	if d.CweDetails == nil {
+		d.CweDetails = other.CweDetails
+	}
+}
@@ -0,0 +1,229 @@
+package finding
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	SortByDate     = "date"
+	SortBySeverity = "severity"
+	SortByFuzzTest = "fuzz-test"
+
+	GroupByFuzzTest = "fuzz-test"
+	GroupByErrorID  = "error-id"
+	GroupByLocation = "location"
+)
+
+var (
+	ValidSortBy  = []string{SortByDate, SortBySeverity, SortByFuzzTest}
+	ValidGroupBy = []string{GroupByFuzzTest, GroupByErrorID, GroupByLocation}
+)
+
+// The minimum scores of the severity levels, as defined by CVSS
+var severityLevelMinScores = map[SeverityLevel]float32{
+	SeverityLevelCritical: 9.0,
+	SeverityLevelHigh:     7.0,
+	SeverityLevelMedium:   4.0,
+	SeverityLevelLow:      0.1,
+}
+
+// Filter selects findings by their properties. Fields which are not set
+// don't restrict the selected findings.
+type Filter struct {
+	FuzzTest string
+	Type     ErrorType
+	ErrorID  string
+	// Findings without a severity are not selected if this is set
+	MinSeverity float32
+	Since       time.Time
+	Statuses    []Status
+	// Selects findings which have this owner, e.g. "@org/team" or an
+	// email address
+	Owner string
+}
+
+// SeverityLevelForScore returns the severity level of the given score.
+// Scores below the minimum of the lowest level return an empty level.
+func SeverityLevelForScore(score float32) SeverityLevel {
+	for _, level := range []SeverityLevel{SeverityLevelCritical, SeverityLevelHigh, SeverityLevelMedium, SeverityLevelLow} {
+		if score >= severityLevelMinScores[level] {
+			return level
+		}
+	}
+	return ""
+}
+
+// ParseSeverity parses either a severity score (e.g. "7.5") or the name
+// of a severity level (e.g. "high"), in which case the minimum score of
+// that level is returned.
+func ParseSeverity(s string) (float32, error) {
+	if score, ok := severityLevelMinScores[SeverityLevel(strings.ToUpper(s))]; ok {
+		return score, nil
+	}
+	score, err := strconv.ParseFloat(s, 32)
+	if err != nil || score < 0 || score > 10 {
+		return 0, errors.Errorf("Invalid severity %q, must be a score between 0 and 10 or one of: critical, high, medium, low", s)
+	}
+	return float32(score), nil
+}
+
+// ParseSince parses either a date (e.g. "2023-06-01"), a timestamp in
+// RFC 3339 format or a duration relative to now (e.g. "12h" or "7d").
+func ParseSince(s string, now time.Time) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation(expiryDateLayout, s, time.Local); err == nil {
+		return t, nil
+	}
+	// time.ParseDuration doesn't support days
+	if strings.HasSuffix(s, "d") {
+		if n, err := strconv.Atoi(strings.TrimSuffix(s, "d")); err == nil && n >= 0 {
+			return now.AddDate(0, 0, -n), nil
+		}
+	}
+	if d, err := time.ParseDuration(s); err == nil && d >= 0 {
+		return now.Add(-d), nil
+	}
+	return time.Time{}, errors.Errorf("Invalid time %q, must be a date (YYYY-MM-DD), an RFC 3339 timestamp or a duration like 12h or 7d", s)
+}
+
+// Matches returns true if the finding matches all criteria of the filter
+func (filter *Filter) Matches(f *Finding) bool {
+	if filter.FuzzTest != "" && f.FuzzTest != filter.FuzzTest {
+		return false
+	}
+	if filter.Type != "" && !strings.EqualFold(string(f.Type), string(filter.Type)) {
+		return false
+	}
+	if filter.ErrorID != "" && f.ErrorID() != filter.ErrorID {
+		return false
+	}
+	if filter.MinSeverity > 0 && f.SeverityScore() < filter.MinSeverity {
+		return false
+	}
+	if !filter.Since.IsZero() && f.CreatedAt.Before(filter.Since) {
+		return false
+	}
+	if filter.Owner != "" && !f.HasOwner(filter.Owner) {
+		return false
+	}
+	if len(filter.Statuses) > 0 {
+		matched := false
+		for _, s := range filter.Statuses {
+			if f.GetStatus() == s {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	return true
+}
+
+// ErrorID returns the error ID of the finding or an empty string if it
+// is unknown
+func (f *Finding) ErrorID() string {
+	if f.MoreDetails == nil {
+		return ""
+	}
+	return f.MoreDetails.ID
+}
+
+// SeverityScore returns the severity score of the finding or -1 if it
+// is unknown
+func (f *Finding) SeverityScore() float32 {
+	if f.MoreDetails == nil || f.MoreDetails.Severity == nil {
+		return -1
+	}
+	return f.MoreDetails.Severity.Score
+}
+
+// SortFindings sorts the findings by the given criterion. Findings
+// which are equal with regard to the criterion are sorted by date,
+// starting with the newest.
+func SortFindings(findings []*Finding, by string) error {
+	newerFirst := func(i, j int) bool {
+		return findings[i].CreatedAt.After(findings[j].CreatedAt)
+	}
+
+	var less func(i, j int) bool
+	switch by {
+	case "", SortByDate:
+		less = newerFirst
+	case SortBySeverity:
+		// Most severe first
+		less = func(i, j int) bool {
+			si, sj := findings[i].SeverityScore(), findings[j].SeverityScore()
+			if si != sj {
+				return si > sj
+			}
+			return newerFirst(i, j)
+		}
+	case SortByFuzzTest:
+		less = func(i, j int) bool {
+			if findings[i].FuzzTest != findings[j].FuzzTest {
+				return findings[i].FuzzTest < findings[j].FuzzTest
+			}
+			return newerFirst(i, j)
+		}
+	default:
+		return errors.Errorf("Invalid sort criterion %q, valid criteria are: %s", by, strings.Join(ValidSortBy, ", "))
+	}
+
+	sort.SliceStable(findings, less)
+	return nil
+}
+
+// Group is a group of findings which have the same value of the
+// property by which they are grouped
+type Group struct {
+	Key      string     `json:"key"`
+	Findings []*Finding `json:"findings"`
+}
+
+// GroupFindings groups the findings by the given property. The order of
+// the groups is determined by the first finding of each group, the
+// order of the findings within the groups is preserved.
+func GroupFindings(findings []*Finding, by string) ([]*Group, error) {
+	var keyFunc func(f *Finding) string
+	switch by {
+	case GroupByFuzzTest:
+		keyFunc = func(f *Finding) string { return f.FuzzTest }
+	case GroupByErrorID:
+		keyFunc = (*Finding).ErrorID
+	case GroupByLocation:
+		keyFunc = func(f *Finding) string {
+			if len(f.StackTrace) == 0 {
+				return ""
+			}
+			return f.StackTrace[0].Location()
+		}
+	default:
+		return nil, errors.Errorf("Invalid group criterion %q, valid criteria are: %s", by, strings.Join(ValidGroupBy, ", "))
+	}
+
+	groups := []*Group{}
+	groupsByKey := make(map[string]*Group)
+	for _, f := range findings {
+		key := keyFunc(f)
+		if key == "" {
+			key = "unknown"
+		}
+		group, ok := groupsByKey[key]
+		if !ok {
+			group = &Group{Key: key, Findings: []*Finding{}}
+			groupsByKey[key] = group
+			groups = append(groups, group)
+		}
+		group.Findings = append(group.Findings, f)
+	}
+	return groups, nil
+}
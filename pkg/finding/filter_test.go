@@ -0,0 +1,100 @@
+package finding
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSeverity(t *testing.T) {
+	score, err := ParseSeverity("High")
+	require.NoError(t, err)
+	require.Equal(t, float32(7.0), score)
+
+	score, err = ParseSeverity("5.5")
+	require.NoError(t, err)
+	require.Equal(t, float32(5.5), score)
+
+	_, err = ParseSeverity("11")
+	require.Error(t, err)
+	_, err = ParseSeverity("severe")
+	require.Error(t, err)
+}
+
+func TestParseSince(t *testing.T) {
+	now := time.Date(2023, 6, 10, 12, 0, 0, 0, time.UTC)
+
+	since, err := ParseSince("7d", now)
+	require.NoError(t, err)
+	require.Equal(t, now.AddDate(0, 0, -7), since)
+
+	since, err = ParseSince("12h", now)
+	require.NoError(t, err)
+	require.Equal(t, now.Add(-12*time.Hour), since)
+
+	since, err = ParseSince("2023-06-01", now)
+	require.NoError(t, err)
+	require.Equal(t, time.Date(2023, 6, 1, 0, 0, 0, 0, time.Local), since)
+
+	_, err = ParseSince("last week", now)
+	require.Error(t, err)
+}
+
+func TestFilter_Matches(t *testing.T) {
+	f := &Finding{
+		Type:        ErrorTypeCrash,
+		FuzzTest:    "my_fuzz_test",
+		CreatedAt:   time.Date(2023, 6, 5, 0, 0, 0, 0, time.UTC),
+		MoreDetails: &ErrorDetails{ID: "heap_buffer_overflow", Severity: &Severity{Score: 8.0}},
+		Owners:      []string{"@org/team-x", "dev@example.com"},
+	}
+
+	require.True(t, (&Filter{}).Matches(f))
+	require.True(t, (&Filter{FuzzTest: "my_fuzz_test", Type: "CRASH", ErrorID: "heap_buffer_overflow"}).Matches(f))
+	require.False(t, (&Filter{FuzzTest: "other_fuzz_test"}).Matches(f))
+	require.True(t, (&Filter{MinSeverity: 7.0}).Matches(f))
+	require.False(t, (&Filter{MinSeverity: 9.0}).Matches(f))
+	require.False(t, (&Filter{Since: time.Date(2023, 6, 6, 0, 0, 0, 0, time.UTC)}).Matches(f))
+	require.True(t, (&Filter{Statuses: []Status{StatusOpen, StatusConfirmed}}).Matches(f))
+	require.False(t, (&Filter{Statuses: []Status{StatusFixed}}).Matches(f))
+	require.True(t, (&Filter{Owner: "@org/team-x"}).Matches(f))
+	require.False(t, (&Filter{Owner: "@org/team-y"}).Matches(f))
+
+	// Findings without a severity don't match a minimum severity
+	require.False(t, (&Filter{MinSeverity: 0.1}).Matches(&Finding{}))
+}
+
+func TestSortAndGroupFindings(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2023, 6, d, 0, 0, 0, 0, time.UTC) }
+	a := &Finding{Name: "a", FuzzTest: "test_2", CreatedAt: day(1), MoreDetails: &ErrorDetails{Severity: &Severity{Score: 5}}}
+	b := &Finding{Name: "b", FuzzTest: "test_1", CreatedAt: day(3)}
+	c := &Finding{Name: "c", FuzzTest: "test_2", CreatedAt: day(2), MoreDetails: &ErrorDetails{Severity: &Severity{Score: 9}}}
+
+	findings := []*Finding{a, b, c}
+	err := SortFindings(findings, SortByDate)
+	require.NoError(t, err)
+	require.Equal(t, []*Finding{b, c, a}, findings)
+
+	err = SortFindings(findings, SortBySeverity)
+	require.NoError(t, err)
+	require.Equal(t, []*Finding{c, a, b}, findings)
+
+	err = SortFindings(findings, SortByFuzzTest)
+	require.NoError(t, err)
+	require.Equal(t, []*Finding{b, c, a}, findings)
+
+	err = SortFindings(findings, "name")
+	require.Error(t, err)
+
+	groups, err := GroupFindings(findings, GroupByFuzzTest)
+	require.NoError(t, err)
+	require.Equal(t, []*Group{
+		{Key: "test_1", Findings: []*Finding{b}},
+		{Key: "test_2", Findings: []*Finding{c, a}},
+	}, groups)
+
+	groups, err = GroupFindings(findings, GroupByLocation)
+	require.NoError(t, err)
+	require.Equal(t, []*Group{{Key: "unknown", Findings: findings}}, groups)
+}
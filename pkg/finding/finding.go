@@ -40,12 +40,54 @@ type Finding struct {
 	CreatedAt  time.Time                `json:"created_at,omitempty"`
 	InputFile  string                   `json:"input_file,omitempty"`
 	StackTrace []*stacktrace.StackFrame `json:"stack_trace,omitempty"`
+	// The exceptions which caused the reported Java exception, with the
+	// root cause last
+	Causes []*stacktrace.ExceptionCause `json:"causes,omitempty"`
+	// The values which the fuzz test consumed from the crashing input
+	// via the FuzzedDataProvider, as determined via
+	// `cifuzz finding show <name> --decode`
+	DataProviderCalls []string `json:"data_provider_calls,omitempty"`
 
 	seedPath string
 
 	// We also store the name of the fuzz test that found this finding so that
 	// we can show it in the finding overview.
 	FuzzTest string `json:"fuzz_test,omitempty"`
+
+	// The names of findings which were merged into this finding because
+	// they were duplicates of it (see `cifuzz finding dedupe`).
+	Duplicates []string `json:"duplicates,omitempty"`
+
+	// Triage information, managed via `cifuzz finding set-status`.
+	// Findings saved by older versions of cifuzz don't have a status
+	// and are treated as open (see GetStatus).
+	Status          Status     `json:"status,omitempty"`
+	StatusUpdatedAt *time.Time `json:"status_updated_at,omitempty"`
+	Assignee        string     `json:"assignee,omitempty"`
+	Notes           []*Note    `json:"notes,omitempty"`
+
+	// The suggested owners of the finding, determined via the
+	// CODEOWNERS file or git blame (see SuggestOwners)
+	Owners []string `json:"owners,omitempty"`
+
+	// The result of replaying the finding after it was found, to detect
+	// flaky findings
+	Reproducibility *Reproducibility `json:"reproducibility,omitempty"`
+
+	// The Git revision of the project in which the finding was found
+	GitRevision *GitRevision `json:"git_revision,omitempty"`
+
+	// The rule of the suppressions file which matched this finding, if
+	// any. Suppressed findings are saved, but not reported.
+	Suppression *SuppressionRule `json:"suppression,omitempty"`
+}
+
+type GitRevision struct {
+	Commit string `json:"commit,omitempty"`
+	Branch string `json:"branch,omitempty"`
+	// The commit which introduced the finding, as determined via
+	// `cifuzz finding bisect`
+	FirstBadCommit string `json:"first_bad_commit,omitempty"`
 }
 
 type ErrorType string
@@ -134,6 +176,94 @@ func (f *Finding) Save(projectDir string) error {
 	return nil
 }
 
+// CopyExistingInfo copies the information which was added to a finding
+// after it was found from the previously saved version of the finding,
+// i.e. the triage information (see CopyTriageInfo), the merged
+// duplicates, the first bad commit, the decoded FuzzedDataProvider
+// calls and the reproducibility. This is used to not lose that
+// information when a finding is found again and therefore saved again.
+func (f *Finding) CopyExistingInfo(existing *Finding) {
+	f.CopyTriageInfo(existing)
+
+	f.Duplicates = existing.Duplicates
+	if f.Reproducibility == nil {
+		f.Reproducibility = existing.Reproducibility
+	}
+	if len(f.DataProviderCalls) == 0 && len(existing.DataProviderCalls) > 0 {
+		f.DataProviderCalls = existing.DataProviderCalls
+		f.HumanReadableInput = existing.HumanReadableInput
+	}
+	if f.HumanReadableInput == "" {
+		f.HumanReadableInput = existing.HumanReadableInput
+	}
+	if existing.GitRevision != nil && existing.GitRevision.FirstBadCommit != "" {
+		if f.GitRevision == nil {
+			f.GitRevision = existing.GitRevision
+		} else if f.GitRevision.FirstBadCommit == "" {
+			f.GitRevision.FirstBadCommit = existing.GitRevision.FirstBadCommit
+		}
+	}
+}
+
+// Delete removes the directory of this finding, including the JSON file
+// and the crashing input.
+func (f *Finding) Delete(projectDir string) error {
+	findingDir := filepath.Join(projectDir, nameFindingsDir, f.Name)
+	return errors.WithStack(os.RemoveAll(findingDir))
+}
+
+// ReadInput returns the crashing input of the finding. The input is
+// read from the copy in the finding directory, if it exists, and taken
+// from the JSON file otherwise.
+func (f *Finding) ReadInput(projectDir string) ([]byte, error) {
+	path := filepath.Join(projectDir, nameFindingsDir, f.Name, nameCrashingInput)
+	input, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return f.InputData, nil
+	}
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return input, nil
+}
+
+// InputPath returns the path of the crashing input in the finding
+// directory. If the file doesn't exist, it's created from the input
+// data stored in the JSON file.
+func (f *Finding) InputPath(projectDir string) (string, error) {
+	path := filepath.Join(projectDir, nameFindingsDir, f.Name, nameCrashingInput)
+	exists, err := fileutil.Exists(path)
+	if err != nil {
+		return "", err
+	}
+	if exists {
+		return path, nil
+	}
+	err = os.MkdirAll(filepath.Dir(path), 0o755)
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+	err = os.WriteFile(path, f.InputData, 0o644)
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+	return path, nil
+}
+
+// FuzzTestMethod returns the name of the @FuzzTest method of a Java
+// finding, i.e. the function of the bottommost stack frame in the fuzz
+// test class, or an empty string if the stack trace doesn't contain
+// such a frame.
+func (f *Finding) FuzzTestMethod() string {
+	for i := len(f.StackTrace) - 1; i >= 0; i-- {
+		frame := f.StackTrace[i]
+		if frame.SourceFile == f.FuzzTest {
+			return frame.Function
+		}
+	}
+	return ""
+}
+
 func (f *Finding) saveJSON(jsonPath string) error {
 	bytes, err := json.MarshalIndent(f, "", "  ")
 	if err != nil {
@@ -267,14 +397,7 @@ func (f *Finding) ShortDescriptionColumns() []string {
 	// add location (file, function, line)
 	if len(f.StackTrace) > 0 {
 		f := f.StackTrace[0]
-		var location string
-		// in some cases ASan/Libfuzzer do not include the column in the stack trace
-		if f.Column != 0 {
-			location = fmt.Sprintf("%s:%d:%d", f.SourceFile, f.Line, f.Column)
-		} else {
-			location = fmt.Sprintf("%s:%d", f.SourceFile, f.Line)
-		}
-		columns = append(columns, fmt.Sprintf("in %s (%s)", f.Function, location))
+		columns = append(columns, fmt.Sprintf("in %s (%s)", f.Function, f.Location()))
 	}
 	return columns
 }
@@ -311,8 +434,32 @@ func ListFindings(projectDir string, errorDetails *[]ErrorDetails) ([]*Finding,
 // LoadFinding parses the JSON file of the specified finding and returns
 // the result.
 // If the specified finding does not exist, a NotExistError is returned.
-// If the user is logged in, the error details are added to the finding.
+// If errorDetails is not nil, the error details are added to the
+// finding (see EnhanceWithErrorDetails).
+// If the stack trace of the finding was not symbolized when it was
+// reported, it's symbolized in memory, the finding is not saved.
 func LoadFinding(projectDir, findingName string, errorDetails *[]ErrorDetails) (*Finding, error) {
+	f, err := LoadStoredFinding(projectDir, findingName)
+	if err != nil {
+		return nil, err
+	}
+
+	// Findings which were reported without a working symbolizer have no
+	// stack trace, so we try to symbolize them now
+	if len(f.StackTrace) == 0 && f.NeedsSymbolization() {
+		f.resymbolize(projectDir)
+	}
+
+	f.EnhanceWithErrorDetails(errorDetails)
+
+	return f, nil
+}
+
+// LoadStoredFinding parses the JSON file of the specified finding and
+// returns the result as it's stored, i.e. without symbolizing its stack
+// trace like LoadFinding does.
+// If the specified finding does not exist, a NotExistError is returned.
+func LoadStoredFinding(projectDir, findingName string) (*Finding, error) {
 	findingDir := filepath.Join(projectDir, nameFindingsDir, findingName)
 	jsonPath := filepath.Join(findingDir, nameJSONFile)
 	bytes, err := os.ReadFile(jsonPath)
@@ -327,14 +474,29 @@ func LoadFinding(projectDir, findingName string, errorDetails *[]ErrorDetails) (
 	if err != nil {
 		return nil, errors.WithStack(err)
 	}
-
-	f.EnhanceWithErrorDetails(errorDetails)
-
 	return &f, nil
 }
 
-// EnhanceWithErrorDetails adds more details to the finding by parsing the
-// error details file.
+// resymbolize symbolizes the stack trace of the finding. Errors are not
+// returned, because symbolizing is not required to use the finding.
+func (f *Finding) resymbolize(projectDir string) {
+	numSymbolized, err := f.Symbolize(&SymbolizeOptions{ProjectDir: projectDir})
+	if err != nil {
+		log.Debugf("Failed to symbolize finding %s: %v", f.Name, err)
+		return
+	}
+	if numSymbolized > 0 {
+		log.Debugf("Symbolized %d stack frames of finding %s", numSymbolized, f.Name)
+	}
+}
+
+// EnhanceWithErrorDetails adds more details to the finding. The error
+// details fetched from the server take precedence, if there are no
+// matching details, the error details shipped with cifuzz are used (see
+// EnhanceWithLocalErrorDetails).
+// If errorDetails is nil, the finding is not changed. This should be
+// used for findings which are saved again, so that the error details
+// are not stored in the finding.
 func (f *Finding) EnhanceWithErrorDetails(errorDetails *[]ErrorDetails) {
 	if errorDetails == nil {
 		return
@@ -350,5 +512,20 @@ func (f *Finding) EnhanceWithErrorDetails(errorDetails *[]ErrorDetails) {
 		}
 	}
 
+	f.EnhanceWithLocalErrorDetails()
+}
+
+// EnhanceWithLocalErrorDetails uses the error details shipped with
+// cifuzz to fill in the details which the finding doesn't have yet,
+// like the severity. This doesn't require the error details of the
+// server and therefore works offline.
+func (f *Finding) EnhanceWithLocalErrorDetails() {
+	if f.MoreDetails != nil {
+		if d := LocalErrorDetailsByID(f.MoreDetails.ID); d != nil {
+			f.MoreDetails.fillFrom(d)
+			return
+		}
+	}
+
 	log.Debugf("No error details found for finding %s", f.Name)
 }
@@ -107,3 +107,56 @@ func testFinding() *Finding {
 		},
 	}
 }
+
+func TestEnhanceWithErrorDetails(t *testing.T) {
+	// Without error details from the server, the error details shipped
+	// with cifuzz are used
+	f := &Finding{Name: "test", MoreDetails: &ErrorDetails{ID: "heap_buffer_overflow"}}
+	f.EnhanceWithErrorDetails(&[]ErrorDetails{})
+	require.Equal(t, "Heap Buffer Overflow", f.MoreDetails.Name)
+	require.NotNil(t, f.MoreDetails.Severity)
+	require.Equal(t, SeverityLevelCritical, f.MoreDetails.Severity.Level)
+	require.Equal(t, int64(122), f.MoreDetails.CweDetails.ID)
+
+	// The error details from the server take precedence
+	f = &Finding{Name: "test", MoreDetails: &ErrorDetails{ID: "heap_buffer_overflow"}}
+	f.EnhanceWithErrorDetails(&[]ErrorDetails{{
+		ID:       "heap_buffer_overflow",
+		Name:     "Heap Buffer Overflow (server)",
+		Severity: &Severity{Level: SeverityLevelHigh, Score: 8},
+	}})
+	require.Equal(t, "Heap Buffer Overflow (server)", f.MoreDetails.Name)
+	require.Equal(t, float32(8), f.MoreDetails.Severity.Score)
+
+	// If no error details are passed, the finding is not changed
+	f = &Finding{Name: "test", MoreDetails: &ErrorDetails{ID: "heap_buffer_overflow"}}
+	f.EnhanceWithErrorDetails(nil)
+	require.Equal(t, &ErrorDetails{ID: "heap_buffer_overflow"}, f.MoreDetails)
+}
+
+func TestEnhanceWithLocalErrorDetails(t *testing.T) {
+	f := &Finding{Name: "test", MoreDetails: &ErrorDetails{ID: "heap_buffer_overflow", Name: "Custom name"}}
+	f.EnhanceWithLocalErrorDetails()
+	// Only the details which the finding doesn't have yet are filled in
+	require.Equal(t, "Custom name", f.MoreDetails.Name)
+	require.NotNil(t, f.MoreDetails.Severity)
+	require.Equal(t, SeverityLevelCritical, f.MoreDetails.Severity.Level)
+
+	// Findings without a known error ID are not changed
+	f = &Finding{Name: "test"}
+	f.EnhanceWithLocalErrorDetails()
+	require.Nil(t, f.MoreDetails)
+}
+
+func TestLocalErrorDetails(t *testing.T) {
+	for _, d := range LocalErrorDetails() {
+		require.NotEmpty(t, d.ID)
+		require.NotEmpty(t, d.Name, d.ID)
+		require.NotEmpty(t, d.Description, d.ID)
+		require.NotEmpty(t, d.Mitigation, d.ID)
+		require.NotNil(t, d.Severity, d.ID)
+		require.Contains(t, []SeverityLevel{
+			SeverityLevelCritical, SeverityLevelHigh, SeverityLevelMedium, SeverityLevelLow,
+		}, d.Severity.Level, d.ID)
+	}
+}
@@ -0,0 +1,13 @@
+package finding
+
+// HarnessSourceFile returns the source file of the fuzz test harness
+// function in the stack trace of the finding, or an empty string if the
+// stack trace doesn't contain the harness function
+func (f *Finding) HarnessSourceFile() string {
+	for _, frame := range f.StackTrace {
+		if f.isHarnessFrame(frame) && frame.SourceFile != "" {
+			return frame.SourceFile
+		}
+	}
+	return ""
+}
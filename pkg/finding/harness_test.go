@@ -0,0 +1,32 @@
+package finding
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"code-intelligence.com/cifuzz/pkg/parser/libfuzzer/stacktrace"
+)
+
+func TestHarnessSourceFile(t *testing.T) {
+	f := &Finding{
+		FuzzTest: "stream_fuzzer",
+		StackTrace: []*stacktrace.StackFrame{
+			{Function: "parse", SourceFile: "src/parser.cpp", Line: 10},
+			{Function: "feed", SourceFile: "fuzz/stream_fuzzer.cpp", Line: 12},
+			{Function: "LLVMFuzzerTestOneInputNoReturn", SourceFile: "fuzz/stream_fuzzer.cpp", Line: 20},
+		},
+	}
+	assert.Equal(t, "fuzz/stream_fuzzer.cpp", f.HarnessSourceFile())
+
+	f = &Finding{
+		FuzzTest: "com.example.ParserFuzzTest::fuzz",
+		StackTrace: []*stacktrace.StackFrame{
+			{Function: "com.example.Parser.parse", SourceFile: "com.example.Parser", Line: 3},
+			{Function: "com.example.ParserFuzzTest.fuzz", SourceFile: "com.example.ParserFuzzTest", Line: 8},
+		},
+	}
+	assert.Equal(t, "com.example.ParserFuzzTest", f.HarnessSourceFile())
+
+	assert.Empty(t, (&Finding{}).HarnessSourceFile())
+}
@@ -0,0 +1,185 @@
+package finding
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"code-intelligence.com/cifuzz/pkg/log"
+	"code-intelligence.com/cifuzz/pkg/vcs"
+)
+
+// The locations of the CODEOWNERS file which are supported by GitHub
+// and GitLab, in the order in which they are searched
+var codeOwnersPaths = []string{
+	"CODEOWNERS",
+	filepath.Join(".github", "CODEOWNERS"),
+	filepath.Join(".gitlab", "CODEOWNERS"),
+	filepath.Join("docs", "CODEOWNERS"),
+}
+
+// CodeOwners contains the rules of a CODEOWNERS file
+type CodeOwners struct {
+	rules []*codeOwnersRule
+}
+
+type codeOwnersRule struct {
+	pattern *regexp.Regexp
+	owners  []string
+}
+
+// LoadCodeOwners parses the CODEOWNERS file of the project. It returns
+// nil if the project doesn't have a CODEOWNERS file.
+func LoadCodeOwners(projectDir string) (*CodeOwners, error) {
+	for _, path := range codeOwnersPaths {
+		content, err := os.ReadFile(filepath.Join(projectDir, path))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		return ParseCodeOwners(content)
+	}
+	return nil, nil
+}
+
+// ParseCodeOwners parses the content of a CODEOWNERS file. Each line
+// consists of a gitignore-style pattern followed by the owners of the
+// matching files.
+func ParseCodeOwners(content []byte) (*CodeOwners, error) {
+	c := &CodeOwners{}
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if i := strings.Index(line, "#"); i != -1 {
+			line = line[:i]
+		}
+		fields := strings.Fields(line)
+		// Skip empty lines and the section headers of GitLab, e.g.
+		// "[Documentation]" or "^[Optional Section]"
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "[") || strings.HasPrefix(fields[0], "^[") {
+			continue
+		}
+		pattern, err := codeOwnersPatternRegex(fields[0])
+		if err != nil {
+			return nil, err
+		}
+		c.rules = append(c.rules, &codeOwnersRule{pattern: pattern, owners: fields[1:]})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return c, nil
+}
+
+// Owners returns the owners of the file with the given path, which is
+// relative to the project directory. Like GitHub and GitLab, the last
+// matching rule takes precedence.
+func (c *CodeOwners) Owners(path string) []string {
+	path = filepath.ToSlash(path)
+	for i := len(c.rules) - 1; i >= 0; i-- {
+		if c.rules[i].pattern.MatchString(path) {
+			return c.rules[i].owners
+		}
+	}
+	return nil
+}
+
+// codeOwnersPatternRegex converts a gitignore-style pattern of a
+// CODEOWNERS file to a regular expression which matches the paths of
+// the files it applies to
+func codeOwnersPatternRegex(pattern string) (*regexp.Regexp, error) {
+	// Patterns which contain a slash (other than a trailing one) are
+	// relative to the root directory, others match at any depth
+	anchored := strings.Contains(strings.TrimSuffix(pattern, "/"), "/")
+	pattern = strings.TrimPrefix(pattern, "/")
+	dirOnly := strings.HasSuffix(pattern, "/")
+	pattern = strings.TrimSuffix(pattern, "/")
+
+	var expr strings.Builder
+	if anchored {
+		expr.WriteString("^")
+	} else {
+		expr.WriteString("^(?:.*/)?")
+	}
+	for i := 0; i < len(pattern); i++ {
+		switch {
+		case strings.HasPrefix(pattern[i:], "**/"):
+			expr.WriteString("(?:.*/)?")
+			i += 2
+		case strings.HasPrefix(pattern[i:], "**"):
+			expr.WriteString(".*")
+			i++
+		case pattern[i] == '*':
+			expr.WriteString("[^/]*")
+		case pattern[i] == '?':
+			expr.WriteString("[^/]")
+		default:
+			expr.WriteString(regexp.QuoteMeta(pattern[i : i+1]))
+		}
+	}
+	if dirOnly {
+		// Only the files below the directory are matched
+		expr.WriteString("/.*$")
+	} else {
+		// A pattern matching a directory also matches the files below it
+		expr.WriteString("(?:/.*)?$")
+	}
+
+	re, err := regexp.Compile(expr.String())
+	if err != nil {
+		return nil, errors.Wrapf(err, "Invalid CODEOWNERS pattern %q", pattern)
+	}
+	return re, nil
+}
+
+// SuggestOwners determines the owners of the finding from the top
+// in-project stack frame. The owners of its source file are looked up
+// in the CODEOWNERS file (which can be nil). If no rule matches, the
+// author of the crashing line as reported by git blame is used.
+func (f *Finding) SuggestOwners(projectDir string, codeOwners *CodeOwners) []string {
+	for _, frame := range f.StackTrace {
+		if frame.Line == 0 || IsRuntimeFrame(frame) {
+			continue
+		}
+		path := resolveSourceFile(projectDir, frame)
+		if path == "" {
+			continue
+		}
+		relPath, err := filepath.Rel(projectDir, path)
+		if err != nil || strings.HasPrefix(relPath, "..") {
+			continue
+		}
+
+		if codeOwners != nil {
+			if owners := codeOwners.Owners(relPath); len(owners) > 0 {
+				return owners
+			}
+		}
+		author, err := vcs.GitBlameAuthorEmail(projectDir, relPath, frame.Line)
+		if err != nil {
+			log.Debugf("Failed to determine the author of %s:%d: %v", relPath, frame.Line, err)
+			return nil
+		}
+		return []string{author}
+	}
+	return nil
+}
+
+// HasOwner returns true if the given owner is one of the owners of the
+// finding. Owners are compared case-insensitively, like GitHub and
+// GitLab do.
+func (f *Finding) HasOwner(owner string) bool {
+	for _, o := range f.Owners {
+		if strings.EqualFold(o, owner) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,82 @@
+package finding
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"code-intelligence.com/cifuzz/internal/testutil"
+	"code-intelligence.com/cifuzz/pkg/parser/libfuzzer/stacktrace"
+)
+
+func TestCodeOwners(t *testing.T) {
+	codeOwners, err := ParseCodeOwners([]byte(`# Default owners
+*                   @org/everyone
+
+[Parser]
+*.cpp               @org/cpp-team
+/src/parser/        @org/parser-team parser@example.com
+docs/**/*.md        @org/docs-team
+/src/parser/gen.cpp
+lib                 @org/lib-team # inline comment
+`))
+	require.NoError(t, err)
+
+	testCases := map[string][]string{
+		"README.md":                   {"@org/everyone"},
+		"main.cpp":                    {"@org/cpp-team"},
+		"src/main.cpp":                {"@org/cpp-team"},
+		"src/parser/parser.cpp":       {"@org/parser-team", "parser@example.com"},
+		"src/parser/sub/lexer.h":      {"@org/parser-team", "parser@example.com"},
+		"other/src/parser/parser.cpp": {"@org/cpp-team"},
+		"docs/a/b/guide.md":           {"@org/docs-team"},
+		"docs/guide.md":               {"@org/docs-team"},
+		"src/parser/gen.cpp":          {},
+		"lib/util.c":                  {"@org/lib-team"},
+		"src/lib/util.c":              {"@org/lib-team"},
+	}
+	for path, expected := range testCases {
+		require.Equal(t, expected, codeOwners.Owners(path), path)
+	}
+}
+
+func TestSuggestOwners(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not found")
+	}
+	projectDir := t.TempDir()
+	testutil.InitGitRepo(t, projectDir)
+
+	err := os.MkdirAll(filepath.Join(projectDir, "src"), 0o755)
+	require.NoError(t, err)
+	err = os.WriteFile(filepath.Join(projectDir, "src", "parser.cpp"), []byte("void parse() {\n  crash();\n}\n"), 0o644)
+	require.NoError(t, err)
+	testutil.RunGit(t, projectDir, "add", "-A")
+	testutil.RunGit(t, projectDir, "commit", "-m", "Add parser")
+
+	f := &Finding{
+		StackTrace: []*stacktrace.StackFrame{
+			{Function: "__asan_memcpy", SourceFile: "asan_interceptors.cpp", Line: 22},
+			{Function: "parse", SourceFile: "src/parser.cpp", Line: 2},
+		},
+	}
+
+	// Without a CODEOWNERS file, the author of the line is the owner
+	require.Equal(t, []string{"you@example.com"}, f.SuggestOwners(projectDir, nil))
+
+	codeOwners, err := ParseCodeOwners([]byte("/src/ @org/parser-team\n"))
+	require.NoError(t, err)
+	require.Equal(t, []string{"@org/parser-team"}, f.SuggestOwners(projectDir, codeOwners))
+
+	// Lines which were not committed yet don't have an owner
+	err = os.WriteFile(filepath.Join(projectDir, "src", "parser.cpp"), []byte("void parse() {\n  crash(1);\n}\n"), 0o644)
+	require.NoError(t, err)
+	require.Empty(t, f.SuggestOwners(projectDir, nil))
+
+	f.Owners = []string{"@org/Parser-Team"}
+	require.True(t, f.HasOwner("@org/parser-team"))
+	require.False(t, f.HasOwner("@org/other-team"))
+}
@@ -0,0 +1,264 @@
+package finding
+
+import (
+	"os"
+	"os/user"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"code-intelligence.com/cifuzz/pkg/parser/libfuzzer/stacktrace"
+)
+
+const (
+	// RedactorHomePaths redacts the home directory of users in paths
+	RedactorHomePaths = "home-paths"
+	// RedactorEmails redacts email addresses
+	RedactorEmails = "emails"
+	// RedactorTokens redacts access tokens in common formats (GitHub,
+	// GitLab, Slack, AWS, JWT, bearer tokens) and the values of
+	// variables whose names indicate that they contain a secret
+	RedactorTokens = "tokens"
+	// RedactorUser redacts the name of the current user
+	RedactorUser = "user"
+	// RedactorHostname redacts the hostname of the current machine
+	RedactorHostname = "hostname"
+
+	defaultRedactReplacement = "<redacted>"
+)
+
+var builtinRedactors = []string{RedactorHomePaths, RedactorEmails, RedactorTokens, RedactorUser, RedactorHostname}
+
+var (
+	// Home directories are only matched at the start of a path, so
+	// that e.g. "/tmp/root" is not redacted. The preceding character is
+	// kept.
+	homePathRegexes = []*regexp.Regexp{
+		regexp.MustCompile(`(^|[^A-Za-z0-9_.~/-])(?:/home/[^/\s:'"]+|/Users/[^/\s:'"]+|/root\b)`),
+		regexp.MustCompile(`(^|[^A-Za-z0-9_.~\\-])[A-Za-z]:\\Users\\[^\\\s:'"]+`),
+	}
+	emailRegex   = regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9-]+(?:\.[A-Za-z0-9-]+)*\.[A-Za-z]{2,}`)
+	tokenRegexes = []*regexp.Regexp{
+		regexp.MustCompile(`\bgh[pousr]_[A-Za-z0-9]{36,}\b`),
+		regexp.MustCompile(`\bgithub_pat_[A-Za-z0-9_]{22,}\b`),
+		regexp.MustCompile(`\bglpat-[A-Za-z0-9_-]{20,}`),
+		regexp.MustCompile(`\bxox[abprs]-[A-Za-z0-9-]{10,}`),
+		regexp.MustCompile(`\b(?:AKIA|ASIA)[0-9A-Z]{16}\b`),
+		regexp.MustCompile(`\beyJ[A-Za-z0-9_-]+\.eyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+`),
+	}
+	// Matches the prefix which is kept when redacting bearer tokens and
+	// the values of secret variables, e.g. "Authorization: Bearer " or
+	// "API_TOKEN="
+	tokenAssignmentRegexes = []*regexp.Regexp{
+		regexp.MustCompile(`(?i)(\bbearer\s+)[A-Za-z0-9._~+/-]{8,}=*`),
+		regexp.MustCompile(`(?i)(\b[a-z0-9_.-]*(?:api[_-]?key|token|secret|password|passwd)[a-z0-9_-]*\s*[=:]\s*)[^\s'",;]+`),
+	}
+)
+
+// RedactOptions configures which data is redacted from findings before
+// they are uploaded or exported. It can be set via the "redact" setting
+// in cifuzz.yaml.
+type RedactOptions struct {
+	// The built-in redactors which should not be applied
+	DisableBuiltins []string `mapstructure:"disable-builtins"`
+	// Additional redaction rules, which are applied before the
+	// built-in ones
+	Rules []*RedactRule `mapstructure:"rules"`
+
+	redactors []*redactor
+}
+
+// RedactRule replaces all matches of a regular expression. The
+// replacement can refer to submatches of the regex, e.g. "${1}".
+type RedactRule struct {
+	Name        string `mapstructure:"name"`
+	Regex       string `mapstructure:"regex"`
+	Replacement string `mapstructure:"replacement"`
+}
+
+// Redaction describes a match which was replaced by a redactor
+type Redaction struct {
+	Field       string
+	Redactor    string
+	Match       string
+	Replacement string
+}
+
+// DisplayMatch returns the match in a form which can be shown to the
+// user. Matches of the tokens redactor are truncated, so that the
+// secret isn't revealed.
+func (r *Redaction) DisplayMatch() string {
+	if r.Redactor != RedactorTokens {
+		return r.Match
+	}
+	// Keep the prefix which is not redacted, e.g. "API_TOKEN=", and at
+	// most a quarter of the secret, but no more than 4 characters
+	prefix := strings.TrimSuffix(r.Replacement, "<token>")
+	if !strings.HasPrefix(r.Match, prefix) {
+		prefix = ""
+	}
+	secret := strings.TrimPrefix(r.Match, prefix)
+	n := len(secret) / 4
+	if n > 4 {
+		n = 4
+	}
+	return prefix + secret[:n] + "****"
+}
+
+type redactor struct {
+	name        string
+	regex       *regexp.Regexp
+	replacement string
+}
+
+// Validate checks the options and compiles the redaction rules. It
+// must be called before Redact.
+func (o *RedactOptions) Validate() error {
+	o.redactors = nil
+
+	for i, rule := range o.Rules {
+		if rule.Regex == "" {
+			return errors.Errorf("Redaction rule #%d has no regex", i+1)
+		}
+		regex, err := regexp.Compile(rule.Regex)
+		if err != nil {
+			return errors.Wrapf(err, "Invalid regex %q of redaction rule #%d", rule.Regex, i+1)
+		}
+		name := rule.Name
+		if name == "" {
+			name = rule.Regex
+		}
+		replacement := rule.Replacement
+		if replacement == "" {
+			replacement = defaultRedactReplacement
+		}
+		o.redactors = append(o.redactors, &redactor{name: name, regex: regex, replacement: replacement})
+	}
+
+	disabled := make(map[string]bool)
+	for _, name := range o.DisableBuiltins {
+		if !isBuiltinRedactor(name) {
+			return errors.Errorf("Invalid built-in redactor %q, valid redactors are: %s",
+				name, strings.Join(builtinRedactors, ", "))
+		}
+		disabled[name] = true
+	}
+	// Tokens and emails are redacted before home paths and user names,
+	// which they might contain
+	if !disabled[RedactorTokens] {
+		for _, regex := range tokenRegexes {
+			o.redactors = append(o.redactors, &redactor{name: RedactorTokens, regex: regex, replacement: "<token>"})
+		}
+		for _, regex := range tokenAssignmentRegexes {
+			o.redactors = append(o.redactors, &redactor{name: RedactorTokens, regex: regex, replacement: "${1}<token>"})
+		}
+	}
+	if !disabled[RedactorEmails] {
+		o.redactors = append(o.redactors, &redactor{name: RedactorEmails, regex: emailRegex, replacement: "<email>"})
+	}
+	if !disabled[RedactorHomePaths] {
+		for _, regex := range homePathRegexes {
+			o.redactors = append(o.redactors, &redactor{name: RedactorHomePaths, regex: regex, replacement: "${1}<home>"})
+		}
+	}
+	if !disabled[RedactorUser] {
+		if r := wordRedactor(RedactorUser, currentUsername(), "<user>"); r != nil {
+			o.redactors = append(o.redactors, r)
+		}
+	}
+	if !disabled[RedactorHostname] {
+		hostname, err := os.Hostname()
+		if err == nil && hostname != "localhost" {
+			if r := wordRedactor(RedactorHostname, hostname, "<host>"); r != nil {
+				o.redactors = append(o.redactors, r)
+			}
+		}
+	}
+
+	return nil
+}
+
+func isBuiltinRedactor(name string) bool {
+	for _, r := range builtinRedactors {
+		if r == name {
+			return true
+		}
+	}
+	return false
+}
+
+func currentUsername() string {
+	u, err := user.Current()
+	if err != nil {
+		return ""
+	}
+	return u.Username
+}
+
+// wordRedactor returns a redactor which replaces the word, or nil if
+// the word is too short to be redacted without replacing lots of
+// unrelated text
+func wordRedactor(name, word, replacement string) *redactor {
+	if len(word) < 3 {
+		return nil
+	}
+	regex := regexp.MustCompile(`\b` + regexp.QuoteMeta(word) + `\b`)
+	return &redactor{name: name, regex: regex, replacement: replacement}
+}
+
+// Redact returns a copy of the finding in which the matches of the
+// redactors are replaced in the logs, the details, the stack traces and
+// the owners (which can be email addresses determined via git blame).
+// It also returns the redactions which were applied. The finding itself
+// is not modified.
+func (o *RedactOptions) Redact(f *Finding) (*Finding, []*Redaction) {
+	var redactions []*Redaction
+	redact := func(field, s string) string {
+		for _, r := range o.redactors {
+			s = r.regex.ReplaceAllStringFunc(s, func(match string) string {
+				replacement := r.regex.ReplaceAllString(match, r.replacement)
+				redactions = append(redactions, &Redaction{
+					Field:       field,
+					Redactor:    r.name,
+					Match:       match,
+					Replacement: replacement,
+				})
+				return replacement
+			})
+		}
+		return s
+	}
+	redactStackTrace := func(field string, frames []*stacktrace.StackFrame) []*stacktrace.StackFrame {
+		var redacted []*stacktrace.StackFrame
+		for _, frame := range frames {
+			frameCopy := *frame
+			frameCopy.SourceFile = redact(field, frame.SourceFile)
+			frameCopy.Function = redact(field, frame.Function)
+			redacted = append(redacted, &frameCopy)
+		}
+		return redacted
+	}
+
+	redacted := *f
+	redacted.Logs = nil
+	for _, line := range f.Logs {
+		redacted.Logs = append(redacted.Logs, redact("logs", line))
+	}
+	redacted.Details = redact("details", f.Details)
+	redacted.StackTrace = redactStackTrace("stack trace", f.StackTrace)
+	redacted.Owners = nil
+	for _, owner := range f.Owners {
+		redacted.Owners = append(redacted.Owners, redact("owners", owner))
+	}
+	redacted.Causes = nil
+	for _, cause := range f.Causes {
+		redacted.Causes = append(redacted.Causes, &stacktrace.ExceptionCause{
+			Exception:  cause.Exception,
+			Message:    redact("causes", cause.Message),
+			StackTrace: redactStackTrace("causes", cause.StackTrace),
+		})
+	}
+
+	return &redacted, redactions
+}
@@ -0,0 +1,102 @@
+package finding
+
+import (
+	"fmt"
+	"time"
+)
+
+// Reproducibility is the result of replaying the crashing input of a
+// finding multiple times after it was found
+type Reproducibility struct {
+	// The fraction of attempts which reproduced the finding (0-1). Only
+	// attempts which ran the fuzz test are considered.
+	Rate     float64         `json:"rate"`
+	Attempts []*ReproAttempt `json:"attempts"`
+}
+
+// ReproAttempt is a single execution of the fuzz test with the crashing
+// input of a finding
+type ReproAttempt struct {
+	// True if the attempt reported a finding with the same error ID
+	Reproduced bool `json:"reproduced"`
+	// The error ID of the finding reported by the attempt, if any
+	ErrorID string `json:"error_id,omitempty"`
+	// The wall time of the attempt
+	Duration time.Duration `json:"duration"`
+	// The error which prevented the attempt from running the fuzz test
+	Error string `json:"error,omitempty"`
+}
+
+// NewReproducibility computes the reproduction rate of the attempts.
+// Attempts which failed with an error are not counted.
+func NewReproducibility(attempts []*ReproAttempt) *Reproducibility {
+	r := &Reproducibility{Attempts: attempts}
+	if completed := r.NumCompleted(); completed > 0 {
+		r.Rate = float64(r.NumReproduced()) / float64(completed)
+	}
+	return r
+}
+
+// NumCompleted returns the number of attempts which ran the fuzz test,
+// i.e. which didn't fail with an error
+func (r *Reproducibility) NumCompleted() int {
+	n := 0
+	for _, a := range r.Attempts {
+		if a.Error == "" {
+			n++
+		}
+	}
+	return n
+}
+
+// NumReproduced returns the number of attempts which reproduced the
+// finding
+func (r *Reproducibility) NumReproduced() int {
+	n := 0
+	for _, a := range r.Attempts {
+		if a.Reproduced {
+			n++
+		}
+	}
+	return n
+}
+
+func (r *Reproducibility) String() string {
+	s := fmt.Sprintf("%d/%d attempts (%.0f%%)", r.NumReproduced(), r.NumCompleted(), r.Rate*100)
+	if failed := len(r.Attempts) - r.NumCompleted(); failed > 0 {
+		s += fmt.Sprintf(", %d failed to run", failed)
+	}
+	return s
+}
+
+// NewReproAttempt creates the attempt of replaying the finding f which
+// resulted in the given findings. The finding is reproduced if one of
+// them has the same error ID as f, or any error ID if the error ID of f
+// is unknown.
+func (f *Finding) NewReproAttempt(findings []*Finding, duration time.Duration) *ReproAttempt {
+	a := &ReproAttempt{Duration: duration}
+	for _, other := range findings {
+		errorID := other.ErrorID()
+		if a.ErrorID == "" {
+			a.ErrorID = errorID
+		}
+		if f.ErrorID() == "" || errorID == f.ErrorID() {
+			a.Reproduced = true
+			a.ErrorID = errorID
+			break
+		}
+	}
+	return a
+}
+
+// IsUnreliable returns true if the finding was replayed after it was
+// found and didn't reproduce in all attempts
+func (f *Finding) IsUnreliable() bool {
+	return f.Reproducibility != nil && f.Reproducibility.Rate < 1
+}
+
+// MeetsReproRate returns true if the reproduction rate of the finding
+// is at least minRate. Findings which were not replayed always meet it.
+func (f *Finding) MeetsReproRate(minRate float64) bool {
+	return f.Reproducibility == nil || f.Reproducibility.Rate >= minRate
+}
@@ -0,0 +1,50 @@
+package finding
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewReproAttempt(t *testing.T) {
+	f := &Finding{MoreDetails: &ErrorDetails{ID: "heap_buffer_overflow"}}
+
+	a := f.NewReproAttempt([]*Finding{{MoreDetails: &ErrorDetails{ID: "heap_buffer_overflow"}}}, time.Second)
+	assert.Equal(t, &ReproAttempt{Reproduced: true, ErrorID: "heap_buffer_overflow", Duration: time.Second}, a)
+
+	// A finding with a different error ID doesn't reproduce the finding
+	a = f.NewReproAttempt([]*Finding{{MoreDetails: &ErrorDetails{ID: "out_of_memory"}}}, time.Second)
+	assert.Equal(t, &ReproAttempt{Reproduced: false, ErrorID: "out_of_memory", Duration: time.Second}, a)
+
+	a = f.NewReproAttempt(nil, time.Second)
+	assert.False(t, a.Reproduced)
+	assert.Empty(t, a.ErrorID)
+
+	// If the error ID of the finding is unknown, any finding reproduces it
+	a = (&Finding{}).NewReproAttempt([]*Finding{{Details: "deadly signal"}}, time.Second)
+	assert.True(t, a.Reproduced)
+}
+
+func TestReproducibility(t *testing.T) {
+	f := &Finding{}
+	assert.False(t, f.IsUnreliable())
+	assert.True(t, f.MeetsReproRate(1))
+
+	f.Reproducibility = NewReproducibility([]*ReproAttempt{{Reproduced: true}, {Reproduced: false}, {Reproduced: true}, {Reproduced: true}})
+	require.Equal(t, 0.75, f.Reproducibility.Rate)
+	assert.Equal(t, "3/4 attempts (75%)", f.Reproducibility.String())
+	assert.True(t, f.IsUnreliable())
+	assert.True(t, f.MeetsReproRate(0.75))
+	assert.False(t, f.MeetsReproRate(0.8))
+
+	f.Reproducibility = NewReproducibility([]*ReproAttempt{{Reproduced: true}})
+	assert.False(t, f.IsUnreliable())
+
+	// Attempts which failed to run don't count as failed reproductions
+	f.Reproducibility = NewReproducibility([]*ReproAttempt{{Reproduced: true}, {Error: "sandbox error"}})
+	require.Equal(t, 1.0, f.Reproducibility.Rate)
+	assert.Equal(t, "1/1 attempts (100%), 1 failed to run", f.Reproducibility.String())
+	assert.False(t, f.IsUnreliable())
+}
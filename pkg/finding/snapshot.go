@@ -0,0 +1,238 @@
+package finding
+
+import (
+	"encoding/json"
+	"os"
+	"path"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"code-intelligence.com/cifuzz/pkg/vcs"
+)
+
+const snapshotVersion = 1
+
+// Snapshot records the findings of a project at some point in time, so
+// that they can be compared to the findings at a later point, e.g. to
+// determine which findings were introduced by a pull request.
+type Snapshot struct {
+	Version   int       `json:"version"`
+	CreatedAt time.Time `json:"created_at"`
+	// The options used to compute the dedup keys. The findings which
+	// are compared to the snapshot must use the same.
+	DedupFrames    int              `json:"dedup_frames"`
+	DedupRootCause bool             `json:"dedup_root_cause,omitempty"`
+	Findings       []*SnapshotEntry `json:"findings"`
+}
+
+// SnapshotEntry is the part of a finding which is recorded in a
+// snapshot
+type SnapshotEntry struct {
+	DedupKey    string    `json:"dedup_key"`
+	Name        string    `json:"name"`
+	FuzzTest    string    `json:"fuzz_test,omitempty"`
+	Description string    `json:"description"`
+	Severity    *Severity `json:"severity,omitempty"`
+	// The fraction of attempts which reproduced the finding, if it was
+	// replayed after it was found
+	ReproRate *float64 `json:"repro_rate,omitempty"`
+}
+
+// NewSnapshot creates a snapshot of the given findings. Closed and
+// suppressed findings are not included, because they shouldn't be
+// reported as new findings.
+func NewSnapshot(findings []*Finding, suppressions *Suppressions, dedup *DedupOptions) *Snapshot {
+	s := &Snapshot{
+		Version:        snapshotVersion,
+		CreatedAt:      time.Now(),
+		DedupFrames:    dedup.Frames,
+		DedupRootCause: dedup.RootCause,
+		Findings:       []*SnapshotEntry{},
+	}
+	for _, f := range findings {
+		if f.GetStatus().IsClosed() || suppressions.Match(f) != nil {
+			continue
+		}
+		entry := &SnapshotEntry{
+			DedupKey:    dedup.Key(f),
+			Name:        f.Name,
+			FuzzTest:    f.FuzzTest,
+			Description: f.ShortDescription(),
+		}
+		if f.MoreDetails != nil {
+			entry.Severity = f.MoreDetails.Severity
+		}
+		if f.Reproducibility != nil {
+			rate := f.Reproducibility.Rate
+			entry.ReproRate = &rate
+		}
+		s.Findings = append(s.Findings, entry)
+	}
+	// Sort the entries to produce stable output which can be compared
+	// and cached
+	sort.SliceStable(s.Findings, func(i, j int) bool {
+		return s.Findings[i].Name < s.Findings[j].Name
+	})
+	return s
+}
+
+// CreateSnapshot creates a snapshot of the current findings of the
+// project
+func CreateSnapshot(projectDir string, dedup *DedupOptions) (*Snapshot, error) {
+	findings, err := ListFindings(projectDir, nil)
+	if err != nil {
+		return nil, err
+	}
+	// Use the error details shipped with cifuzz to determine the
+	// severity of the findings
+	for _, f := range findings {
+		f.EnhanceWithLocalErrorDetails()
+	}
+	suppressions, err := LoadSuppressions(projectDir)
+	if err != nil {
+		return nil, err
+	}
+	return NewSnapshot(findings, suppressions, dedup), nil
+}
+
+// CreateSnapshotAtRevision creates a snapshot of the findings which were
+// committed to the Git repository of the project in the given revision.
+// The current suppression rules are applied to the findings.
+func CreateSnapshotAtRevision(projectDir, revision string, dedup *DedupOptions) (*Snapshot, error) {
+	findings, err := ListFindingsAtRevision(projectDir, revision)
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range findings {
+		f.EnhanceWithLocalErrorDetails()
+	}
+	suppressions, err := LoadSuppressions(projectDir)
+	if err != nil {
+		return nil, err
+	}
+	return NewSnapshot(findings, suppressions, dedup), nil
+}
+
+// DedupOptions returns the options which were used to compute the dedup
+// keys of the snapshot
+func (s *Snapshot) DedupOptions() *DedupOptions {
+	return &DedupOptions{
+		Frames:    s.DedupFrames,
+		RootCause: s.DedupRootCause,
+	}
+}
+
+// LoadSnapshot reads a snapshot which was written by
+// `cifuzz finding snapshot`
+func LoadSnapshot(path string) (*Snapshot, error) {
+	bytes, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	var s Snapshot
+	err = json.Unmarshal(bytes, &s)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to parse snapshot %s", path)
+	}
+	if s.Version != snapshotVersion {
+		return nil, errors.Errorf("Unsupported version %d of snapshot %s", s.Version, path)
+	}
+	return &s, nil
+}
+
+// ListFindingsAtRevision returns the findings which were committed to
+// the Git repository of the project in the given revision
+func ListFindingsAtRevision(projectDir, revision string) ([]*Finding, error) {
+	files, err := vcs.GitListFiles(projectDir, revision, nameFindingsDir)
+	if err != nil {
+		return nil, err
+	}
+
+	res := []*Finding{}
+	for _, file := range files {
+		// Git always uses forward slashes
+		if path.Base(file) != nameJSONFile {
+			continue
+		}
+		bytes, err := vcs.GitShowFile(projectDir, revision, file)
+		if err != nil {
+			return nil, err
+		}
+		var f Finding
+		err = json.Unmarshal(bytes, &f)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Failed to parse %s in revision %s", file, revision)
+		}
+		res = append(res, &f)
+	}
+	return res, nil
+}
+
+// Diff is the result of comparing the findings of a snapshot to a base
+// snapshot
+type Diff struct {
+	// Findings which are not in the base snapshot
+	New []*SnapshotEntry `json:"new"`
+	// Findings which are in both snapshots
+	StillPresent []*SnapshotEntry `json:"still_present"`
+	// Findings of the base snapshot which are not in the snapshot
+	Fixed []*SnapshotEntry `json:"fixed"`
+}
+
+// DiffSnapshots compares the findings of the two snapshots by their
+// dedup keys. The dedup keys include the fuzz test, so the same error
+// found by another fuzz test is a new finding, and findings without
+// in-project stack frames, like most timeouts, are only matched if they
+// have the same stack trace and crashing input (see DedupKey).
+func DiffSnapshots(base, head *Snapshot) *Diff {
+	diff := &Diff{
+		New:          []*SnapshotEntry{},
+		StillPresent: []*SnapshotEntry{},
+		Fixed:        []*SnapshotEntry{},
+	}
+
+	baseKeys := make(map[string]bool)
+	for _, e := range base.Findings {
+		baseKeys[e.DedupKey] = true
+	}
+	headKeys := make(map[string]bool)
+	for _, e := range head.Findings {
+		headKeys[e.DedupKey] = true
+		if baseKeys[e.DedupKey] {
+			diff.StillPresent = append(diff.StillPresent, e)
+		} else {
+			diff.New = append(diff.New, e)
+		}
+	}
+
+	// Report each fixed bug only once, even if the base snapshot
+	// contains duplicate findings of it
+	fixedKeys := make(map[string]bool)
+	for _, e := range base.Findings {
+		if headKeys[e.DedupKey] || fixedKeys[e.DedupKey] {
+			continue
+		}
+		fixedKeys[e.DedupKey] = true
+		diff.Fixed = append(diff.Fixed, e)
+	}
+	return diff
+}
+
+// ExceedsSeverity returns true if the severity of the finding is at
+// least minSeverity. Findings with an unknown severity are considered to
+// exceed any severity, so that they are not missed.
+func (e *SnapshotEntry) ExceedsSeverity(minSeverity float32) bool {
+	if e.Severity == nil {
+		return true
+	}
+	return e.Severity.Score >= minSeverity
+}
+
+// MeetsReproRate returns true if the reproduction rate of the finding
+// is at least minRate. Findings which were not replayed are considered
+// to be reproducible.
+func (e *SnapshotEntry) MeetsReproRate(minRate float64) bool {
+	return e.ReproRate == nil || *e.ReproRate >= minRate
+}
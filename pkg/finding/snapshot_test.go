@@ -0,0 +1,80 @@
+package finding
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"code-intelligence.com/cifuzz/pkg/parser/libfuzzer/stacktrace"
+)
+
+func TestNewSnapshot(t *testing.T) {
+	open := &Finding{
+		Name:        "open_finding",
+		FuzzTest:    "my_fuzz_test",
+		MoreDetails: &ErrorDetails{ID: "heap_buffer_overflow", Severity: &Severity{Score: 9.0}},
+		StackTrace:  []*stacktrace.StackFrame{{Function: "parse", SourceFile: "src/parser.cpp"}},
+	}
+	fixed := &Finding{Name: "fixed_finding"}
+	fixed.SetStatus(StatusFixed)
+	suppressed := &Finding{Name: "suppressed_finding"}
+	suppressions := &Suppressions{Rules: []*SuppressionRule{{Finding: "suppressed_finding"}}}
+
+	s := NewSnapshot([]*Finding{open, fixed, suppressed}, suppressions, &DedupOptions{Frames: DefaultDedupFrames})
+	require.Equal(t, DefaultDedupFrames, s.DedupFrames)
+	require.Equal(t, []*SnapshotEntry{{
+		DedupKey:    open.DedupKey(DefaultDedupFrames),
+		Name:        "open_finding",
+		FuzzTest:    "my_fuzz_test",
+		Description: open.ShortDescription(),
+		Severity:    &Severity{Score: 9.0},
+	}}, s.Findings)
+}
+
+func TestLoadSnapshot(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "snapshot.json")
+	_, err := LoadSnapshot(path)
+	require.Error(t, err)
+
+	err = os.WriteFile(path, []byte(`{"version": 1, "dedup_frames": 2, "findings": [{"dedup_key": "key", "name": "a"}]}`), 0o644)
+	require.NoError(t, err)
+	s, err := LoadSnapshot(path)
+	require.NoError(t, err)
+	require.Equal(t, 2, s.DedupFrames)
+	require.Len(t, s.Findings, 1)
+
+	err = os.WriteFile(path, []byte(`{"version": 2, "findings": []}`), 0o644)
+	require.NoError(t, err)
+	_, err = LoadSnapshot(path)
+	require.Error(t, err)
+}
+
+func TestDiffSnapshots(t *testing.T) {
+	fixed := &SnapshotEntry{DedupKey: "fixed", Name: "fixed"}
+	fixedDuplicate := &SnapshotEntry{DedupKey: "fixed", Name: "fixed_duplicate"}
+	present := &SnapshotEntry{DedupKey: "present", Name: "present"}
+	// Findings with the same dedup key are the same, even if their
+	// names differ
+	presentRenamed := &SnapshotEntry{DedupKey: "present", Name: "present_renamed"}
+	newEntry := &SnapshotEntry{DedupKey: "new", Name: "new"}
+
+	base := &Snapshot{Findings: []*SnapshotEntry{fixed, fixedDuplicate, present}}
+	head := &Snapshot{Findings: []*SnapshotEntry{newEntry, presentRenamed}}
+
+	diff := DiffSnapshots(base, head)
+	require.Equal(t, []*SnapshotEntry{newEntry}, diff.New)
+	require.Equal(t, []*SnapshotEntry{presentRenamed}, diff.StillPresent)
+	require.Equal(t, []*SnapshotEntry{fixed}, diff.Fixed)
+}
+
+func TestSnapshotEntry_ExceedsSeverity(t *testing.T) {
+	e := &SnapshotEntry{Severity: &Severity{Score: 5.0}}
+	require.True(t, e.ExceedsSeverity(0))
+	require.True(t, e.ExceedsSeverity(5.0))
+	require.False(t, e.ExceedsSeverity(7.0))
+
+	// Findings with an unknown severity always exceed the threshold
+	require.True(t, (&SnapshotEntry{}).ExceedsSeverity(9.0))
+}
@@ -0,0 +1,145 @@
+package finding
+
+import (
+	"bufio"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"code-intelligence.com/cifuzz/pkg/log"
+	"code-intelligence.com/cifuzz/pkg/parser/libfuzzer/stacktrace"
+)
+
+const (
+	DefaultSourceContextFrames = 3
+	// The number of lines shown before and after the line of a frame
+	sourceContextLines = 3
+)
+
+// SourceContext contains the lines of source code around the location
+// of a stack frame
+type SourceContext struct {
+	Function   string        `json:"function,omitempty"`
+	SourceFile string        `json:"source_file"`
+	Line       uint32        `json:"line"`
+	Lines      []*SourceLine `json:"lines"`
+}
+
+type SourceLine struct {
+	Number uint32 `json:"number"`
+	Text   string `json:"text"`
+}
+
+// SourceContexts returns the source code around the top numFrames
+// stack frames of the finding. Frames of the fuzzer and sanitizer
+// runtimes and frames whose source file can't be found in the project
+// directory are skipped.
+func (f *Finding) SourceContexts(projectDir string, numFrames int) []*SourceContext {
+	var contexts []*SourceContext
+	seen := make(map[string]bool)
+	for _, frame := range f.StackTrace {
+		if len(contexts) >= numFrames {
+			break
+		}
+		if frame.Line == 0 || IsRuntimeFrame(frame) || seen[frame.Location()] {
+			continue
+		}
+		// Recursive calls result in multiple frames with the same
+		// location, which we only show once
+		seen[frame.Location()] = true
+
+		path := resolveSourceFile(projectDir, frame)
+		if path == "" {
+			log.Debugf("Source file of stack frame %s not found", frame.Location())
+			continue
+		}
+		first := uint32(1)
+		if frame.Line > sourceContextLines {
+			first = frame.Line - sourceContextLines
+		}
+		lines, err := readLines(path, first, frame.Line+sourceContextLines)
+		if err != nil {
+			log.Debugf("Failed to read source file %s: %v", path, err)
+			continue
+		}
+		if len(lines) == 0 {
+			continue
+		}
+		contexts = append(contexts, &SourceContext{
+			Function:   frame.Function,
+			SourceFile: frame.SourceFile,
+			Line:       frame.Line,
+			Lines:      lines,
+		})
+	}
+	return contexts
+}
+
+// resolveSourceFile returns the path of the source file of the stack
+// frame or an empty string if it doesn't exist
+func resolveSourceFile(projectDir string, frame *stacktrace.StackFrame) string {
+	path := filepath.FromSlash(frame.SourceFile)
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(projectDir, path)
+	}
+	if info, err := os.Stat(path); err == nil && !info.IsDir() {
+		return path
+	}
+
+	// The source files of Java stack frames are fully qualified class
+	// names, e.g. "com.example.Parser$Inner", so we search for a file
+	// with the corresponding path, e.g. "com/example/Parser.java"
+	if strings.Contains(frame.SourceFile, "/") {
+		return ""
+	}
+	class := frame.SourceFile
+	if i := strings.Index(class, "$"); i != -1 {
+		class = class[:i]
+	}
+	suffix := string(filepath.Separator) + filepath.FromSlash(strings.ReplaceAll(class, ".", "/")) + ".java"
+
+	var found string
+	errFound := errors.New("found")
+	_ = filepath.WalkDir(projectDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if d.IsDir() {
+			if path != projectDir && strings.HasPrefix(d.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if strings.HasSuffix(path, suffix) {
+			found = path
+			return errFound
+		}
+		return nil
+	})
+	return found
+}
+
+// readLines returns the lines from first to last (inclusive, starting
+// at 1) of the file
+func readLines(path string, first, last uint32) ([]*SourceLine, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	defer file.Close()
+
+	var lines []*SourceLine
+	scanner := bufio.NewScanner(file)
+	for number := uint32(1); number <= last && scanner.Scan(); number++ {
+		if number >= first {
+			lines = append(lines, &SourceLine{Number: number, Text: scanner.Text()})
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return lines, nil
+}
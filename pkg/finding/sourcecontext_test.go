@@ -0,0 +1,76 @@
+package finding
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"code-intelligence.com/cifuzz/pkg/parser/libfuzzer/stacktrace"
+)
+
+func TestSourceContexts(t *testing.T) {
+	projectDir := t.TempDir()
+	writeSourceFile(t, projectDir, "src/parser.cpp", 10)
+	writeSourceFile(t, projectDir, "src/main/java/com/example/Parser.java", 3)
+
+	f := &Finding{
+		StackTrace: []*stacktrace.StackFrame{
+			{SourceFile: "src/parser.cpp", Line: 2, Function: "parse"},
+			// Recursive call, which is only shown once
+			{SourceFile: "src/parser.cpp", Line: 2, Function: "parse"},
+			// The source file doesn't exist
+			{SourceFile: "src/missing.cpp", Line: 5, Function: "missing"},
+			{SourceFile: "com.example.Parser$Inner", Line: 3, Function: "parse"},
+			{SourceFile: "src/parser.cpp", Line: 9, Function: "LLVMFuzzerTestOneInput"},
+		},
+	}
+
+	contexts := f.SourceContexts(projectDir, 2)
+	require.Equal(t, []*SourceContext{
+		{
+			Function:   "parse",
+			SourceFile: "src/parser.cpp",
+			Line:       2,
+			Lines: []*SourceLine{
+				{Number: 1, Text: "line 1"},
+				{Number: 2, Text: "line 2"},
+				{Number: 3, Text: "line 3"},
+				{Number: 4, Text: "line 4"},
+				{Number: 5, Text: "line 5"},
+			},
+		},
+		{
+			Function:   "parse",
+			SourceFile: "com.example.Parser$Inner",
+			Line:       3,
+			Lines: []*SourceLine{
+				{Number: 1, Text: "line 1"},
+				{Number: 2, Text: "line 2"},
+				{Number: 3, Text: "line 3"},
+			},
+		},
+	}, contexts)
+
+	// The lines after the end of the file are omitted
+	contexts = f.SourceContexts(projectDir, 3)
+	require.Len(t, contexts, 3)
+	require.Equal(t, uint32(6), contexts[2].Lines[0].Number)
+	require.Equal(t, uint32(10), contexts[2].Lines[len(contexts[2].Lines)-1].Number)
+
+	require.Empty(t, f.SourceContexts(projectDir, 0))
+}
+
+func writeSourceFile(t *testing.T, projectDir, path string, numLines int) {
+	path = filepath.Join(projectDir, filepath.FromSlash(path))
+	err := os.MkdirAll(filepath.Dir(path), 0o755)
+	require.NoError(t, err)
+	var content string
+	for i := 1; i <= numLines; i++ {
+		content += "line " + fmt.Sprint(i) + "\n"
+	}
+	err = os.WriteFile(path, []byte(content), 0o644)
+	require.NoError(t, err)
+}
@@ -0,0 +1,86 @@
+package finding
+
+import (
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Status is the triage status of a finding
+type Status string
+
+const (
+	StatusOpen      Status = "open"
+	StatusConfirmed Status = "confirmed"
+	StatusFixed     Status = "fixed"
+	StatusIgnored   Status = "ignored"
+	StatusWontFix   Status = "wontfix"
+)
+
+var ValidStatuses = []Status{StatusOpen, StatusConfirmed, StatusFixed, StatusIgnored, StatusWontFix}
+
+// Note is a comment on a finding, added when triaging it
+type Note struct {
+	Text      string    `json:"text"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ParseStatus returns the status with the given name
+func ParseStatus(s string) (Status, error) {
+	for _, status := range ValidStatuses {
+		if strings.EqualFold(s, string(status)) {
+			return status, nil
+		}
+	}
+
+	var names []string
+	for _, status := range ValidStatuses {
+		names = append(names, string(status))
+	}
+	return "", errors.Errorf("Invalid status %q, valid statuses are: %s", s, strings.Join(names, ", "))
+}
+
+// IsClosed returns true if no further action is required for findings
+// with this status
+func (s Status) IsClosed() bool {
+	return s == StatusFixed || s == StatusIgnored || s == StatusWontFix
+}
+
+// GetStatus returns the triage status of the finding. Findings which
+// were saved before the status was introduced are open.
+func (f *Finding) GetStatus() Status {
+	if f.Status == "" {
+		return StatusOpen
+	}
+	return f.Status
+}
+
+// SetStatus sets the triage status of the finding and updates the
+// timestamp of the last status change
+func (f *Finding) SetStatus(status Status) {
+	now := time.Now()
+	f.Status = status
+	f.StatusUpdatedAt = &now
+}
+
+// AddNote adds a note to the finding
+func (f *Finding) AddNote(text string) {
+	f.Notes = append(f.Notes, &Note{Text: text, CreatedAt: time.Now()})
+}
+
+// CopyTriageInfo copies the triage status, assignee and notes from
+// another finding. This is used to keep the triage information when a
+// finding is found again and therefore saved again. If a finding which
+// was marked as fixed is found again, it is reopened.
+func (f *Finding) CopyTriageInfo(other *Finding) {
+	f.Status = other.Status
+	f.StatusUpdatedAt = other.StatusUpdatedAt
+	f.Assignee = other.Assignee
+	f.Notes = other.Notes
+
+	if other.GetStatus() == StatusFixed {
+		f.SetStatus(StatusOpen)
+		f.AddNote("Reopened because the finding was found again")
+	}
+}
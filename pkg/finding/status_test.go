@@ -0,0 +1,59 @@
+package finding
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseStatus(t *testing.T) {
+	status, err := ParseStatus("WontFix")
+	require.NoError(t, err)
+	require.Equal(t, StatusWontFix, status)
+	require.True(t, status.IsClosed())
+
+	_, err = ParseStatus("done")
+	require.Error(t, err)
+}
+
+func TestLoadFinding_WithoutStatus(t *testing.T) {
+	testDir, err := os.MkdirTemp(testBaseDir, "status-test-")
+	require.NoError(t, err)
+
+	// finding.json written by a cifuzz version without triage status
+	findingDir := filepath.Join(testDir, nameFindingsDir, "old_finding")
+	err = os.MkdirAll(findingDir, 0o755)
+	require.NoError(t, err)
+	err = os.WriteFile(filepath.Join(findingDir, nameJSONFile), []byte(`{
+  "name": "old_finding",
+  "type": "CRASH",
+  "created_at": "2023-01-02T15:04:05Z"
+}`), 0o644)
+	require.NoError(t, err)
+
+	f, err := LoadFinding(testDir, "old_finding", nil)
+	require.NoError(t, err)
+	require.Equal(t, StatusOpen, f.GetStatus())
+	require.Empty(t, f.Notes)
+}
+
+func TestCopyTriageInfo(t *testing.T) {
+	old := &Finding{Assignee: "alice"}
+	old.SetStatus(StatusConfirmed)
+	old.AddNote("Looks bad")
+
+	f := &Finding{}
+	f.CopyTriageInfo(old)
+	require.Equal(t, StatusConfirmed, f.GetStatus())
+	require.Equal(t, "alice", f.Assignee)
+	require.Len(t, f.Notes, 1)
+
+	// Fixed findings are reopened when they are found again
+	old.SetStatus(StatusFixed)
+	f = &Finding{}
+	f.CopyTriageInfo(old)
+	require.Equal(t, StatusOpen, f.GetStatus())
+	require.Len(t, f.Notes, 2)
+}
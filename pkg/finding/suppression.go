@@ -0,0 +1,158 @@
+package finding
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"time"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// SuppressionsFileName is the name of the file in the project directory
+// which contains the suppression rules.
+const SuppressionsFileName = ".cifuzz-suppressions.yaml"
+
+const expiryDateLayout = "2006-01-02"
+
+// Suppressions is the content of the suppressions file. Findings which
+// match one of the rules are saved, but not reported.
+type Suppressions struct {
+	Rules []*SuppressionRule `yaml:"suppressions"`
+}
+
+// SuppressionRule matches findings by error ID, function or source file
+// in the stack trace, or finding name. If multiple criteria are
+// specified, all of them must match. Rules are only applied until their
+// expiry date (inclusive), if one is set.
+type SuppressionRule struct {
+	ErrorID string `yaml:"error_id,omitempty" json:"error_id,omitempty"`
+	// Regular expression matched against the function names of the
+	// stack frames
+	Function string `yaml:"function,omitempty" json:"function,omitempty"`
+	// Regular expression matched against the source files of the stack
+	// frames
+	File    string `yaml:"file,omitempty" json:"file,omitempty"`
+	Finding string `yaml:"finding,omitempty" json:"finding,omitempty"`
+	Reason  string `yaml:"reason" json:"reason"`
+	// Date in the format YYYY-MM-DD
+	Expires string `yaml:"expires,omitempty" json:"expires,omitempty"`
+
+	functionRegex *regexp.Regexp
+	fileRegex     *regexp.Regexp
+	expiresAt     time.Time
+}
+
+// LoadSuppressions parses the suppressions file in the project
+// directory. If the file doesn't exist, no rules are returned.
+func LoadSuppressions(projectDir string) (*Suppressions, error) {
+	path := filepath.Join(projectDir, SuppressionsFileName)
+	bytes, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return &Suppressions{}, nil
+	}
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	var s Suppressions
+	err = yaml.Unmarshal(bytes, &s)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to parse %s", SuppressionsFileName)
+	}
+
+	for i, rule := range s.Rules {
+		err = rule.compile()
+		if err != nil {
+			return nil, errors.WithMessagef(err, "Invalid rule #%d in %s", i+1, SuppressionsFileName)
+		}
+	}
+
+	return &s, nil
+}
+
+func (r *SuppressionRule) compile() error {
+	var err error
+
+	if r.ErrorID == "" && r.Function == "" && r.File == "" && r.Finding == "" {
+		return errors.New("At least one of error_id, function, file or finding must be set")
+	}
+	if r.Reason == "" {
+		return errors.New("A reason must be set")
+	}
+
+	if r.Function != "" {
+		r.functionRegex, err = regexp.Compile(r.Function)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+	}
+	if r.File != "" {
+		r.fileRegex, err = regexp.Compile(r.File)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+	}
+	if r.Expires != "" {
+		r.expiresAt, err = time.ParseInLocation(expiryDateLayout, r.Expires, time.Local)
+		if err != nil {
+			return errors.Wrapf(err, "Invalid expiry date %q, expected format YYYY-MM-DD", r.Expires)
+		}
+	}
+	return nil
+}
+
+// Match returns the first rule which matches the finding and did not
+// expire yet, or nil if the finding is not suppressed.
+func (s *Suppressions) Match(f *Finding) *SuppressionRule {
+	if s == nil {
+		return nil
+	}
+	now := time.Now()
+	for _, rule := range s.Rules {
+		if rule.Expired(now) {
+			continue
+		}
+		if rule.Matches(f) {
+			return rule
+		}
+	}
+	return nil
+}
+
+// Expired returns true if the rule expired before the given time
+func (r *SuppressionRule) Expired(now time.Time) bool {
+	if r.expiresAt.IsZero() {
+		return false
+	}
+	// The rule is still valid on the day of the expiry date
+	return !now.Before(r.expiresAt.AddDate(0, 0, 1))
+}
+
+// Matches returns true if the finding matches all criteria of the rule
+func (r *SuppressionRule) Matches(f *Finding) bool {
+	if r.Finding != "" && r.Finding != f.Name {
+		return false
+	}
+	if r.ErrorID != "" && (f.MoreDetails == nil || f.MoreDetails.ID != r.ErrorID) {
+		return false
+	}
+	if r.functionRegex != nil || r.fileRegex != nil {
+		matched := false
+		for _, frame := range f.StackTrace {
+			if r.functionRegex != nil && !r.functionRegex.MatchString(frame.Function) {
+				continue
+			}
+			if r.fileRegex != nil && !r.fileRegex.MatchString(frame.SourceFile) {
+				continue
+			}
+			matched = true
+			break
+		}
+		if !matched {
+			return false
+		}
+	}
+	return true
+}
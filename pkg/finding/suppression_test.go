@@ -0,0 +1,76 @@
+package finding
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"code-intelligence.com/cifuzz/pkg/parser/libfuzzer/stacktrace"
+)
+
+func TestLoadSuppressions(t *testing.T) {
+	testDir, err := os.MkdirTemp(testBaseDir, "suppressions-test-")
+	require.NoError(t, err)
+
+	// No suppressions file
+	s, err := LoadSuppressions(testDir)
+	require.NoError(t, err)
+	require.Empty(t, s.Rules)
+
+	err = os.WriteFile(filepath.Join(testDir, SuppressionsFileName), []byte(`
+suppressions:
+  - error_id: heap_buffer_overflow
+    file: "^third_party/"
+    reason: Bug in third-party code
+  - finding: funky_angelfish
+    reason: Accepted
+    expires: 2000-01-01
+  - function: "^zlib::"
+    reason: Bug in zlib
+`), 0o644)
+	require.NoError(t, err)
+
+	s, err = LoadSuppressions(testDir)
+	require.NoError(t, err)
+	require.Len(t, s.Rules, 3)
+
+	f := &Finding{
+		Name:        "funky_angelfish",
+		MoreDetails: &ErrorDetails{ID: "heap_buffer_overflow"},
+		StackTrace: []*stacktrace.StackFrame{
+			{Function: "inflate", SourceFile: "third_party/zlib/inflate.c"},
+			{Function: "parse", SourceFile: "src/parser.cpp"},
+		},
+	}
+	require.Equal(t, s.Rules[0], s.Match(f))
+
+	// Both the error ID and the file must match
+	f.MoreDetails.ID = "use_after_free"
+	require.Nil(t, s.Match(f))
+
+	// The rule matching the finding name expired
+	require.True(t, s.Rules[1].Expired(time.Now()))
+
+	f.StackTrace = append(f.StackTrace, &stacktrace.StackFrame{Function: "zlib::uncompress"})
+	require.Equal(t, s.Rules[2], s.Match(f))
+}
+
+func TestLoadSuppressions_Invalid(t *testing.T) {
+	testDir, err := os.MkdirTemp(testBaseDir, "suppressions-test-")
+	require.NoError(t, err)
+
+	for _, content := range []string{
+		"suppressions:\n  - error_id: heap_buffer_overflow\n",
+		"suppressions:\n  - reason: No criteria\n",
+		"suppressions:\n  - function: \"(\"\n    reason: Invalid regex\n",
+		"suppressions:\n  - finding: foo\n    reason: Invalid date\n    expires: tomorrow\n",
+	} {
+		err = os.WriteFile(filepath.Join(testDir, SuppressionsFileName), []byte(content), 0o644)
+		require.NoError(t, err)
+		_, err = LoadSuppressions(testDir)
+		require.Error(t, err, content)
+	}
+}
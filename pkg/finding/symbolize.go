@@ -0,0 +1,231 @@
+package finding
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"code-intelligence.com/cifuzz/pkg/log"
+	"code-intelligence.com/cifuzz/pkg/parser/libfuzzer/stacktrace"
+	"code-intelligence.com/cifuzz/pkg/runfiles"
+	"code-intelligence.com/cifuzz/util/fileutil"
+)
+
+// Matches stack frames which the sanitizers print when they can't
+// symbolize them, e.g.
+//
+//	#0 0x55d4f1a2b3c4 in parse (/path/to/fuzz_test+0x1234)
+//	#1 0x55d4f1a2b3c4  (/path/to/fuzz_test+0x1234)
+var unsymbolizedFramePattern = regexp.MustCompile(
+	`^(?P<prefix>\s*#(?P<frame_number>\d+)\s+(?P<address>0x[0-9a-fA-F]+))\s+(in\s+\S+\s+)?\((?P<module>[^()\s]+)\+(?P<offset>0x[0-9a-fA-F]+)\)`)
+
+// SymbolizeOptions configures how stack traces are symbolized
+type SymbolizeOptions struct {
+	ProjectDir string
+	// If set, this binary is used instead of the binaries in the stack
+	// frames which have the same file name or which don't exist
+	// anymore, for example because the fuzz test was built elsewhere.
+	Binary string
+	// The path to llvm-symbolizer. Defaults to the one found via
+	// runfiles.Finder.
+	SymbolizerPath string
+}
+
+type unsymbolizedFrame struct {
+	logIndex    int
+	prefix      string
+	frameNumber int
+	module      string
+	offset      uint64
+}
+
+// NeedsSymbolization returns true if the stack trace of the finding
+// contains frames which were not symbolized
+func (f *Finding) NeedsSymbolization() bool {
+	return len(unsymbolizedFrames(f.Logs)) > 0
+}
+
+// Symbolize symbolizes the frames of the stack trace in the logs which
+// were not symbolized when the finding was reported, using
+// llvm-symbolizer. The symbolized frames replace the unsymbolized ones
+// in the logs and the stack trace of the finding is parsed again.
+// It returns the number of frames which were symbolized.
+func (f *Finding) Symbolize(opts *SymbolizeOptions) (int, error) {
+	frames := unsymbolizedFrames(f.Logs)
+	if len(frames) == 0 {
+		return 0, nil
+	}
+
+	// Symbolize the offsets of each binary with a single call of
+	// llvm-symbolizer
+	framesByBinary := make(map[string][]*unsymbolizedFrame)
+	var binaries []string
+	for _, frame := range frames {
+		binary := opts.binaryFor(frame.module)
+		if binary == "" {
+			log.Debugf("Not symbolizing frame #%d, %s doesn't exist", frame.frameNumber, frame.module)
+			continue
+		}
+		if _, ok := framesByBinary[binary]; !ok {
+			binaries = append(binaries, binary)
+		}
+		framesByBinary[binary] = append(framesByBinary[binary], frame)
+	}
+	if len(binaries) == 0 {
+		return 0, nil
+	}
+
+	symbolizer := opts.SymbolizerPath
+	if symbolizer == "" {
+		var err error
+		symbolizer, err = runfiles.Finder.LLVMSymbolizerPath()
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	logs := append([]string{}, f.Logs...)
+	numSymbolized := 0
+	for _, binary := range binaries {
+		var offsets []uint64
+		for _, frame := range framesByBinary[binary] {
+			offset := frame.offset
+			// Except for the top frame, the offsets are return
+			// addresses, which point to the instruction after the
+			// call. Like the sanitizers, we subtract one to get the
+			// location of the call.
+			if frame.frameNumber > 0 && offset > 0 {
+				offset--
+			}
+			offsets = append(offsets, offset)
+		}
+		locations, err := runSymbolizer(symbolizer, binary, offsets)
+		if err != nil {
+			return 0, err
+		}
+		for i, frame := range framesByBinary[binary] {
+			if locations[i] == nil {
+				continue
+			}
+			// Use the format of symbolized frames, so that they are
+			// handled by the stack trace parser like any other frame
+			logs[frame.logIndex] = fmt.Sprintf("%s in %s %s", frame.prefix, locations[i].function, locations[i].location)
+			numSymbolized++
+		}
+	}
+	if numSymbolized == 0 {
+		return 0, nil
+	}
+
+	stackTrace, err := stacktrace.NewParser(&stacktrace.ParserOptions{ProjectDir: opts.ProjectDir}).Parse(logs)
+	if err != nil {
+		return 0, err
+	}
+	f.Logs = logs
+	f.StackTrace = stackTrace
+	return numSymbolized, nil
+}
+
+// binaryFor returns the binary which should be used to symbolize
+// frames of the given module, or an empty string if there is none
+func (opts *SymbolizeOptions) binaryFor(module string) string {
+	exists, err := fileutil.Exists(module)
+	if err != nil {
+		log.Debugf("Failed to check if %s exists: %v", module, err)
+	}
+	if opts.Binary != "" && (!exists || filepath.Base(module) == filepath.Base(opts.Binary)) {
+		return opts.Binary
+	}
+	if !exists {
+		return ""
+	}
+	return module
+}
+
+// unsymbolizedFrames returns the unsymbolized frames of the first stack
+// trace in the logs
+func unsymbolizedFrames(logs []string) []*unsymbolizedFrame {
+	var frames []*unsymbolizedFrame
+	lastFrameNumber := -1
+	for i, line := range logs {
+		match := unsymbolizedFramePattern.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+		frameNumber, err := strconv.Atoi(match[unsymbolizedFramePattern.SubexpIndex("frame_number")])
+		if err != nil {
+			continue
+		}
+		if frameNumber <= lastFrameNumber {
+			// This is probably a new stack trace, e.g. the one of the
+			// allocation of the memory which was accessed
+			break
+		}
+		lastFrameNumber = frameNumber
+		offset, err := strconv.ParseUint(strings.TrimPrefix(match[unsymbolizedFramePattern.SubexpIndex("offset")], "0x"), 16, 64)
+		if err != nil {
+			continue
+		}
+		frames = append(frames, &unsymbolizedFrame{
+			logIndex:    i,
+			prefix:      match[unsymbolizedFramePattern.SubexpIndex("prefix")],
+			frameNumber: frameNumber,
+			module:      match[unsymbolizedFramePattern.SubexpIndex("module")],
+			offset:      offset,
+		})
+	}
+	return frames
+}
+
+type symbolizedLocation struct {
+	function string
+	location string
+}
+
+// runSymbolizer symbolizes the offsets in the binary with
+// llvm-symbolizer. For offsets which can't be symbolized, the returned
+// slice contains nil.
+func runSymbolizer(symbolizer, binary string, offsets []uint64) ([]*symbolizedLocation, error) {
+	var stdin bytes.Buffer
+	for _, offset := range offsets {
+		_, _ = fmt.Fprintf(&stdin, "0x%x\n", offset)
+	}
+
+	cmd := exec.Command(symbolizer, "--obj="+binary, "--no-inlines", "--demangle", "--functions=linkage")
+	cmd.Stdin = &stdin
+	log.Debugf("Command: %s", cmd.String())
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to symbolize %s", binary)
+	}
+	return parseSymbolizerOutput(string(output), len(offsets)), nil
+}
+
+// parseSymbolizerOutput parses the output of llvm-symbolizer, which
+// consists of a function name and a location per address, separated
+// by empty lines
+func parseSymbolizerOutput(output string, numOffsets int) []*symbolizedLocation {
+	locations := make([]*symbolizedLocation, numOffsets)
+	blocks := strings.Split(strings.TrimSpace(output), "\n\n")
+	for i, block := range blocks {
+		if i >= numOffsets {
+			break
+		}
+		lines := strings.Split(strings.TrimSpace(block), "\n")
+		if len(lines) < 2 {
+			continue
+		}
+		function, location := strings.TrimSpace(lines[0]), strings.TrimSpace(lines[1])
+		if function == "??" || strings.HasPrefix(location, "??") {
+			continue
+		}
+		locations[i] = &symbolizedLocation{function: function, location: location}
+	}
+	return locations
+}
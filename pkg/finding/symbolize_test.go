@@ -0,0 +1,133 @@
+package finding
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnsymbolizedFrames(t *testing.T) {
+	logs := []string{
+		"==1==ERROR: AddressSanitizer: heap-buffer-overflow on address 0x602000000011",
+		"    #0 0x55d4f1a2b3c4 in parse (/build/fuzz_test+0x1234)",
+		"    #1 0x55d4f1a2b3c5 in fuzz /src/fuzz_test.cpp:10:3",
+		"    #2 0x55d4f1a2b3c6  (/lib/libfoo.so+0xabc)",
+		"allocated by thread T0 here:",
+		"    #0 0x55d4f1a2b3c7  (/build/fuzz_test+0x5678)",
+	}
+	frames := unsymbolizedFrames(logs)
+	require.Equal(t, []*unsymbolizedFrame{
+		{logIndex: 1, prefix: "    #0 0x55d4f1a2b3c4", frameNumber: 0, module: "/build/fuzz_test", offset: 0x1234},
+		{logIndex: 3, prefix: "    #2 0x55d4f1a2b3c6", frameNumber: 2, module: "/lib/libfoo.so", offset: 0xabc},
+	}, frames)
+
+	require.True(t, (&Finding{Logs: logs}).NeedsSymbolization())
+	require.False(t, (&Finding{Logs: logs[:1]}).NeedsSymbolization())
+}
+
+func TestParseSymbolizerOutput(t *testing.T) {
+	output := `parse(char const*, unsigned long)
+/src/parser.cpp:12:3
+
+??
+??:0:0
+
+_end
+??:0:0
+`
+	locations := parseSymbolizerOutput(output, 3)
+	require.Equal(t, []*symbolizedLocation{
+		{function: "parse(char const*, unsigned long)", location: "/src/parser.cpp:12:3"},
+		nil,
+		nil,
+	}, locations)
+}
+
+func TestSymbolize(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Symbolization is tested on Unix only")
+	}
+	symbolizer, err := exec.LookPath("llvm-symbolizer")
+	if err != nil {
+		t.Skip("llvm-symbolizer not found")
+	}
+	compiler, err := exec.LookPath("cc")
+	if err != nil {
+		t.Skip("C compiler not found")
+	}
+
+	projectDir := t.TempDir()
+	source := filepath.Join(projectDir, "fuzz_test.c")
+	err = os.WriteFile(source, []byte(`int parse(int x) {
+  return x * 2;
+}
+
+int main() {
+  return parse(3);
+}
+`), 0o644)
+	require.NoError(t, err)
+	binary := filepath.Join(projectDir, "fuzz_test")
+	out, err := exec.Command(compiler, "-g", "-O0", "-fno-pie", "-no-pie", "-o", binary, source).CombinedOutput()
+	require.NoError(t, err, string(out))
+
+	// Determine the offset of the function to simulate an unsymbolized
+	// stack frame
+	out, err = exec.Command("nm", binary).Output()
+	if err != nil {
+		t.Skip("nm not found")
+	}
+	var address string
+	for _, line := range strings.Split(string(out), "\n") {
+		if strings.HasSuffix(line, " T parse") {
+			address = "0x" + strings.TrimLeft(strings.Fields(line)[0], "0")
+		}
+	}
+	require.NotEmpty(t, address)
+
+	f := &Finding{
+		Name: "my_finding",
+		Logs: []string{
+			"==1==ERROR: AddressSanitizer: SEGV on unknown address",
+			fmt.Sprintf("    #0 %s  (%s+%s)", address, filepath.Join("/moved", "fuzz_test"), address),
+		},
+	}
+
+	// The binary doesn't exist at the path in the stack trace
+	numSymbolized, err := f.Symbolize(&SymbolizeOptions{ProjectDir: projectDir, SymbolizerPath: symbolizer})
+	require.NoError(t, err)
+	require.Equal(t, 0, numSymbolized)
+	require.Empty(t, f.StackTrace)
+
+	numSymbolized, err = f.Symbolize(&SymbolizeOptions{ProjectDir: projectDir, SymbolizerPath: symbolizer, Binary: binary})
+	require.NoError(t, err)
+	require.Equal(t, 1, numSymbolized)
+	require.Len(t, f.StackTrace, 1)
+	require.Equal(t, "parse", f.StackTrace[0].Function)
+	require.Equal(t, "fuzz_test.c", f.StackTrace[0].SourceFile)
+	require.Equal(t, uint32(1), f.StackTrace[0].Line)
+	require.False(t, f.NeedsSymbolization())
+
+	// LoadFinding symbolizes the stack trace of a stored finding in
+	// memory, without saving the finding
+	stored := &Finding{
+		Name: "stored_finding",
+		Logs: []string{fmt.Sprintf("    #0 %s  (%s+%s)", address, binary, address)},
+	}
+	err = stored.Save(projectDir)
+	require.NoError(t, err)
+	loaded, err := LoadFinding(projectDir, stored.Name, nil)
+	require.NoError(t, err)
+	require.Len(t, loaded.StackTrace, 1)
+	require.Equal(t, "parse", loaded.StackTrace[0].Function)
+	loaded, err = LoadStoredFinding(projectDir, stored.Name)
+	require.NoError(t, err)
+	require.Empty(t, loaded.StackTrace)
+	require.True(t, loaded.NeedsSymbolization())
+}
@@ -30,13 +30,40 @@ func (m *matcher) Match(input string) bool {
 }
 
 var matchers = []matcher{
-	{id: "alloc_dealloc_mismatch", substrings: []string{"attempting free on address which was not malloc"}},
+	{
+		id:         "alloc_dealloc_mismatch",
+		substrings: []string{"attempting free on address which was not malloc", "alloc-dealloc-mismatch", "new-delete-type-mismatch"},
+	},
+	{
+		id:         "allocation_size_too_big",
+		substrings: []string{"allocation-size-too-big", "calloc-parameter-overflow", "requested allocation size"},
+	},
+	{id: "class_cast", substrings: []string{"java.lang.ClassCastException"}},
+	{id: "container_overflow", substrings: []string{"container-overflow on address"}},
+	{id: "data_race", substrings: []string{"data race"}},
 	{id: "deadly_signal", substrings: []string{"deadly signal"}},
+	{
+		id:         "division_by_zero",
+		substrings: []string{"undefined behavior: division by zero", "FPE on unknown address", "java.lang.ArithmeticException: / by zero"},
+	},
 	{id: "double_free", substrings: []string{"attempting double-free on"}},
+	{id: "dynamic_stack_buffer_overflow", substrings: []string{"dynamic-stack-buffer-overflow on address"}},
+	{id: "expression_language_injection", substrings: []string{"Security Issue: Expression Language Injection"}},
+	{id: "file_path_traversal", substrings: []string{"Security Issue: File path traversal", "Security Issue: File read/write hook path"}},
+	{id: "float_cast_overflow", regexs: []*regexp.Regexp{regexp.MustCompile(`undefined behaviou?r: .+ is outside the range of representable values`)}},
+	{id: "function_type_mismatch", substrings: []string{"through pointer to incorrect function type"}},
 	{id: "heap_buffer_overflow", substrings: []string{"heap-buffer-overflow on address"}},
-	{id: "heap_use_after_free", substrings: []string{"heap-use-after-free on address"}},
+	{id: "heap_use_after_free", substrings: []string{"heap-use-after-free on address", "heap-use-after-free (pid="}},
 	{id: "global_buffer_overflow", substrings: []string{"global-buffer-overflow on address"}},
+	{id: "implicit_conversion", substrings: []string{"undefined behavior: implicit conversion from type"}},
+	{id: "invalid_value", substrings: []string{"which is not a valid value for type"}},
 	{id: "java_assertion_error", substrings: []string{"Java Assertion Error"}},
+	{id: "jndi_injection", substrings: []string{"Security Issue: Remote JNDI Lookup"}},
+	{id: "lock_order_inversion", substrings: []string{"lock-order-inversion"}},
+	{id: "misaligned_address", regexs: []*regexp.Regexp{regexp.MustCompile(`undefined behaviou?r: .*misaligned address`)}},
+	{id: "missing_return", substrings: []string{"execution reached the end of a value-returning function without returning a value"}},
+	{id: "mutex_misuse", regexs: []*regexp.Regexp{regexp.MustCompile(`(unlock of an unlocked|double lock of a|destroy of a locked|read lock of a write locked|read unlock of a write locked) mutex`)}},
+	{id: "memory_param_overlap", regexs: []*regexp.Regexp{regexp.MustCompile(`\w+-param-overlap`)}},
 	{
 		id:         "out_of_bounds",
 		substrings: []string{"java.lang.ArrayIndexOutOfBoundsException"},
@@ -46,24 +73,46 @@ var matchers = []matcher{
 	{id: "load_arbitrary_library", substrings: []string{"Security Issue: load arbitrary library"}},
 	{id: "memory_leak", substrings: []string{"detected memory leaks"}},
 	{id: "negative_array_size", substrings: []string{"java.lang.NegativeArraySizeException"}},
-	{id: "null_pointer", substrings: []string{"java.lang.NullPointerException"}},
+	{id: "negative_size_param", substrings: []string{"negative-size-param"}},
+	{
+		id:         "null_pointer",
+		substrings: []string{"java.lang.NullPointerException"},
+		regexs: []*regexp.Regexp{
+			regexp.MustCompile(`undefined behaviou?r: (load of|store to|member access within|member call on|reference binding to) null pointer`),
+		},
+	},
 	{id: "number_format", substrings: []string{"java.lang.NumberFormatException"}},
 	{id: "os_command_injection", substrings: []string{"Security Issue: OS Command Injection"}},
-	{id: "out_of_memory", substrings: []string{"out-of-memory"}},
+	{id: "out_of_memory", substrings: []string{"out-of-memory", "java.lang.OutOfMemoryError"}},
+	{
+		id:     "pointer_overflow",
+		regexs: []*regexp.Regexp{regexp.MustCompile(`undefined behaviou?r: (pointer index expression with base|applying (non-)?zero offset|addition of unsigned offset|subtraction of unsigned offset)`)},
+	},
 	{id: "regex_injection", substrings: []string{"Security Issue: Regular Expression Injection"}},
 	{id: "remote_code_execution", substrings: []string{"Security Issue: Remote Code Execution"}},
+	{id: "script_engine_injection", substrings: []string{"Security Issue: Script Engine Injection"}},
 	{id: "segmentation_fault", substrings: []string{"SEGV on unknown address"}},
-	{id: "signed_integer_overflow", substrings: []string{"undefined behavior: signed integer overflow"}},
+	{id: "server_side_request_forgery", substrings: []string{"Security Issue: Server Side Request Forgery"}},
+	{
+		id:         "signed_integer_overflow",
+		substrings: []string{"undefined behavior: signed integer overflow"},
+		regexs:     []*regexp.Regexp{regexp.MustCompile(`undefined behaviou?r: negation of -?\d+ cannot be represented`)},
+	},
+	{id: "signal_unsafe_call", substrings: []string{"signal-unsafe call inside of a signal"}},
 	{id: "slow_input", substrings: []string{"Slow input detected. Processing time:"}},
 	{id: "stack_buffer_overflow", substrings: []string{"stack-buffer-overflow on address"}},
-	{id: "stack_exhaustion", substrings: []string{"stack-overflow on address"}},
+	{id: "stack_exhaustion", substrings: []string{"stack-overflow on address", "java.lang.StackOverflowError"}},
 	{id: "sql_injection", substrings: []string{"Security Issue: SQL Injection"}},
+	{id: "thread_leak", substrings: []string{"thread leak"}},
 	{
 		id:         "timeout",
 		substrings: []string{"timeout"},
 		regexs:     []*regexp.Regexp{regexp.MustCompile(`timeout after \d+ \w+`)},
 	},
 	{id: "shift_exponent", regexs: []*regexp.Regexp{regexp.MustCompile(`undefined behaviou?r: shift exponent.+`)}},
+	{id: "unreachable_code", substrings: []string{"execution reached an unreachable program point"}},
+	{id: "unsigned_integer_overflow", substrings: []string{"undefined behavior: unsigned integer overflow"}},
+	{id: "use_after_destroy", substrings: []string{"use-after-destroy"}},
 	{id: "use_after_return", substrings: []string{"stack-use-after-return on address"}},
 	{id: "use_after_scope", substrings: []string{"stack-use-after-scope on address"}},
 	{id: "use_of_uninitialized_value", substrings: []string{"use-of-uninitialized-value"}},
@@ -73,12 +122,16 @@ var matchers = []matcher{
 	{id: "jazzer_security_issue", substrings: []string{"Security Issue:"}},
 }
 
+// ForFinding returns the ID of the first built-in matcher which matches
+// the details of the finding, or an empty string if none matches. The
+// user matchers (see UserMatchers) are applied later, so it's up to the
+// caller to warn if no error ID could be determined at all.
 func ForFinding(f *finding.Finding) string {
 	for _, m := range matchers {
 		if m.Match(f.Details) {
 			return m.id
 		}
 	}
-	log.Warnf("unable to find matching error id for given finding: %s", f.Details)
+	log.Debugf("unable to find matching built-in error id for given finding: %s", f.Details)
 	return ""
 }
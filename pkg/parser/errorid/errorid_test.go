@@ -31,6 +31,26 @@ func TestForFinding(t *testing.T) {
 		{id: "timeout", f: &finding.Finding{Details: "timeout after 30 seconds"}},
 		{id: "use_of_uninitialized_value", f: &finding.Finding{Details: "use-of-uninitialized-value"}},
 
+		{id: "allocation_size_too_big", f: &finding.Finding{Details: "requested allocation size 0xffffffffffffffff exceeds maximum supported size of 0x10000000000"}},
+		{id: "container_overflow", f: &finding.Finding{Details: "container-overflow on address 0x603000000058 at pc 0x0000004f8b34"}},
+		{id: "data_race", f: &finding.Finding{Details: "data race (pid=12345)"}},
+		{id: "division_by_zero", f: &finding.Finding{Details: "undefined behavior: division by zero"}},
+		{id: "division_by_zero", f: &finding.Finding{Details: "java.lang.ArithmeticException: / by zero"}},
+		{id: "file_path_traversal", f: &finding.Finding{Details: "Security Issue: File path traversal: ../../etc/passwd"}},
+		{id: "heap_use_after_free", f: &finding.Finding{Details: "heap-use-after-free (pid=12345)"}},
+		{id: "lock_order_inversion", f: &finding.Finding{Details: "lock-order-inversion (potential deadlock) (pid=12345)"}},
+		{id: "memory_param_overlap", f: &finding.Finding{Details: "memcpy-param-overlap: memory ranges [0x6020000000f0,0x6020000000f4) and [0x6020000000f2, 0x6020000000f6) overlap"}},
+		{id: "misaligned_address", f: &finding.Finding{Details: "undefined behavior: load of misaligned address 0x000001d3c0a1 for type 'int', which requires 4 byte alignment"}},
+		{id: "mutex_misuse", f: &finding.Finding{Details: "unlock of an unlocked mutex (or by a wrong thread) (pid=12345)"}},
+		{id: "null_pointer", f: &finding.Finding{Details: "undefined behavior: member access within null pointer of type 'struct Foo'"}},
+		{id: "pointer_overflow", f: &finding.Finding{Details: "undefined behavior: applying non-zero offset 8 to null pointer"}},
+		{id: "server_side_request_forgery", f: &finding.Finding{Details: "Security Issue: Server Side Request Forgery (SSRF)"}},
+		{id: "signed_integer_overflow", f: &finding.Finding{Details: "undefined behavior: negation of -2147483648 cannot be represented in type 'int'"}},
+		{id: "stack_exhaustion", f: &finding.Finding{Details: "java.lang.StackOverflowError"}},
+		{id: "thread_leak", f: &finding.Finding{Details: "thread leak (pid=12345)"}},
+		{id: "unsigned_integer_overflow", f: &finding.Finding{Details: "undefined behavior: unsigned integer overflow"}},
+		{id: "use_after_destroy", f: &finding.Finding{Details: "use-after-destroy"}},
+
 		{f: &finding.Finding{Details: "Security Issue: FooBar"}, id: "jazzer_security_issue"},
 	}
 
@@ -40,3 +60,12 @@ func TestForFinding(t *testing.T) {
 		})
 	}
 }
+
+// All error IDs should have error details which are shipped with
+// cifuzz, so that findings are described even without a connection to
+// the server
+func TestLocalErrorDetailsExistForAllIDs(t *testing.T) {
+	for _, m := range matchers {
+		assert.NotNil(t, finding.LocalErrorDetailsByID(m.id), m.id)
+	}
+}
@@ -0,0 +1,113 @@
+package errorid
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"code-intelligence.com/cifuzz/pkg/finding"
+)
+
+// UserMatcher is an error ID matcher which is defined via the
+// "error-ids" setting in cifuzz.yaml, to classify findings which the
+// built-in matchers don't cover, e.g. failures of in-house assertion
+// macros or crashes reported by custom crash handlers.
+type UserMatcher struct {
+	ID string `mapstructure:"id"`
+	// A human-readable name of the error, defaults to the ID
+	Name       string   `mapstructure:"name"`
+	Substrings []string `mapstructure:"substrings"`
+	Regexes    []string `mapstructure:"regexes"`
+	// The severity score (0-10) of the error
+	Severity float32 `mapstructure:"severity"`
+	// The ID of the CWE entry of the error
+	CWE int64 `mapstructure:"cwe"`
+
+	matcher *matcher
+}
+
+// UserMatchers are the error ID matchers defined in cifuzz.yaml. They
+// take precedence over the built-in matchers.
+type UserMatchers []*UserMatcher
+
+// Validate checks the matchers and compiles their regular expressions.
+func (ms UserMatchers) Validate() error {
+	for i, m := range ms {
+		if m.ID == "" {
+			return errors.Errorf("Error ID matcher #%d has no id", i+1)
+		}
+		if len(m.Substrings) == 0 && len(m.Regexes) == 0 {
+			return errors.Errorf("Error ID matcher %q needs at least one substring or regex", m.ID)
+		}
+		if m.Severity < 0 || m.Severity > 10 {
+			return errors.Errorf("Invalid severity %.1f of error ID matcher %q, must be between 0 and 10", m.Severity, m.ID)
+		}
+		if m.CWE < 0 {
+			return errors.Errorf("Invalid CWE %d of error ID matcher %q", m.CWE, m.ID)
+		}
+
+		m.matcher = &matcher{id: m.ID, substrings: m.Substrings}
+		for _, r := range m.Regexes {
+			regex, err := regexp.Compile(r)
+			if err != nil {
+				return errors.Wrapf(err, "Invalid regex %q of error ID matcher %q", r, m.ID)
+			}
+			m.matcher.regexs = append(m.matcher.regexs, regex)
+		}
+	}
+	return nil
+}
+
+// ErrorDetails returns the error details of the first matcher which
+// matches the details or the logs of the finding, or nil if none
+// matches. Validate must be called before.
+func (ms UserMatchers) ErrorDetails(f *finding.Finding) *finding.ErrorDetails {
+	for _, m := range ms {
+		if m.matches(f) {
+			return m.errorDetails()
+		}
+	}
+	return nil
+}
+
+// matches returns true if the finding's details or logs match. Unlike
+// the built-in matchers, user matchers are also applied to the logs,
+// because messages of custom crash handlers usually don't end up in the
+// details of the finding.
+func (m *UserMatcher) matches(f *finding.Finding) bool {
+	if m.matcher.Match(f.Details) {
+		return true
+	}
+	for _, line := range f.Logs {
+		if m.matcher.Match(line) {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *UserMatcher) errorDetails() *finding.ErrorDetails {
+	d := &finding.ErrorDetails{
+		ID:   m.ID,
+		Name: m.Name,
+	}
+	if d.Name == "" {
+		d.Name = strings.ReplaceAll(m.ID, "_", " ")
+	}
+	if m.Severity > 0 {
+		d.Severity = &finding.Severity{
+			Score: m.Severity,
+			Level: finding.SeverityLevelForScore(m.Severity),
+		}
+	}
+	if m.CWE > 0 {
+		d.CweDetails = &finding.ExternalDetail{ID: m.CWE}
+		d.Links = []finding.Link{{
+			Description: fmt.Sprintf("CWE-%d", m.CWE),
+			URL:         fmt.Sprintf("https://cwe.mitre.org/data/definitions/%d.html", m.CWE),
+		}}
+	}
+	return d
+}
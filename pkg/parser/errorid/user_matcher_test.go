@@ -0,0 +1,48 @@
+package errorid
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"code-intelligence.com/cifuzz/pkg/finding"
+)
+
+func TestUserMatchers(t *testing.T) {
+	matchers := UserMatchers{
+		{ID: "assertion_failure", Name: "Assertion Failure", Substrings: []string{"MY_ASSERT failed"}, Severity: 5.5, CWE: 617},
+		{ID: "custom_crash", Regexes: []string{`^CRASH HANDLER: signal \d+`}},
+	}
+	require.NoError(t, matchers.Validate())
+
+	// The message of the assertion macro is only part of the logs
+	f := &finding.Finding{
+		Details: "deadly signal",
+		Logs:    []string{"MY_ASSERT failed: size > 0", "==1== ERROR: libFuzzer: deadly signal"},
+	}
+	require.Equal(t, &finding.ErrorDetails{
+		ID:         "assertion_failure",
+		Name:       "Assertion Failure",
+		Severity:   &finding.Severity{Score: 5.5, Level: finding.SeverityLevelMedium},
+		CweDetails: &finding.ExternalDetail{ID: 617},
+		Links:      []finding.Link{{Description: "CWE-617", URL: "https://cwe.mitre.org/data/definitions/617.html"}},
+	}, matchers.ErrorDetails(f))
+
+	f = &finding.Finding{Details: "deadly signal", Logs: []string{"CRASH HANDLER: signal 11"}}
+	require.Equal(t, &finding.ErrorDetails{ID: "custom_crash", Name: "custom crash"}, matchers.ErrorDetails(f))
+
+	f = &finding.Finding{Details: "deadly signal"}
+	require.Nil(t, matchers.ErrorDetails(f))
+}
+
+func TestUserMatchers_Validate(t *testing.T) {
+	invalid := []*UserMatcher{
+		{Substrings: []string{"foo"}},
+		{ID: "no_patterns"},
+		{ID: "invalid_regex", Regexes: []string{"("}},
+		{ID: "invalid_severity", Substrings: []string{"foo"}, Severity: 11},
+	}
+	for _, m := range invalid {
+		require.Error(t, UserMatchers{m}.Validate(), m.ID)
+	}
+}
@@ -21,6 +21,7 @@ import (
 	"code-intelligence.com/cifuzz/pkg/parser/sanitizer"
 	"code-intelligence.com/cifuzz/pkg/report"
 	"code-intelligence.com/cifuzz/util/regexutil"
+	"code-intelligence.com/cifuzz/util/sliceutil"
 )
 
 var (
@@ -53,9 +54,15 @@ var (
 		`Test unit written to\s*(?P<test_input_file>.*)`)
 	slowInputPattern = regexp.MustCompile(
 		`\s*Slowest unit: (?P<duration>\d+) s.*`)
-	goPanicPattern = regexp.MustCompile(`^panic:\s+\S+`)
+	goPanicPattern         = regexp.MustCompile(`^panic:\s+\S+`)
+	rustPanicPattern       = regexp.MustCompile(`^thread '.*' panicked at`)
+	pythonExceptionPattern = regexp.MustCompile(`^\s*=== Uncaught Python exception: ===`)
 )
 
+// The details of findings which are reported by the runtime of the
+// fuzz test's language before libFuzzer reports the resulting crash
+var runtimeErrorDetails = []string{"Go Panic", "Rust Panic", "Python Exception"}
+
 var errNotFound = errors.New("not found")
 
 type parser struct {
@@ -215,7 +222,7 @@ func (p *parser) parseLine(ctx context.Context, line string) error {
 
 	finding := p.parseAsNewFinding(line)
 
-	if finding != nil && !p.libFuzzerErrorFollowingGoPanic(finding) {
+	if finding != nil && !p.libFuzzerErrorFollowingRuntimeError(finding) {
 		// If there is still a pending finding, send it now, because
 		// we'll treat all further output lines as belonging to the new
 		// finding.
@@ -270,7 +277,7 @@ func (p *parser) parseAsNewFinding(line string) *finding.Finding {
 		}
 	}
 
-	finding := p.parseAsGoFinding(line)
+	finding := p.parseAsRuntimeFinding(line)
 	if finding != nil {
 		return finding
 	}
@@ -301,19 +308,29 @@ func parseAsTestInputFilePath(logLine string) (string, bool) {
 	return "", false
 }
 
-func (p *parser) parseAsGoFinding(line string) *finding.Finding {
-	if _, found := regexutil.FindNamedGroupsMatch(goPanicPattern, line); found {
-		return &finding.Finding{
-			Type:    finding.ErrorTypeCrash,
-			Details: "Go Panic",
-			Logs:    []string{line},
+// parseAsRuntimeFinding parses Go panics, Rust panics and uncaught
+// Python exceptions
+func (p *parser) parseAsRuntimeFinding(line string) *finding.Finding {
+	patterns := []*regexp.Regexp{goPanicPattern, rustPanicPattern, pythonExceptionPattern}
+	for i, pattern := range patterns {
+		if pattern.MatchString(line) {
+			return &finding.Finding{
+				Type:    finding.ErrorTypeCrash,
+				Details: runtimeErrorDetails[i],
+				Logs:    []string{line},
+			}
 		}
 	}
 	return nil
 }
 
-func (p *parser) libFuzzerErrorFollowingGoPanic(report *finding.Finding) bool {
-	return p.pendingFinding.GetDetails() == "Go Panic" && report.GetDetails() != "Go Panic"
+// libFuzzerErrorFollowingRuntimeError returns true if the finding is the
+// crash which libFuzzer reports after the runtime of the fuzz test's
+// language reported an error, e.g. a Go panic. The logs of that crash
+// belong to the pending finding.
+func (p *parser) libFuzzerErrorFollowingRuntimeError(report *finding.Finding) bool {
+	return sliceutil.Contains(runtimeErrorDetails, p.pendingFinding.GetDetails()) &&
+		!sliceutil.Contains(runtimeErrorDetails, report.GetDetails())
 }
 
 func (p *parser) parseAsLibfuzzerFinding(line string) *finding.Finding {
@@ -519,10 +536,17 @@ func (p *parser) finalizeAndSendPendingFinding(ctx context.Context) error {
 		ProjectDir:    p.ProjectDir,
 		SupportJazzer: p.SupportJazzer,
 	}
-	p.pendingFinding.StackTrace, err = stacktrace.NewParser(parserOpts).Parse(p.pendingFinding.Logs)
+	stackTraceParser := stacktrace.NewParser(parserOpts)
+	p.pendingFinding.StackTrace, err = stackTraceParser.Parse(p.pendingFinding.Logs)
 	if err != nil {
 		return err
 	}
+	if p.SupportJazzer {
+		p.pendingFinding.Causes, err = stackTraceParser.ParseCauses(p.pendingFinding.Logs)
+		if err != nil {
+			return err
+		}
+	}
 
 	p.pendingFinding.MoreDetails = &finding.ErrorDetails{
 		ID: errorid.ForFinding(p.pendingFinding),
@@ -446,6 +446,10 @@ Base64: UVFcb1w8L1xzY3JpcHQt`,
 							fmt.Sprintf("artifact_prefix='./'; Test unit written to %s", testInputFile.Name()),
 							"Base64: UVFcb1w8L1xzY3JpcHQt",
 						},
+						Causes: []*stacktrace.ExceptionCause{{
+							Exception: "com.code_intelligence.jazzer.api.FuzzerSecurityIssueHigh",
+							Message:   "Output contains </script",
+						}},
 					},
 				},
 			},
@@ -738,6 +742,58 @@ SUMMARY: libFuzzer: timeout`,
 				},
 			},
 		},
+		{
+			name: "rust panic",
+			logs: `INFO: A corpus is not provided, starting from an empty corpus
+thread '<unnamed>' panicked at src/lib.rs:10:5:
+index out of bounds: the len is 3 but the index is 5
+stack backtrace:
+   0: rust_begin_unwind
+             at /rustc/90c5418/library/std/src/panicking.rs:578:5
+   1: parser::parse::h0123456789abcdef
+             at ./src/lib.rs:10:5
+   2: fuzz_parse::_::__libfuzzer_sys_run
+             at ./fuzz/fuzz_targets/fuzz_parse.rs:7:5
+==3336601== ERROR: libFuzzer: deadly signal`,
+			expected: []*report.Report{
+				{Status: report.RunStatusInitializing},
+				{
+					Status: report.RunStatusRunning,
+					Finding: &finding.Finding{
+						Type:    finding.ErrorTypeCrash,
+						Details: "Rust Panic",
+						Logs: []string{
+							"thread '<unnamed>' panicked at src/lib.rs:10:5:",
+							"index out of bounds: the len is 3 but the index is 5",
+							"stack backtrace:",
+							"   0: rust_begin_unwind",
+							"             at /rustc/90c5418/library/std/src/panicking.rs:578:5",
+							"   1: parser::parse::h0123456789abcdef",
+							"             at ./src/lib.rs:10:5",
+							"   2: fuzz_parse::_::__libfuzzer_sys_run",
+							"             at ./fuzz/fuzz_targets/fuzz_parse.rs:7:5",
+							"==3336601== ERROR: libFuzzer: deadly signal",
+						},
+						StackTrace: []*stacktrace.StackFrame{
+							{
+								SourceFile:  "src/lib.rs",
+								Line:        10,
+								Column:      5,
+								FrameNumber: 1,
+								Function:    "parser::parse",
+							},
+							{
+								SourceFile:  "fuzz/fuzz_targets/fuzz_parse.rs",
+								Line:        7,
+								Column:      5,
+								FrameNumber: 2,
+								Function:    "fuzz_parse::_::__libfuzzer_sys_run",
+							},
+						},
+					},
+				},
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
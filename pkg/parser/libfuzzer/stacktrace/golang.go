@@ -0,0 +1,62 @@
+package stacktrace
+
+import (
+	"regexp"
+	"strings"
+
+	"code-intelligence.com/cifuzz/util/regexutil"
+)
+
+// Go prints a stack trace for each goroutine when it panics. Each frame
+// consists of two lines, the function with its arguments and the
+// source location, e.g.
+//
+//	goroutine 17 [running, locked to thread]:
+//	example.com/parser.Parse({0xc000012345, 0x3, 0x3})
+//		/home/user/parser/parser.go:12 +0x1d
+var (
+	goFunctionPattern = regexp.MustCompile(`^(?P<function>\S+)\([^()]*\)$`)
+	goLocationPattern = regexp.MustCompile(`^\s+(?P<source_file>\S+\.go):(?P<line>\d+)(\s+\+0x[0-9a-fA-F]+)?$`)
+)
+
+// parseGoStackTrace parses the stack trace of the first goroutine
+// printed in the logs, which is the one that panicked
+func (p *parser) parseGoStackTrace(logs []string) ([]*StackFrame, error) {
+	var frames []*StackFrame
+	inGoroutine := false
+	function := ""
+	var frameNumber uint32
+	for _, line := range logs {
+		if !inGoroutine {
+			inGoroutine = goroutinePattern.MatchString(line)
+			continue
+		}
+
+		// The stack trace ends with an empty line. The "created by"
+		// frame belongs to the goroutine which started this one, which
+		// is not relevant for the panic.
+		if strings.TrimSpace(line) == "" || strings.HasPrefix(line, "created by ") {
+			break
+		}
+
+		if matches, found := regexutil.FindNamedGroupsMatch(goFunctionPattern, line); found {
+			function = matches["function"]
+			continue
+		}
+
+		matches, found := regexutil.FindNamedGroupsMatch(goLocationPattern, line)
+		if !found || function == "" {
+			continue
+		}
+		frame, err := p.newStackFrame(matches["source_file"], function, frameNumber, matches["line"], "")
+		if err != nil {
+			return nil, err
+		}
+		frameNumber++
+		function = ""
+		if frame != nil {
+			frames = append(frames, frame)
+		}
+	}
+	return frames, nil
+}
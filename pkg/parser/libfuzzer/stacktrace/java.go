@@ -0,0 +1,128 @@
+package stacktrace
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"code-intelligence.com/cifuzz/util/regexutil"
+)
+
+// If an exception was caused by another exception, Java prints the
+// stack trace of the cause after the one of the exception, e.g.
+//
+//	== Java Exception: java.lang.RuntimeException: Failed to parse
+//		at com.example.Parser.parse(Parser.java:20)
+//		at com.example.FuzzTest.fuzzerTestOneInput(FuzzTest.java:10)
+//	Caused by: java.lang.IllegalStateException: Unexpected token
+//		at com.example.Lexer.next(Lexer.java:5)
+//		at com.example.Parser.parse(Parser.java:18)
+//		... 1 more
+//
+// The "... 1 more" line means that the remaining frames are the same
+// as the last frame of the enclosing stack trace.
+var (
+	javaCausePattern       = regexp.MustCompile(`^\s*Caused by: (?P<exception>[^\s:]+)(: (?P<message>.*))?$`)
+	javaCommonFramePattern = regexp.MustCompile(`^\s*\.\.\. (?P<num_frames>\d+) more$`)
+	javaFrameLinePattern   = regexp.MustCompile(`^\s*at \S+`)
+)
+
+// ExceptionCause is a segment of a Java stack trace which belongs to an
+// exception that caused the reported exception
+type ExceptionCause struct {
+	Exception  string        `json:"exception"`
+	Message    string        `json:"message,omitempty"`
+	StackTrace []*StackFrame `json:"stack_trace,omitempty"`
+}
+
+// ParseCauses parses the "Caused by:" segments of a Java stack trace.
+// The causes are returned in the order in which they are printed, so
+// the last one is the root cause.
+func (p *parser) ParseCauses(logs []string) ([]*ExceptionCause, error) {
+	var causes []*ExceptionCause
+
+	// All frames of the current segment, including those which are not
+	// part of the project (which are nil), to resolve the "... n more"
+	// lines of the following segment
+	var enclosingFrames, frames []*StackFrame
+	var cause *ExceptionCause
+	inStackTrace := false
+
+	finishSegment := func() {
+		if cause != nil {
+			for _, frame := range frames {
+				if frame != nil {
+					cause.StackTrace = append(cause.StackTrace, frame)
+				}
+			}
+			causes = append(causes, cause)
+		}
+		enclosingFrames = frames
+		frames = nil
+		cause = nil
+	}
+
+	for _, line := range logs {
+		if matches, found := regexutil.FindNamedGroupsMatch(javaCausePattern, line); found {
+			finishSegment()
+			cause = &ExceptionCause{
+				Exception: matches["exception"],
+				Message:   strings.TrimSpace(matches["message"]),
+			}
+			inStackTrace = true
+			continue
+		}
+
+		if matches, found := regexutil.FindNamedGroupsMatch(javaCommonFramePattern, line); found {
+			n, err := strconv.Atoi(matches["num_frames"])
+			if err != nil {
+				return nil, errors.WithStack(err)
+			}
+			if n > len(enclosingFrames) {
+				n = len(enclosingFrames)
+			}
+			for _, frame := range enclosingFrames[len(enclosingFrames)-n:] {
+				if frame != nil {
+					// Copy the frame to set the frame number of
+					// this segment
+					f := *frame
+					f.FrameNumber = uint32(len(frames))
+					frame = &f
+				}
+				frames = append(frames, frame)
+			}
+			continue
+		}
+
+		if javaFrameLinePattern.MatchString(line) {
+			if !inStackTrace {
+				// This is the first frame of the stack trace of the
+				// reported exception
+				inStackTrace = true
+				frames = nil
+			}
+			matches, found := regexutil.FindNamedGroupsMatch(framePatternJava, line)
+			var frame *StackFrame
+			if found {
+				var err error
+				frame, err = p.newStackFrame(matches["source_file"], matches["function"], uint32(len(frames)), matches["line"], "")
+				if err != nil {
+					return nil, err
+				}
+			}
+			frames = append(frames, frame)
+			continue
+		}
+
+		if cause != nil {
+			// The stack trace of the last cause ends with the first
+			// line which doesn't belong to it
+			break
+		}
+	}
+	finishSegment()
+
+	return causes, nil
+}
@@ -0,0 +1,50 @@
+package stacktrace
+
+import (
+	"regexp"
+
+	"code-intelligence.com/cifuzz/util/regexutil"
+)
+
+// Python prints the stack trace of an uncaught exception with the most
+// recent call last, e.g.
+//
+//	Traceback (most recent call last):
+//	  File "/home/user/parser/fuzz_parser.py", line 10, in TestOneInput
+//	    parse(data)
+//	  File "/home/user/parser/parser.py", line 5, in parse
+//	    raise ValueError("invalid input")
+//	ValueError: invalid input
+var pythonFramePattern = regexp.MustCompile(`^\s*File "(?P<source_file>[^"]+)", line (?P<line>\d+), in (?P<function>\S+)`)
+
+// parsePythonStackTrace parses the last traceback printed in the logs.
+// If an exception was raised while handling another exception, Python
+// prints the traceback of the exception which was not caught last.
+func (p *parser) parsePythonStackTrace(logs []string) ([]*StackFrame, error) {
+	var traceback []map[string]string
+	for _, line := range logs {
+		if pythonTracebackPattern.MatchString(line) {
+			traceback = nil
+			continue
+		}
+		if matches, found := regexutil.FindNamedGroupsMatch(pythonFramePattern, line); found {
+			traceback = append(traceback, matches)
+		}
+	}
+
+	// Reverse the order of the frames, so that the most recent call is
+	// the first frame, like in the stack traces of other languages
+	var frames []*StackFrame
+	for i := len(traceback) - 1; i >= 0; i-- {
+		matches := traceback[i]
+		frameNumber := uint32(len(traceback) - 1 - i)
+		frame, err := p.newStackFrame(matches["source_file"], matches["function"], frameNumber, matches["line"], "")
+		if err != nil {
+			return nil, err
+		}
+		if frame != nil {
+			frames = append(frames, frame)
+		}
+	}
+	return frames, nil
+}
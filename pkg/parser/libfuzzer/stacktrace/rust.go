@@ -0,0 +1,86 @@
+package stacktrace
+
+import (
+	"regexp"
+	"strconv"
+
+	"github.com/pkg/errors"
+
+	"code-intelligence.com/cifuzz/util/regexutil"
+)
+
+// Rust prints the stack trace of a panic if RUST_BACKTRACE is set. Each
+// frame consists of the function and, if available, a line with the
+// source location, e.g.
+//
+//	thread '<unnamed>' panicked at src/lib.rs:10:5:
+//	index out of bounds: the len is 3 but the index is 5
+//	stack backtrace:
+//	   0: rust_begin_unwind
+//	             at /rustc/90c5418/library/std/src/panicking.rs:578:5
+//	   1: parser::parse
+//	             at ./src/lib.rs:10:5
+var (
+	rustFramePattern    = regexp.MustCompile(`^\s*(?P<frame_number>\d+):\s+(0x[0-9a-fA-F]+ - )?(?P<function>\S+)`)
+	rustLocationPattern = regexp.MustCompile(`^\s+at (?P<source_file>\S+?):(?P<line>\d+)(:(?P<column>\d+))?$`)
+	// Older versions of Rust print the message before the location
+	rustPanicLocationPattern = regexp.MustCompile(
+		`panicked at (.*', )?(?P<source_file>[^\s']+?):(?P<line>\d+):(?P<column>\d+):?$`)
+	// The hash which is appended to mangled function names
+	rustHashSuffixPattern = regexp.MustCompile(`::h[0-9a-f]{16}$`)
+)
+
+// parseRustStackTrace parses the stack trace of a Rust panic. If the
+// logs don't contain a stack trace, the location of the panic is used
+// as a single frame stack trace.
+func (p *parser) parseRustStackTrace(logs []string) ([]*StackFrame, error) {
+	var frames []*StackFrame
+	var panicFrame *StackFrame
+	var function string
+	var frameNumber uint32
+	for _, line := range logs {
+		if panicFrame == nil && rustPanicPattern.MatchString(line) {
+			matches, found := regexutil.FindNamedGroupsMatch(rustPanicLocationPattern, line)
+			if found {
+				frame, err := p.newStackFrame(matches["source_file"], "", 0, matches["line"], matches["column"])
+				if err != nil {
+					return nil, err
+				}
+				panicFrame = frame
+			}
+			continue
+		}
+
+		if matches, found := regexutil.FindNamedGroupsMatch(rustFramePattern, line); found {
+			n, err := strconv.ParseUint(matches["frame_number"], 10, 32)
+			if err != nil {
+				return nil, errors.WithStack(err)
+			}
+			if len(frames) > 0 && uint32(n) <= frames[len(frames)-1].FrameNumber {
+				// This is probably the stack trace of another panic
+				break
+			}
+			frameNumber = uint32(n)
+			function = rustHashSuffixPattern.ReplaceAllString(matches["function"], "")
+			continue
+		}
+
+		matches, found := regexutil.FindNamedGroupsMatch(rustLocationPattern, line)
+		if !found || function == "" {
+			continue
+		}
+		frame, err := p.newStackFrame(matches["source_file"], function, frameNumber, matches["line"], matches["column"])
+		if err != nil {
+			return nil, err
+		}
+		function = ""
+		if frame != nil {
+			frames = append(frames, frame)
+		}
+	}
+
+	if len(frames) == 0 && panicFrame != nil {
+		return []*StackFrame{panicFrame}, nil
+	}
+	return frames, nil
+}
@@ -1,6 +1,7 @@
 package stacktrace
 
 import (
+	"fmt"
 	"path/filepath"
 	"regexp"
 	"strconv"
@@ -33,9 +34,42 @@ type StackFrame struct {
 	Function    string
 }
 
+// Location returns the source location of the stack frame in the
+// format <source file>:<line>[:<column>].
+func (f *StackFrame) Location() string {
+	// in some cases ASan/Libfuzzer do not include the column in the stack trace
+	if f.Column != 0 {
+		return fmt.Sprintf("%s:%d:%d", f.SourceFile, f.Line, f.Column)
+	}
+	return fmt.Sprintf("%s:%d", f.SourceFile, f.Line)
+}
+
+// Language determines the format of the stack traces which are parsed
+type Language string
+
+const (
+	// LanguageUnknown lets the parser detect the language from the logs
+	LanguageUnknown Language = ""
+	// LanguageCPP is used for the stack traces printed by the
+	// sanitizers and by Jazzer
+	LanguageCPP    Language = "c++"
+	LanguageGo     Language = "go"
+	LanguageRust   Language = "rust"
+	LanguagePython Language = "python"
+)
+
+var (
+	goroutinePattern       = regexp.MustCompile(`^goroutine \d+ \[`)
+	rustPanicPattern       = regexp.MustCompile(`^thread '.*' panicked at`)
+	pythonTracebackPattern = regexp.MustCompile(`^\s*Traceback \(most recent call last\):`)
+)
+
 type ParserOptions struct {
 	ProjectDir    string
 	SupportJazzer bool
+	// The language of the fuzz test. If not set, it's detected from
+	// the logs.
+	Language Language
 }
 
 type parser struct {
@@ -49,7 +83,32 @@ func NewParser(opts *ParserOptions) *parser {
 // Parse parses output from an error reported by libFuzzer or a sanitizer
 // and returns a stack trace if one is found in the error report.
 func (p *parser) Parse(logs []string) ([]*StackFrame, error) {
-	trace, err := p.parseStackTrace(logs)
+	language := p.Language
+	if language == LanguageUnknown {
+		language = DetectLanguage(logs)
+	}
+
+	var trace []*StackFrame
+	var err error
+	switch language {
+	case LanguageGo:
+		trace, err = p.parseGoStackTrace(logs)
+	case LanguageRust:
+		trace, err = p.parseRustStackTrace(logs)
+	case LanguagePython:
+		trace, err = p.parsePythonStackTrace(logs)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if trace != nil {
+		return trace, nil
+	}
+
+	// Findings of fuzz tests in other languages can still contain a
+	// stack trace printed by a sanitizer, e.g. if the crash occurred in
+	// native code, so we fall back to parsing those
+	trace, err = p.parseStackTrace(logs)
 	if err != nil {
 		return nil, err
 	}
@@ -67,6 +126,24 @@ func (p *parser) Parse(logs []string) ([]*StackFrame, error) {
 	return p.parseSourceLocation(logs)
 }
 
+// DetectLanguage returns the language of the fuzz test which produced
+// the logs, based on the format of the error report. LanguageCPP is
+// returned if the logs don't contain a Go panic, a Rust panic or a
+// Python traceback.
+func DetectLanguage(logs []string) Language {
+	for _, line := range logs {
+		switch {
+		case goroutinePattern.MatchString(line):
+			return LanguageGo
+		case rustPanicPattern.MatchString(line):
+			return LanguageRust
+		case pythonTracebackPattern.MatchString(line):
+			return LanguagePython
+		}
+	}
+	return LanguageCPP
+}
+
 func (p *parser) parseStackTrace(logs []string) ([]*StackFrame, error) {
 	var frames []*StackFrame
 	for _, line := range logs {
@@ -163,6 +240,37 @@ func (p *parser) stackFrameFromLine(line string) (*StackFrame, error) {
 	}, nil
 }
 
+// newStackFrame creates a stack frame from the parts matched by the
+// frame patterns of the different languages. It returns nil if the
+// source file is not part of the project.
+func (p *parser) newStackFrame(sourceFile, function string, frameNumber uint32, line, column string) (*StackFrame, error) {
+	sourceFile = p.validateSourceFile(filepath.Clean(sourceFile))
+	if sourceFile == "" {
+		return nil, nil
+	}
+
+	lineNumber, err := strconv.ParseUint(line, 10, 32)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	var columnNumber uint64
+	if column != "" {
+		columnNumber, err = strconv.ParseUint(column, 10, 32)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+	}
+
+	return &StackFrame{
+		SourceFile:  filepath.ToSlash(sourceFile),
+		Line:        uint32(lineNumber),
+		Column:      uint32(columnNumber),
+		FrameNumber: frameNumber,
+		Function:    function,
+	}, nil
+}
+
 func (p *parser) validateSourceFile(path string) string {
 	var err error
 
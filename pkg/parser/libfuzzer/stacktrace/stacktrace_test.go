@@ -174,3 +174,124 @@ func TestStackTrace(t *testing.T) {
 		})
 	}
 }
+
+func TestStackTrace_Languages(t *testing.T) {
+	projectDir := os.TempDir()
+	parser := NewParser(&ParserOptions{ProjectDir: projectDir})
+
+	tests := []struct {
+		name               string
+		logs               []string
+		expectedLanguage   Language
+		expectedStackTrace []*StackFrame
+	}{
+		{
+			"go_panic",
+			[]string{
+				"panic: runtime error: index out of range [5] with length 3",
+				"",
+				"goroutine 17 [running, locked to thread]:",
+				"example.com/parser.Parse({0xc000012345, 0x3, 0x3})",
+				fmt.Sprintf("\t%s:12 +0x1d", filepath.Join(projectDir, "parser.go")),
+				"example.com/parser.(*Parser).parseAll(...)",
+				fmt.Sprintf("\t%s:20", filepath.Join(projectDir, "parser.go")),
+				"runtime.goexit()",
+				"\t/usr/local/go/src/runtime/asm_amd64.s:1598 +0x1",
+				"created by example.com/parser.Start",
+				fmt.Sprintf("\t%s:30 +0x2b", filepath.Join(projectDir, "parser.go")),
+			},
+			LanguageGo,
+			[]*StackFrame{
+				{SourceFile: "parser.go", Line: 12, FrameNumber: 0, Function: "example.com/parser.Parse"},
+				{SourceFile: "parser.go", Line: 20, FrameNumber: 1, Function: "example.com/parser.(*Parser).parseAll"},
+			},
+		},
+		{
+			"rust_panic_without_backtrace",
+			[]string{
+				"thread '<unnamed>' panicked at 'index out of bounds: the len is 3 but the index is 5', src/lib.rs:10:5",
+				"note: run with `RUST_BACKTRACE=1` environment variable to display a backtrace",
+			},
+			LanguageRust,
+			[]*StackFrame{{SourceFile: "src/lib.rs", Line: 10, Column: 5}},
+		},
+		{
+			"python_traceback",
+			[]string{
+				" === Uncaught Python exception: ===",
+				"ValueError: invalid input",
+				"Traceback (most recent call last):",
+				fmt.Sprintf("  File \"%s\", line 10, in TestOneInput", filepath.Join(projectDir, "fuzz_parser.py")),
+				"    parse(data)",
+				fmt.Sprintf("  File \"%s\", line 5, in parse", filepath.Join(projectDir, "parser.py")),
+				"    raise ValueError(\"invalid input\")",
+			},
+			LanguagePython,
+			[]*StackFrame{
+				{SourceFile: "parser.py", Line: 5, FrameNumber: 0, Function: "parse"},
+				{SourceFile: "fuzz_parser.py", Line: 10, FrameNumber: 1, Function: "TestOneInput"},
+			},
+		},
+		{
+			"sanitizer_stack_trace",
+			[]string{
+				fmt.Sprintf("    #0 0x530ce7 in DoStuff %s:24:10", filepath.Join(projectDir, "api.cpp")),
+			},
+			LanguageCPP,
+			[]*StackFrame{{SourceFile: "api.cpp", Line: 24, Column: 10, Function: "DoStuff"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.expectedLanguage, DetectLanguage(tt.logs))
+			trace, err := parser.Parse(tt.logs)
+			require.NoError(t, err)
+			require.Equal(t, tt.expectedStackTrace, trace)
+		})
+	}
+}
+
+func TestParseCauses(t *testing.T) {
+	parser := NewParser(&ParserOptions{ProjectDir: os.TempDir(), SupportJazzer: true})
+	logs := []string{
+		"== Java Exception: java.lang.RuntimeException: Failed to parse",
+		"\tat com.example.Parser.parse(Parser.java:20)",
+		"\tat com.example.FuzzTest.fuzzerTestOneInput(FuzzTest.java:10)",
+		"Caused by: java.io.UncheckedIOException: Failed to read",
+		"\tat com.example.Reader.read(Reader.java:7)",
+		"\t... 2 more",
+		"Caused by: java.lang.IllegalStateException",
+		"\tat java.base/java.util.Objects.requireNonNull(Objects.java:208)",
+		"\tat com.example.Lexer.next(Lexer.java:5)",
+		"\t... 3 more",
+		"DEDUP_TOKEN: e943c470c21ef432",
+		"\tat com.example.Unrelated.method(Unrelated.java:1)",
+	}
+
+	causes, err := parser.ParseCauses(logs)
+	require.NoError(t, err)
+	require.Equal(t, []*ExceptionCause{
+		{
+			Exception: "java.io.UncheckedIOException",
+			Message:   "Failed to read",
+			StackTrace: []*StackFrame{
+				{SourceFile: "com.example.Reader", Function: "read", Line: 7, FrameNumber: 0},
+				{SourceFile: "com.example.Parser", Function: "parse", Line: 20, FrameNumber: 1},
+				{SourceFile: "com.example.FuzzTest", Function: "fuzzerTestOneInput", Line: 10, FrameNumber: 2},
+			},
+		},
+		{
+			Exception: "java.lang.IllegalStateException",
+			StackTrace: []*StackFrame{
+				{SourceFile: "com.example.Lexer", Function: "next", Line: 5, FrameNumber: 1},
+				{SourceFile: "com.example.Reader", Function: "read", Line: 7, FrameNumber: 2},
+				{SourceFile: "com.example.Parser", Function: "parse", Line: 20, FrameNumber: 3},
+				{SourceFile: "com.example.FuzzTest", Function: "fuzzerTestOneInput", Line: 10, FrameNumber: 4},
+			},
+		},
+	}, causes)
+
+	causes, err = parser.ParseCauses(logs[:3])
+	require.NoError(t, err)
+	require.Empty(t, causes)
+}
@@ -0,0 +1,127 @@
+package report
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// The run summaries are stored in the build directory, because they
+// only describe the last run of each fuzz test and don't have to be
+// shared with others.
+var runSummariesDir = filepath.Join(".cifuzz-build", "runs")
+
+var unsafeFileNameChars = regexp.MustCompile(`[^A-Za-z0-9._-]`)
+
+// RunSummary contains the metrics of a completed fuzzing run
+type RunSummary struct {
+	FuzzTest      string         `json:"fuzz_test"`
+	StartedAt     time.Time      `json:"started_at"`
+	Duration      time.Duration  `json:"duration"`
+	FirstMetrics  *FuzzingMetric `json:"first_metrics,omitempty"`
+	LastMetrics   *FuzzingMetric `json:"last_metrics,omitempty"`
+	CorpusEntries uint           `json:"corpus_entries"`
+	Findings      []string       `json:"findings,omitempty"`
+}
+
+// AverageExecutionsPerSecond calculates the average number of
+// executions per second between the first and the last metrics of a
+// run.
+func AverageExecutionsPerSecond(first, last *FuzzingMetric) uint64 {
+	if last == nil {
+		return 0
+	}
+	if first == nil {
+		return uint64(last.ExecutionsPerSecond)
+	}
+	metricsDuration := last.Timestamp.Sub(first.Timestamp)
+	if metricsDuration.Milliseconds() == 0 {
+		// The first and last metrics are either the same or were
+		// printed too fast one after the other to calculate a
+		// meaningful average, so we just use the exec/s from the
+		// last metrics as the average.
+		return uint64(last.ExecutionsPerSecond)
+	}
+	// We use milliseconds here to calculate a more accurate average
+	execs := last.TotalExecutions - first.TotalExecutions
+	return uint64(float64(execs) / (float64(metricsDuration.Milliseconds()) / 1000))
+}
+
+// AverageExecutionsPerSecond returns the average number of executions
+// per second of the run.
+func (s *RunSummary) AverageExecutionsPerSecond() uint64 {
+	return AverageExecutionsPerSecond(s.FirstMetrics, s.LastMetrics)
+}
+
+// Save stores the run summary in the project directory, replacing the
+// summary of the previous run of the same fuzz test.
+func (s *RunSummary) Save(projectDir string) error {
+	dir := filepath.Join(projectDir, runSummariesDir)
+	err := os.MkdirAll(dir, 0o755)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	bytes, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	err = os.WriteFile(filepath.Join(dir, runSummaryFileName(s.FuzzTest)), bytes, 0o644)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
+
+// runSummaryFileName returns the name of the file in which the run
+// summary of the fuzz test is stored. Characters which are not safe in
+// file names are replaced, so a short hash of the fuzz test name is
+// appended to keep the names of different fuzz tests, like "a/b" and
+// "a_b", distinct.
+func runSummaryFileName(fuzzTest string) string {
+	hash := sha256.Sum256([]byte(fuzzTest))
+	return unsafeFileNameChars.ReplaceAllString(fuzzTest, "_") + "-" + hex.EncodeToString(hash[:])[:8] + ".json"
+}
+
+// LoadRunSummaries returns the summaries of the last run of each fuzz
+// test in the project, sorted by fuzz test name.
+func LoadRunSummaries(projectDir string) ([]*RunSummary, error) {
+	dir := filepath.Join(projectDir, runSummariesDir)
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return []*RunSummary{}, nil
+	}
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	var res []*RunSummary
+	for _, e := range entries {
+		if e.IsDir() || filepath.Ext(e.Name()) != ".json" {
+			continue
+		}
+		bytes, err := os.ReadFile(filepath.Join(dir, e.Name()))
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		var s RunSummary
+		err = json.Unmarshal(bytes, &s)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		res = append(res, &s)
+	}
+
+	sort.SliceStable(res, func(i, j int) bool {
+		return res[i].FuzzTest < res[j].FuzzTest
+	})
+	return res, nil
+}
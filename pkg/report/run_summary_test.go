@@ -0,0 +1,24 @@
+package report
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunSummary_SaveDoesNotCollide(t *testing.T) {
+	projectDir := t.TempDir()
+
+	for _, fuzzTest := range []string{"a/b", "a_b"} {
+		s := &RunSummary{FuzzTest: fuzzTest, StartedAt: time.Now()}
+		require.NoError(t, s.Save(projectDir))
+	}
+
+	summaries, err := LoadRunSummaries(projectDir)
+	require.NoError(t, err)
+	require.Len(t, summaries, 2)
+	assert.Equal(t, "a/b", summaries[0].FuzzTest)
+	assert.Equal(t, "a_b", summaries[1].FuzzTest)
+}
@@ -116,6 +116,10 @@ func (f RunfilesFinderImpl) DumperSourcePath() (string, error) {
 	return f.findFollowSymlinks("src/dumper.c")
 }
 
+func (f RunfilesFinderImpl) DataProviderHooksSourcePath() (string, error) {
+	return f.findFollowSymlinks("src/DataProviderLoggingHooks.java")
+}
+
 func (f RunfilesFinderImpl) VisualStudioPath() (string, error) {
 	path, found := os.LookupEnv("VSINSTALLDIR")
 	if !found {
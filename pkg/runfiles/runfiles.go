@@ -22,6 +22,7 @@ type RunfilesFinder interface {
 	ProcessWrapperPath() (string, error)
 	ReplayerSourcePath() (string, error)
 	DumperSourcePath() (string, error)
+	DataProviderHooksSourcePath() (string, error)
 	VisualStudioPath() (string, error)
 	VSCodeTasksPath() (string, error)
 	LogoPath() (string, error)
@@ -108,8 +108,14 @@ func (r *Runner) Run(ctx context.Context) error {
 		args = append(args, options.LibFuzzerRSSLimitFlag("3000"))
 	}
 
-	// Add any additional corpus directories as further positional arguments
-	args = append(args, r.SeedCorpusDirs...)
+	if len(r.InputFiles) > 0 {
+		// When passed files instead of directories, Jazzer executes
+		// the fuzz test once with each of them and exits
+		args = append(args, r.InputFiles...)
+	} else {
+		// Add any additional corpus directories as further positional arguments
+		args = append(args, r.SeedCorpusDirs...)
+	}
 
 	// -----------------------------
 	// --- fuzz target arguments ---
@@ -131,10 +137,16 @@ func (r *Runner) Run(ctx context.Context) error {
 	if r.UseMinijail {
 		jazzerArgs := args
 
-		bindings := []*minijail.Binding{
+		var bindings []*minijail.Binding
+
+		if r.GeneratedCorpusDir != "" {
 			// The first corpus directory must be writable, because
 			// libfuzzer writes new test inputs to it
-			{Source: r.GeneratedCorpusDir, Writable: minijail.ReadWrite},
+			bindings = append(bindings, &minijail.Binding{Source: r.GeneratedCorpusDir, Writable: minijail.ReadWrite})
+		}
+
+		for _, file := range r.InputFiles {
+			bindings = append(bindings, &minijail.Binding{Source: file})
 		}
 
 		for _, dir := range r.SeedCorpusDirs {
@@ -44,16 +44,19 @@ type RunnerOptions struct {
 	FuzzTarget         string
 	FuzzTestArgs       []string
 	GeneratedCorpusDir string
-	KeepColor          bool
-	LibraryDirs        []string
-	LogOutput          io.Writer
-	ProjectDir         string
-	ReadOnlyBindings   []string
-	ReportHandler      report.Handler
-	SeedCorpusDirs     []string
-	Timeout            time.Duration
-	UseMinijail        bool
-	Verbose            bool
+	// If set, the fuzz test is not fuzzed but only executed once with
+	// each of these inputs. The corpus directories are ignored then.
+	InputFiles       []string
+	KeepColor        bool
+	LibraryDirs      []string
+	LogOutput        io.Writer
+	ProjectDir       string
+	ReadOnlyBindings []string
+	ReportHandler    report.Handler
+	SeedCorpusDirs   []string
+	Timeout          time.Duration
+	UseMinijail      bool
+	Verbose          bool
 }
 
 func (options *RunnerOptions) ValidateOptions() error {
@@ -118,11 +121,17 @@ func (r *Runner) Run(ctx context.Context) error {
 	// Add user-specified libfuzzer options
 	args = append(args, r.EngineArgs...)
 
-	// Tell libfuzzer which corpus directory it should use
-	args = append(args, r.GeneratedCorpusDir)
+	if len(r.InputFiles) > 0 {
+		// When passed files instead of directories, libfuzzer executes
+		// the fuzz test once with each of them and exits
+		args = append(args, r.InputFiles...)
+	} else {
+		// Tell libfuzzer which corpus directory it should use
+		args = append(args, r.GeneratedCorpusDir)
 
-	// Add any seed corpus directories as further positional arguments
-	args = append(args, r.SeedCorpusDirs...)
+		// Add any seed corpus directories as further positional arguments
+		args = append(args, r.SeedCorpusDirs...)
+	}
 
 	// Set the directory in which fuzzing artifacts (e.g. crashes) are
 	// stored. This must be an absolute path, because else crash files
@@ -153,9 +162,16 @@ func (r *Runner) Run(ctx context.Context) error {
 		bindings := []*minijail.Binding{
 			// The fuzz target must be accessible
 			{Source: r.FuzzTarget},
+		}
+
+		if r.GeneratedCorpusDir != "" {
 			// The first corpus directory must be writable, because
 			// libfuzzer writes new test inputs to it
-			{Source: r.GeneratedCorpusDir, Writable: minijail.ReadWrite},
+			bindings = append(bindings, &minijail.Binding{Source: r.GeneratedCorpusDir, Writable: minijail.ReadWrite})
+		}
+
+		for _, file := range r.InputFiles {
+			bindings = append(bindings, &minijail.Binding{Source: file})
 		}
 
 		for _, dir := range r.ReadOnlyBindings {
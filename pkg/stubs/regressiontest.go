@@ -0,0 +1,184 @@
+package stubs
+
+import (
+	_ "embed"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"text/template"
+	"unicode"
+
+	"github.com/pkg/errors"
+
+	"code-intelligence.com/cifuzz/internal/config"
+	"code-intelligence.com/cifuzz/util/fileutil"
+)
+
+const (
+	TestFrameworkGoogleTest = "gtest"
+	TestFrameworkDoctest    = "doctest"
+)
+
+var ValidTestFrameworks = []string{TestFrameworkGoogleTest, TestFrameworkDoctest}
+
+//go:embed regression-test-gtest.cpp.tmpl
+var cppGoogleTestRegressionTestStub string
+
+//go:embed regression-test-doctest.cpp.tmpl
+var cppDoctestRegressionTestStub string
+
+//go:embed regressionTest.java.tmpl
+var javaRegressionTestStub string
+
+var nonIdentifierChars = regexp.MustCompile(`[^A-Za-z0-9_]`)
+
+// RegressionTestOptions describes the regression test which is created
+// for a finding
+type RegressionTestOptions struct {
+	FindingName string
+	FuzzTest    string
+	Input       []byte
+
+	// The test framework used for C/C++ regression tests, one of
+	// ValidTestFrameworks. Defaults to GoogleTest.
+	Framework string
+
+	// The fully qualified name of the class containing the fuzz test
+	// and the name of the @FuzzTest method, used for Java regression
+	// tests
+	FuzzTestClass  string
+	FuzzTestMethod string
+	// The package and name of the generated JUnit test class
+	PackageName string
+	ClassName   string
+}
+
+// CreateRegressionTest creates a unit test at the given path which
+// executes the fuzz test with the crashing input of a finding. For
+// C/C++, the test calls LLVMFuzzerTestOneInput, for Java, it calls the
+// @FuzzTest method.
+func CreateRegressionTest(path string, testType config.FuzzTestType, opts *RegressionTestOptions) error {
+	exists, err := fileutil.Exists(path)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return errors.WithStack(os.ErrExist)
+	}
+
+	var stub string
+	data := map[string]string{
+		"FindingName": opts.FindingName,
+		"FuzzTest":    opts.FuzzTest,
+		"TestName":    RegressionTestName(opts.FindingName),
+	}
+	switch testType {
+	case config.CPP:
+		switch opts.Framework {
+		case "", TestFrameworkGoogleTest:
+			stub = cppGoogleTestRegressionTestStub
+		case TestFrameworkDoctest:
+			stub = cppDoctestRegressionTestStub
+		default:
+			return errors.Errorf("Unsupported test framework %q, valid frameworks are: %s",
+				opts.Framework, strings.Join(ValidTestFrameworks, ", "))
+		}
+		data["TestSuiteName"] = toCamelCase(opts.FuzzTest) + "Regression"
+		data["InputArray"] = cppByteArray(opts.Input)
+		data["InputSize"] = fmt.Sprint(len(opts.Input))
+	case config.Java:
+		stub = javaRegressionTestStub
+		data["FuzzTestClass"] = opts.FuzzTestClass
+		data["FuzzTestMethod"] = opts.FuzzTestMethod
+		data["PackageName"] = opts.PackageName
+		data["ClassName"] = opts.ClassName
+		data["InputArray"] = javaByteArray(opts.Input)
+	default:
+		return errors.Errorf("Regression tests are not supported for test type %q", testType)
+	}
+
+	tmpl, err := template.New("regression-test").Parse(stub)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	var content strings.Builder
+	err = tmpl.Execute(&content, data)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	err = os.WriteFile(path, []byte(content.String()), 0o644)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
+
+// RegressionTestName returns the name of the test function of the
+// regression test for the given finding
+func RegressionTestName(findingName string) string {
+	return "Finding_" + nonIdentifierChars.ReplaceAllString(findingName, "_")
+}
+
+// RegressionTestClassName returns the name of the JUnit test class of
+// the regression test for the given finding and fuzz test class, e.g.
+// "ParserFuzzTestAdoringOrangutanRegressionTest"
+func RegressionTestClassName(fuzzTestClass, findingName string) string {
+	simpleName := fuzzTestClass[strings.LastIndex(fuzzTestClass, ".")+1:]
+	return toCamelCase(simpleName) + toCamelCase(findingName) + "RegressionTest"
+}
+
+// toCamelCase converts a name like "my_fuzz_test" or "//src:my-test" to
+// an identifier like "MyFuzzTest" or "SrcMyTest"
+func toCamelCase(s string) string {
+	var b strings.Builder
+	upper := true
+	for _, c := range s {
+		if !(unicode.IsLetter(c) || unicode.IsDigit(c)) || c > unicode.MaxASCII {
+			upper = true
+			continue
+		}
+		if b.Len() == 0 && unicode.IsDigit(c) {
+			b.WriteRune('_')
+		}
+		if upper {
+			c = unicode.ToUpper(c)
+			upper = false
+		}
+		b.WriteRune(c)
+	}
+	return b.String()
+}
+
+func cppByteArray(input []byte) string {
+	if len(input) == 0 {
+		// Arrays of size zero are not allowed in C++
+		return "    0x00,\n"
+	}
+	return byteArray(input, func(c byte) string { return fmt.Sprintf("0x%02x", c) }, "    ")
+}
+
+func javaByteArray(input []byte) string {
+	// Java bytes are signed, so values above 127 have to be written as
+	// negative numbers
+	return byteArray(input, func(c byte) string { return fmt.Sprint(int8(c)) }, "        ")
+}
+
+func byteArray(input []byte, format func(c byte) string, indent string) string {
+	const bytesPerLine = 12
+
+	var b strings.Builder
+	for i, c := range input {
+		if i%bytesPerLine == 0 {
+			b.WriteString(indent)
+		}
+		b.WriteString(format(c) + ",")
+		if i%bytesPerLine == bytesPerLine-1 || i == len(input)-1 {
+			b.WriteString("\n")
+		} else {
+			b.WriteString(" ")
+		}
+	}
+	return b.String()
+}
@@ -144,3 +144,68 @@ func TestCreateJavaFileAndClassName(t *testing.T) {
 	assert.NoError(t, err)
 	assert.True(t, strings.Contains(string(testFile), "class "+strings.TrimSuffix(stubName, ".java")))
 }
+
+func TestCreateRegressionTest_CPP(t *testing.T) {
+	projectDir, err := os.MkdirTemp(baseTempDir, "project-")
+	require.NoError(t, err)
+
+	for _, framework := range ValidTestFrameworks {
+		testFile := filepath.Join(projectDir, framework+"_regression_test.cpp")
+		err = CreateRegressionTest(testFile, config.CPP, &RegressionTestOptions{
+			FindingName: "adoring_orangutan",
+			FuzzTest:    "my_fuzz_test",
+			Input:       []byte{'A', 0xff},
+			Framework:   framework,
+		})
+		require.NoError(t, err)
+
+		content, err := os.ReadFile(testFile)
+		require.NoError(t, err)
+		assert.Contains(t, string(content), "    0x41, 0xff,\n")
+		assert.Contains(t, string(content), "kCrashingInputSize = 2;")
+		assert.Contains(t, string(content), "MyFuzzTestRegression")
+		assert.Contains(t, string(content), "Finding_adoring_orangutan")
+	}
+
+	// Empty inputs must still produce a valid array
+	testFile := filepath.Join(projectDir, "empty_regression_test.cpp")
+	err = CreateRegressionTest(testFile, config.CPP, &RegressionTestOptions{FindingName: "empty"})
+	require.NoError(t, err)
+	content, err := os.ReadFile(testFile)
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "    0x00,\n")
+	assert.Contains(t, string(content), "kCrashingInputSize = 0;")
+	assert.Contains(t, string(content), "#include <gtest/gtest.h>")
+
+	err = CreateRegressionTest(testFile, config.CPP, &RegressionTestOptions{FindingName: "empty"})
+	assert.ErrorIs(t, err, os.ErrExist)
+}
+
+func TestCreateRegressionTest_Java(t *testing.T) {
+	projectDir, err := os.MkdirTemp(baseTempDir, "project-")
+	require.NoError(t, err)
+
+	className := RegressionTestClassName("com.example.ParserFuzzTest", "adoring_orangutan")
+	assert.Equal(t, "ParserFuzzTestAdoringOrangutanRegressionTest", className)
+
+	testFile := filepath.Join(projectDir, className+".java")
+	err = CreateRegressionTest(testFile, config.Java, &RegressionTestOptions{
+		FindingName:    "adoring_orangutan",
+		FuzzTest:       "com.example.ParserFuzzTest",
+		Input:          []byte{'A', 0xff},
+		FuzzTestClass:  "com.example.ParserFuzzTest",
+		FuzzTestMethod: "fuzzParser",
+		PackageName:    "com.example",
+		ClassName:      className,
+	})
+	require.NoError(t, err)
+
+	content, err := os.ReadFile(testFile)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(content), "package com.example;\n"))
+	assert.Contains(t, string(content), "class "+className+" {")
+	assert.Contains(t, string(content), "        65, -1,\n")
+	assert.Contains(t, string(content), `Class.forName("com.example.ParserFuzzTest")`)
+	assert.Contains(t, string(content), `method.getName().equals("fuzzParser")`)
+	assert.Contains(t, string(content), "void Finding_adoring_orangutan()")
+}
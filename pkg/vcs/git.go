@@ -1,7 +1,10 @@
 package vcs
 
 import (
+	"fmt"
 	"os/exec"
+	"path/filepath"
+	"regexp"
 	"strings"
 
 	"github.com/pkg/errors"
@@ -9,9 +12,9 @@ import (
 	"code-intelligence.com/cifuzz/pkg/log"
 )
 
-// GitCommit returns the full SHA of the current commit if the working directory is contained in a Git repository.
-func GitCommit() (string, error) {
-	cmd := exec.Command("git", "rev-parse", "HEAD")
+// GitCommit returns the full SHA of the current commit if dir is contained in a Git repository.
+func GitCommit(dir string) (string, error) {
+	cmd := exec.Command("git", "-C", dir, "rev-parse", "HEAD")
 	commit, err := cmd.Output()
 	if err != nil {
 		return "", errors.WithStack(err)
@@ -20,9 +23,9 @@ func GitCommit() (string, error) {
 	return strings.TrimSpace(string(commit)), nil
 }
 
-// GitBranch returns the name of the current branch if the working directory is contained in a Git repository.
-func GitBranch() (string, error) {
-	cmd := exec.Command("git", "rev-parse", "--abbrev-ref", "HEAD")
+// GitBranch returns the name of the current branch if dir is contained in a Git repository.
+func GitBranch(dir string) (string, error) {
+	cmd := exec.Command("git", "-C", dir, "rev-parse", "--abbrev-ref", "HEAD")
 	branch, err := cmd.Output()
 	if err != nil {
 		return "", errors.WithStack(err)
@@ -31,13 +34,147 @@ func GitBranch() (string, error) {
 	return strings.TrimSpace(string(branch)), nil
 }
 
-// GitIsDirty returns true if and only if the current working directory is contained in a Git repository that has
-// uncommitted changes and/or untracked files.
-func GitIsDirty() bool {
-	cmd := exec.Command("git", "status", "--porcelain")
+// GitIsDirty returns true if and only if dir is contained in a Git repository that has uncommitted changes and/or
+// untracked files.
+func GitIsDirty(dir string) bool {
+	cmd := exec.Command("git", "-C", dir, "status", "--porcelain")
 	commit, err := cmd.CombinedOutput()
 	if err != nil {
 		log.Debugf("failed to run git status --porcelain: %+v", err)
 	}
 	return len(strings.TrimSpace(string(commit))) != 0
 }
+
+// GitHasUncommittedChanges returns true if and only if dir is contained in a Git repository that has uncommitted
+// changes to tracked files. Unlike GitIsDirty, it ignores untracked files, which are not affected by checking out
+// other commits.
+func GitHasUncommittedChanges(dir string) bool {
+	cmd := exec.Command("git", "-C", dir, "status", "--porcelain", "--untracked-files=no")
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		log.Debugf("failed to run git status --porcelain --untracked-files=no: %+v", err)
+	}
+	return len(strings.TrimSpace(string(out))) != 0
+}
+
+// BisectResult is the result of testing a commit during git bisect.
+type BisectResult string
+
+const (
+	BisectGood BisectResult = "good"
+	BisectBad  BisectResult = "bad"
+	BisectSkip BisectResult = "skip"
+)
+
+var firstBadCommitPattern = regexp.MustCompile(`(?m)^([0-9a-f]{40}) is the first bad commit`)
+
+// GitBisect uses git bisect in the Git repository containing dir to find the first bad commit between the good and the
+// bad revision. The test function is called for each commit which git checks out and must return whether the commit is
+// good, bad or should be skipped. It returns the full SHA of the first bad commit. Afterwards, the repository is reset
+// to the state before the bisection.
+func GitBisect(dir, good, bad string, test func(commit string) (BisectResult, error)) (firstBadCommit string, err error) {
+	output, err := runGitBisect(dir, "start", bad, good)
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		_, resetErr := runGitBisect(dir, "reset")
+		if err == nil {
+			err = resetErr
+		}
+	}()
+
+	for {
+		if match := firstBadCommitPattern.FindStringSubmatch(output); match != nil {
+			return match[1], nil
+		}
+		if strings.Contains(output, "waiting for good commit") || strings.Contains(output, "waiting for bad commit") {
+			return "", errors.Errorf("git bisect did not start:\n%s", output)
+		}
+		if strings.Contains(output, "only 'skip'ped commits left to test") {
+			return "", errors.Errorf("Could not determine the first bad commit because commits had to be skipped:\n%s",
+				output)
+		}
+
+		commit, err := GitCommit(dir)
+		if err != nil {
+			return "", err
+		}
+		result, err := test(commit)
+		if err != nil {
+			return "", err
+		}
+		log.Debugf("Commit %s is %s", commit, result)
+
+		output, err = runGitBisect(dir, string(result))
+		if err != nil && !strings.Contains(output, "only 'skip'ped commits left to test") {
+			return "", err
+		}
+	}
+}
+
+func runGitBisect(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", append([]string{"-C", dir, "bisect"}, args...)...)
+	output, err := cmd.CombinedOutput()
+	log.Debugf("git bisect %s:\n%s", strings.Join(args, " "), string(output))
+	if err != nil {
+		return string(output), errors.Wrapf(err, "git bisect %s failed:\n%s", strings.Join(args, " "), string(output))
+	}
+	return string(output), nil
+}
+
+// GitRevisionExists returns true if the given revision refers to a commit in the Git repository containing dir.
+func GitRevisionExists(dir, revision string) bool {
+	cmd := exec.Command("git", "-C", dir, "rev-parse", "--verify", "--quiet", revision+"^{commit}")
+	return cmd.Run() == nil
+}
+
+// GitListFiles returns the paths of all files below path in the given revision. Both path and the returned paths are
+// relative to dir.
+func GitListFiles(dir, revision, path string) ([]string, error) {
+	cmd := exec.Command("git", "-C", dir, "ls-tree", "-r", "--name-only", revision, "--", path)
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, errors.Wrapf(err, "git ls-tree %s failed", revision)
+	}
+	var files []string
+	for _, line := range strings.Split(string(output), "\n") {
+		if line != "" {
+			files = append(files, line)
+		}
+	}
+	return files, nil
+}
+
+// GitShowFile returns the content of the file at path in the given revision. The path is relative to dir.
+func GitShowFile(dir, revision, path string) ([]byte, error) {
+	cmd := exec.Command("git", "-C", dir, "show", revision+":./"+filepath.ToSlash(path))
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, errors.Wrapf(err, "git show %s:%s failed", revision, path)
+	}
+	return output, nil
+}
+
+// GitBlameAuthorEmail returns the email address of the author of the last commit which changed the given line of the
+// file. The path is relative to dir. An error is returned if the line was not committed yet.
+func GitBlameAuthorEmail(dir, path string, line uint32) (string, error) {
+	cmd := exec.Command("git", "-C", dir, "blame", "--porcelain", "-L", fmt.Sprintf("%d,%d", line, line), "--", filepath.ToSlash(path))
+	output, err := cmd.Output()
+	if err != nil {
+		return "", errors.Wrapf(err, "git blame %s:%d failed", path, line)
+	}
+	for _, l := range strings.Split(string(output), "\n") {
+		if !strings.HasPrefix(l, "author-mail ") {
+			continue
+		}
+		email := strings.TrimSuffix(strings.TrimPrefix(strings.TrimPrefix(l, "author-mail "), "<"), ">")
+		// Lines which were not committed yet are attributed to this
+		// pseudo author
+		if email == "not.committed.yet" {
+			return "", errors.Errorf("%s:%d was not committed yet", path, line)
+		}
+		return email, nil
+	}
+	return "", errors.Errorf("git blame %s:%d didn't return an author", path, line)
+}
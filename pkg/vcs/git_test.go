@@ -1,31 +1,28 @@
 package vcs_test
 
 import (
+	"fmt"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/require"
 
+	"code-intelligence.com/cifuzz/internal/testutil"
 	"code-intelligence.com/cifuzz/pkg/vcs"
 	"code-intelligence.com/cifuzz/util/fileutil"
 )
 
-// Tests can't run in parallel as they change the current working directory.
-
 func TestGitBranch(t *testing.T) {
 	repo := createGitRepoWithCommits(t)
 	defer os.RemoveAll(repo)
-	err := os.Chdir(repo)
-	require.NoError(t, err)
 
-	branch, err := vcs.GitBranch()
+	branch, err := vcs.GitBranch(repo)
 	require.NoError(t, err)
 	require.Equal(t, "main", branch)
 
-	runGit(t, "", "checkout", "HEAD~")
-	branch, err = vcs.GitBranch()
+	testutil.RunGit(t, repo, "checkout", "HEAD~")
+	branch, err = vcs.GitBranch(repo)
 	require.NoError(t, err)
 	require.Equal(t, "HEAD", branch)
 }
@@ -33,16 +30,14 @@ func TestGitBranch(t *testing.T) {
 func TestGitCommit(t *testing.T) {
 	repo := createGitRepoWithCommits(t)
 	defer os.RemoveAll(repo)
-	err := os.Chdir(repo)
-	require.NoError(t, err)
 
-	commit1, err := vcs.GitCommit()
+	commit1, err := vcs.GitCommit(repo)
 	require.NoError(t, err)
 	// Verify that we obtain a full SHA-1 hash.
 	require.Equalf(t, 40, len(commit1), "Expected full commit SHA, got %q", commit1)
 
-	runGit(t, "", "checkout", "HEAD~")
-	commit2, err := vcs.GitCommit()
+	testutil.RunGit(t, repo, "checkout", "HEAD~")
+	commit2, err := vcs.GitCommit(repo)
 	require.NoError(t, err)
 	require.Equalf(t, 40, len(commit2), "Expected full commit SHA, got %q", commit2)
 
@@ -52,24 +47,112 @@ func TestGitCommit(t *testing.T) {
 func TestGitIsDirty(t *testing.T) {
 	repo := createGitRepoWithCommits(t)
 	defer os.RemoveAll(repo)
-	err := os.Chdir(repo)
-	require.NoError(t, err)
 
-	require.False(t, vcs.GitIsDirty())
+	require.False(t, vcs.GitIsDirty(repo))
 
 	// Verify that modified files trigger a "dirty" state.
-	err = os.WriteFile("empty_file", []byte("changed"), 0644)
+	err := os.WriteFile(filepath.Join(repo, "empty_file"), []byte("changed"), 0644)
 	require.NoError(t, err)
-	require.True(t, vcs.GitIsDirty())
+	require.True(t, vcs.GitIsDirty(repo))
 
 	// Reset modifications.
-	runGit(t, "", "checkout", "--", ".")
-	require.False(t, vcs.GitIsDirty())
+	testutil.RunGit(t, repo, "checkout", "--", ".")
+	require.False(t, vcs.GitIsDirty(repo))
 
 	// Verify that untracked files trigger a "dirty" state.
-	err = fileutil.Touch("third_file")
+	err = fileutil.Touch(filepath.Join(repo, "third_file"))
+	require.NoError(t, err)
+	require.True(t, vcs.GitIsDirty(repo))
+}
+
+func TestGitHasUncommittedChanges(t *testing.T) {
+	repo := createGitRepoWithCommits(t)
+	defer os.RemoveAll(repo)
+
+	require.False(t, vcs.GitHasUncommittedChanges(repo))
+
+	// Verify that untracked files are ignored.
+	err := fileutil.Touch(filepath.Join(repo, "third_file"))
+	require.NoError(t, err)
+	require.False(t, vcs.GitHasUncommittedChanges(repo))
+
+	err = os.WriteFile(filepath.Join(repo, "empty_file"), []byte("changed"), 0644)
+	require.NoError(t, err)
+	require.True(t, vcs.GitHasUncommittedChanges(repo))
+}
+
+func TestGitBisect(t *testing.T) {
+	repo := createGitRepoWithCommits(t)
+	defer os.RemoveAll(repo)
+
+	// Create commits of which the fourth one introduces a bug and the
+	// second one can't be tested
+	var commits []string
+	for i, content := range []string{"ok", "broken build", "still ok", "bug", "still bug"} {
+		err := os.WriteFile(filepath.Join(repo, "state"), []byte(content), 0o644)
+		require.NoError(t, err)
+		testutil.RunGit(t, repo, "add", "state")
+		testutil.RunGit(t, repo, "commit", "-m", fmt.Sprintf("Commit %d", i))
+		commit, err := vcs.GitCommit(repo)
+		require.NoError(t, err)
+		commits = append(commits, commit)
+	}
+
+	firstBadCommit, err := vcs.GitBisect(repo, commits[0], "HEAD", func(commit string) (vcs.BisectResult, error) {
+		content, err := os.ReadFile(filepath.Join(repo, "state"))
+		if err != nil {
+			return "", err
+		}
+		switch string(content) {
+		case "broken build":
+			return vcs.BisectSkip, nil
+		case "bug", "still bug":
+			return vcs.BisectBad, nil
+		default:
+			return vcs.BisectGood, nil
+		}
+	})
+	require.NoError(t, err)
+	require.Equal(t, commits[3], firstBadCommit)
+
+	// The repository is reset to the original state
+	commit, err := vcs.GitCommit(repo)
+	require.NoError(t, err)
+	require.Equal(t, commits[4], commit)
+	branch, err := vcs.GitBranch(repo)
+	require.NoError(t, err)
+	require.Equal(t, "main", branch)
+}
+
+func TestGitShowFile(t *testing.T) {
+	repo := createGitRepoWithCommits(t)
+	defer os.RemoveAll(repo)
+
+	err := os.MkdirAll(filepath.Join(repo, "dir", "sub"), 0o755)
+	require.NoError(t, err)
+	err = os.WriteFile(filepath.Join(repo, "dir", "sub", "file"), []byte("old"), 0o644)
 	require.NoError(t, err)
-	require.True(t, vcs.GitIsDirty())
+	testutil.RunGit(t, repo, "add", "dir")
+	testutil.RunGit(t, repo, "commit", "-m", "Add file")
+	err = os.WriteFile(filepath.Join(repo, "dir", "sub", "file"), []byte("new"), 0o644)
+	require.NoError(t, err)
+	testutil.RunGit(t, repo, "commit", "-am", "Change file")
+
+	require.True(t, vcs.GitRevisionExists(repo, "HEAD~"))
+	require.False(t, vcs.GitRevisionExists(repo, "no-such-revision"))
+
+	// Paths are relative to the specified directory
+	dir := filepath.Join(repo, "dir")
+	files, err := vcs.GitListFiles(dir, "HEAD~", "sub")
+	require.NoError(t, err)
+	require.Equal(t, []string{"sub/file"}, files)
+
+	content, err := vcs.GitShowFile(dir, "HEAD~", filepath.Join("sub", "file"))
+	require.NoError(t, err)
+	require.Equal(t, "old", string(content))
+
+	_, err = vcs.GitShowFile(dir, "HEAD~", "no-such-file")
+	require.Error(t, err)
 }
 
 func createGitRepoWithCommits(t *testing.T) string {
@@ -78,31 +161,20 @@ func createGitRepoWithCommits(t *testing.T) string {
 	repo, err := os.MkdirTemp("", "git-test-*")
 	require.NoError(t, err)
 
-	runGit(t, repo, "init")
-	runGit(t, repo, "config", "user.email", "you@example.com")
-	runGit(t, repo, "config", "user.name", "Your Name")
+	testutil.InitGitRepo(t, repo)
 
 	// Ensure that the main branch is called "main" even with older Git versions.
-	runGit(t, repo, "branch", "-M", "main")
+	testutil.RunGit(t, repo, "branch", "-M", "main")
 
 	err = fileutil.Touch(filepath.Join(repo, "empty_file"))
 	require.NoError(t, err)
-	runGit(t, repo, "add", "empty_file")
-	runGit(t, repo, "commit", "-m", "Initial commit")
+	testutil.RunGit(t, repo, "add", "empty_file")
+	testutil.RunGit(t, repo, "commit", "-m", "Initial commit")
 
 	err = fileutil.Touch(filepath.Join(repo, "other_file"))
 	require.NoError(t, err)
-	runGit(t, repo, "add", "other_file")
-	runGit(t, repo, "commit", "-m", "Second commit")
+	testutil.RunGit(t, repo, "add", "other_file")
+	testutil.RunGit(t, repo, "commit", "-m", "Second commit")
 
 	return repo
 }
-
-func runGit(t *testing.T, repo string, args ...string) {
-	cmd := exec.Command("git", args...)
-	cmd.Dir = repo
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-	err := cmd.Run()
-	require.NoError(t, err)
-}
@@ -0,0 +1,294 @@
+package com.code_intelligence.cifuzz;
+
+import com.code_intelligence.jazzer.api.HookType;
+import com.code_intelligence.jazzer.api.MethodHook;
+import java.io.FileOutputStream;
+import java.io.IOException;
+import java.io.OutputStreamWriter;
+import java.io.PrintWriter;
+import java.lang.invoke.MethodHandle;
+import java.lang.reflect.Array;
+import java.nio.charset.StandardCharsets;
+import java.util.Collection;
+
+/**
+ * Logs each call of the public methods of Jazzer's FuzzedDataProvider
+ * and the returned value to the file specified via the environment
+ * variable CIFUZZ_DATA_PROVIDER_LOG, one call per line, e.g.
+ *
+ * <pre>
+ * consumeInt(0, 10) = 7
+ * consumeString(20) = "ab"
+ * </pre>
+ *
+ * The format matches the one of the C/C++ FuzzedDataProvider shipped
+ * with cifuzz (include/fuzzer/FuzzedDataProvider.h). The hooks are used
+ * by {@code cifuzz finding show --decode} and passed to Jazzer via
+ * --custom_hooks. Jazzer hooks the call sites in the instrumented
+ * classes, so calls made by the data provider itself (e.g. pickValue
+ * calling consumeInt) are not logged.
+ */
+public final class DataProviderLoggingHooks {
+  private static final String FDP = "com.code_intelligence.jazzer.api.FuzzedDataProvider";
+  private static final char[] HEX_DIGITS = "0123456789abcdef".toCharArray();
+  // The environment variable is only checked once per process, because
+  // the hooks are called for every input while fuzzing
+  private static final PrintWriter LOG = openLog();
+
+  private DataProviderLoggingHooks() {}
+
+  @MethodHook(type = HookType.AFTER, targetClassName = FDP, targetMethod = "consumeBoolean")
+  public static void consumeBoolean(
+      MethodHandle method, Object thisObject, Object[] arguments, int hookId, Object returnValue) {
+    logCall("consumeBoolean", arguments, returnValue);
+  }
+
+  @MethodHook(type = HookType.AFTER, targetClassName = FDP, targetMethod = "consumeBooleans")
+  public static void consumeBooleans(
+      MethodHandle method, Object thisObject, Object[] arguments, int hookId, Object returnValue) {
+    logCall("consumeBooleans", arguments, returnValue);
+  }
+
+  @MethodHook(type = HookType.AFTER, targetClassName = FDP, targetMethod = "consumeByte")
+  public static void consumeByte(
+      MethodHandle method, Object thisObject, Object[] arguments, int hookId, Object returnValue) {
+    logCall("consumeByte", arguments, returnValue);
+  }
+
+  @MethodHook(type = HookType.AFTER, targetClassName = FDP, targetMethod = "consumeBytes")
+  public static void consumeBytes(
+      MethodHandle method, Object thisObject, Object[] arguments, int hookId, Object returnValue) {
+    logCall("consumeBytes", arguments, returnValue);
+  }
+
+  @MethodHook(type = HookType.AFTER, targetClassName = FDP, targetMethod = "consumeRemainingAsBytes")
+  public static void consumeRemainingAsBytes(
+      MethodHandle method, Object thisObject, Object[] arguments, int hookId, Object returnValue) {
+    logCall("consumeRemainingAsBytes", arguments, returnValue);
+  }
+
+  @MethodHook(type = HookType.AFTER, targetClassName = FDP, targetMethod = "consumeShort")
+  public static void consumeShort(
+      MethodHandle method, Object thisObject, Object[] arguments, int hookId, Object returnValue) {
+    logCall("consumeShort", arguments, returnValue);
+  }
+
+  @MethodHook(type = HookType.AFTER, targetClassName = FDP, targetMethod = "consumeShorts")
+  public static void consumeShorts(
+      MethodHandle method, Object thisObject, Object[] arguments, int hookId, Object returnValue) {
+    logCall("consumeShorts", arguments, returnValue);
+  }
+
+  @MethodHook(type = HookType.AFTER, targetClassName = FDP, targetMethod = "consumeInt")
+  public static void consumeInt(
+      MethodHandle method, Object thisObject, Object[] arguments, int hookId, Object returnValue) {
+    logCall("consumeInt", arguments, returnValue);
+  }
+
+  @MethodHook(type = HookType.AFTER, targetClassName = FDP, targetMethod = "consumeInts")
+  public static void consumeInts(
+      MethodHandle method, Object thisObject, Object[] arguments, int hookId, Object returnValue) {
+    logCall("consumeInts", arguments, returnValue);
+  }
+
+  @MethodHook(type = HookType.AFTER, targetClassName = FDP, targetMethod = "consumeLong")
+  public static void consumeLong(
+      MethodHandle method, Object thisObject, Object[] arguments, int hookId, Object returnValue) {
+    logCall("consumeLong", arguments, returnValue);
+  }
+
+  @MethodHook(type = HookType.AFTER, targetClassName = FDP, targetMethod = "consumeLongs")
+  public static void consumeLongs(
+      MethodHandle method, Object thisObject, Object[] arguments, int hookId, Object returnValue) {
+    logCall("consumeLongs", arguments, returnValue);
+  }
+
+  @MethodHook(type = HookType.AFTER, targetClassName = FDP, targetMethod = "consumeFloat")
+  public static void consumeFloat(
+      MethodHandle method, Object thisObject, Object[] arguments, int hookId, Object returnValue) {
+    logCall("consumeFloat", arguments, returnValue);
+  }
+
+  @MethodHook(type = HookType.AFTER, targetClassName = FDP, targetMethod = "consumeRegularFloat")
+  public static void consumeRegularFloat(
+      MethodHandle method, Object thisObject, Object[] arguments, int hookId, Object returnValue) {
+    logCall("consumeRegularFloat", arguments, returnValue);
+  }
+
+  @MethodHook(type = HookType.AFTER, targetClassName = FDP, targetMethod = "consumeProbabilityFloat")
+  public static void consumeProbabilityFloat(
+      MethodHandle method, Object thisObject, Object[] arguments, int hookId, Object returnValue) {
+    logCall("consumeProbabilityFloat", arguments, returnValue);
+  }
+
+  @MethodHook(type = HookType.AFTER, targetClassName = FDP, targetMethod = "consumeDouble")
+  public static void consumeDouble(
+      MethodHandle method, Object thisObject, Object[] arguments, int hookId, Object returnValue) {
+    logCall("consumeDouble", arguments, returnValue);
+  }
+
+  @MethodHook(type = HookType.AFTER, targetClassName = FDP, targetMethod = "consumeRegularDouble")
+  public static void consumeRegularDouble(
+      MethodHandle method, Object thisObject, Object[] arguments, int hookId, Object returnValue) {
+    logCall("consumeRegularDouble", arguments, returnValue);
+  }
+
+  @MethodHook(type = HookType.AFTER, targetClassName = FDP, targetMethod = "consumeProbabilityDouble")
+  public static void consumeProbabilityDouble(
+      MethodHandle method, Object thisObject, Object[] arguments, int hookId, Object returnValue) {
+    logCall("consumeProbabilityDouble", arguments, returnValue);
+  }
+
+  @MethodHook(type = HookType.AFTER, targetClassName = FDP, targetMethod = "consumeChar")
+  public static void consumeChar(
+      MethodHandle method, Object thisObject, Object[] arguments, int hookId, Object returnValue) {
+    logCall("consumeChar", arguments, returnValue);
+  }
+
+  @MethodHook(type = HookType.AFTER, targetClassName = FDP, targetMethod = "consumeCharNoSurrogates")
+  public static void consumeCharNoSurrogates(
+      MethodHandle method, Object thisObject, Object[] arguments, int hookId, Object returnValue) {
+    logCall("consumeCharNoSurrogates", arguments, returnValue);
+  }
+
+  @MethodHook(type = HookType.AFTER, targetClassName = FDP, targetMethod = "consumeString")
+  public static void consumeString(
+      MethodHandle method, Object thisObject, Object[] arguments, int hookId, Object returnValue) {
+    logCall("consumeString", arguments, returnValue);
+  }
+
+  @MethodHook(type = HookType.AFTER, targetClassName = FDP, targetMethod = "consumeRemainingAsString")
+  public static void consumeRemainingAsString(
+      MethodHandle method, Object thisObject, Object[] arguments, int hookId, Object returnValue) {
+    logCall("consumeRemainingAsString", arguments, returnValue);
+  }
+
+  @MethodHook(type = HookType.AFTER, targetClassName = FDP, targetMethod = "consumeAsciiString")
+  public static void consumeAsciiString(
+      MethodHandle method, Object thisObject, Object[] arguments, int hookId, Object returnValue) {
+    logCall("consumeAsciiString", arguments, returnValue);
+  }
+
+  @MethodHook(type = HookType.AFTER, targetClassName = FDP, targetMethod = "consumeRemainingAsAsciiString")
+  public static void consumeRemainingAsAsciiString(
+      MethodHandle method, Object thisObject, Object[] arguments, int hookId, Object returnValue) {
+    logCall("consumeRemainingAsAsciiString", arguments, returnValue);
+  }
+
+  @MethodHook(type = HookType.AFTER, targetClassName = FDP, targetMethod = "pickValue")
+  public static void pickValue(
+      MethodHandle method, Object thisObject, Object[] arguments, int hookId, Object returnValue) {
+    logCall("pickValue", arguments, returnValue);
+  }
+
+  @MethodHook(type = HookType.AFTER, targetClassName = FDP, targetMethod = "pickValues")
+  public static void pickValues(
+      MethodHandle method, Object thisObject, Object[] arguments, int hookId, Object returnValue) {
+    logCall("pickValues", arguments, returnValue);
+  }
+
+  private static PrintWriter openLog() {
+    String path = System.getenv("CIFUZZ_DATA_PROVIDER_LOG");
+    if (path == null || path.isEmpty()) {
+      return null;
+    }
+    try {
+      return new PrintWriter(
+          new OutputStreamWriter(new FileOutputStream(path, true), StandardCharsets.UTF_8));
+    } catch (IOException e) {
+      System.err.printf("cifuzz: failed to open data provider log %s: %s%n", path, e);
+      return null;
+    }
+  }
+
+  private static void logCall(String name, Object[] arguments, Object returnValue) {
+    if (LOG == null) {
+      return;
+    }
+    StringBuilder call = new StringBuilder(name).append('(');
+    for (int i = 0; i < arguments.length; i++) {
+      if (i > 0) {
+        call.append(", ");
+      }
+      // The values to pick from are logged as their number only, like
+      // PickValueInArray of the C/C++ FuzzedDataProvider does
+      if (i == 0 && name.startsWith("pick")) {
+        call.append(size(arguments[i])).append(" values");
+      } else {
+        call.append(formatValue(arguments[i]));
+      }
+    }
+    call.append(") = ").append(formatValue(returnValue));
+    synchronized (LOG) {
+      LOG.println(call);
+      // Flush, so that the call is logged even if the fuzz test crashes
+      LOG.flush();
+    }
+  }
+
+  private static int size(Object values) {
+    if (values instanceof Collection) {
+      return ((Collection<?>) values).size();
+    }
+    if (values != null && values.getClass().isArray()) {
+      return Array.getLength(values);
+    }
+    return 0;
+  }
+
+  private static String formatValue(Object value) {
+    if (value instanceof byte[]) {
+      return formatBytes((byte[]) value);
+    }
+    if (value instanceof String || value instanceof Character) {
+      return formatBytes(value.toString().getBytes(StandardCharsets.UTF_8));
+    }
+    if (value != null && value.getClass().isArray()) {
+      StringBuilder result = new StringBuilder("[");
+      for (int i = 0; i < Array.getLength(value); i++) {
+        if (i > 0) {
+          result.append(", ");
+        }
+        result.append(formatValue(Array.get(value, i)));
+      }
+      return result.append(']').toString();
+    }
+    if (value instanceof Collection) {
+      return formatValue(((Collection<?>) value).toArray());
+    }
+    return String.valueOf(value);
+  }
+
+  // Formats the bytes as a quoted string with C escape sequences, so
+  // that each call is logged on a single line
+  private static String formatBytes(byte[] bytes) {
+    StringBuilder result = new StringBuilder("\"");
+    for (byte b : bytes) {
+      int c = b & 0xff;
+      switch (c) {
+        case '"':
+          result.append("\\\"");
+          break;
+        case '\\':
+          result.append("\\\\");
+          break;
+        case '\n':
+          result.append("\\n");
+          break;
+        case '\r':
+          result.append("\\r");
+          break;
+        case '\t':
+          result.append("\\t");
+          break;
+        default:
+          if (c >= 0x20 && c < 0x7f) {
+            result.append((char) c);
+          } else {
+            result.append("\\x").append(HEX_DIGITS[c >> 4]).append(HEX_DIGITS[c & 0xf]);
+          }
+      }
+    }
+    return result.append('"').toString();
+  }
+}